@@ -45,6 +45,7 @@ type ImageBuilder struct {
 	Volumes        map[string]struct{}
 	OnBuild        []string
 	User           string
+	Healthcheck    *docker.HealthConfig
 	HasData        bool
 	TarData        bool
 }
@@ -207,6 +208,7 @@ func NewImageBuilder(
 		Volumes:        imageInfo.Config.Volumes,
 		OnBuild:        imageInfo.Config.OnBuild,
 		User:           imageInfo.Config.User,
+		Healthcheck:    imageInfo.Config.Healthcheck,
 	}
 
 	if builder.ExposedPorts == nil {
@@ -336,6 +338,10 @@ func NewImageBuilder(
 
 			builder.Env = newEnv
 		}
+
+		if instructions.RemoveHealthcheck {
+			builder.Healthcheck = nil
+		}
 	}
 
 	if sourceImage != "" {
@@ -413,6 +419,7 @@ func (b *ImageBuilder) GenerateDockerfile() error {
 		b.ExposedPorts,
 		b.Entrypoint,
 		b.Cmd,
+		b.Healthcheck,
 		b.HasData,
 		b.TarData)
 }
@@ -51,6 +51,8 @@ var CLI = &cli.Command{
 		command.Cflag(command.FlagContainerProbeComposeSvc),
 		command.Cflag(command.FlagHostExec),
 		command.Cflag(command.FlagHostExecFile),
+		command.Cflag(command.FlagTCPProbe),
+		command.Cflag(command.FlagExecProbe),
 
 		command.Cflag(command.FlagTargetKubeWorkload),
 		command.Cflag(command.FlagTargetKubeWorkloadNamespace),
@@ -118,6 +120,7 @@ var CLI = &cli.Command{
 		cflag(FlagRemoveEnv),
 		cflag(FlagRemoveLabel),
 		cflag(FlagRemoveVolume),
+		cflag(FlagRemoveHealthcheck),
 		cflag(FlagPreservePath),
 		cflag(FlagPreservePathFile),
 		cflag(FlagIncludePath),
@@ -316,6 +319,8 @@ var CLI = &cli.Command{
 		doPublishExposedPorts := ctx.Bool(command.FlagPublishExposedPorts)
 
 		httpProbeOpts := command.GetHTTPProbeOptions(xc, ctx, false)
+		tcpProbeOpts := command.GetTCPProbeOptions(xc, ctx)
+		execProbeOpts := command.GetExecProbeOptions(xc, ctx)
 
 		continueAfter, err := command.GetContinueAfter(ctx)
 		if err != nil {
@@ -327,7 +332,7 @@ var CLI = &cli.Command{
 			xc.Exit(-1)
 		}
 
-		if continueAfter.Mode == config.CAMProbe && !httpProbeOpts.Do {
+		if continueAfter.Mode == config.CAMProbe && !httpProbeOpts.Do && len(tcpProbeOpts.Specs) == 0 && len(execProbeOpts.Cmds) == 0 {
 			continueAfter.Mode = ""
 			xc.Out.Info("exec",
 				ovars{
@@ -786,6 +791,8 @@ var CLI = &cli.Command{
 			crOpts,
 			outputTags,
 			httpProbeOpts,
+			tcpProbeOpts,
+			execProbeOpts,
 			portBindings,
 			doPublishExposedPorts,
 			hostExecProbes,
@@ -133,6 +133,8 @@ const (
 	FlagRemoveEnv     = "remove-env"
 	FlagRemoveLabel   = "remove-label"
 
+	FlagRemoveHealthcheck = "remove-healthcheck"
+
 	FlagTag = "tag"
 
 	FlagImageOverrides = "image-overrides"
@@ -211,6 +213,8 @@ const (
 	FlagRemoveLabelUsage   = "Remove LABEL instructions for the optimized image"
 	FlagRemoveVolumeUsage  = "Remove VOLUME instructions for the optimized image"
 
+	FlagRemoveHealthcheckUsage = "Remove the HEALTHCHECK instruction for the optimized image (it's preserved from the source image by default)"
+
 	FlagTagUsage = "Custom tags for the generated image"
 
 	FlagImageOverridesUsage = "Save runtime overrides in generated image (values is 'all' or a comma delimited list of override types: 'entrypoint', 'cmd', 'workdir', 'env', 'expose', 'volume', 'label')"
@@ -621,6 +625,11 @@ var Flags = map[string]cli.Flag{
 		Usage:   FlagRemoveVolumeUsage,
 		EnvVars: []string{"DSLIM_RM_VOLUME"},
 	},
+	FlagRemoveHealthcheck: &cli.BoolFlag{
+		Name:    FlagRemoveHealthcheck,
+		Usage:   FlagRemoveHealthcheckUsage,
+		EnvVars: []string{"DSLIM_RM_HEALTHCHECK"},
+	},
 	FlagIncludeBinFile: &cli.StringFlag{
 		Name:    FlagIncludeBinFile,
 		Value:   "",
@@ -737,8 +746,9 @@ func GetImageInstructions(ctx *cli.Context) (*config.ImageNewInstructions, error
 	removeExpose := ctx.StringSlice(FlagRemoveExpose)
 
 	instructions := &config.ImageNewInstructions{
-		Workdir: ctx.String(FlagNewWorkdir),
-		Env:     ctx.StringSlice(FlagNewEnv),
+		Workdir:           ctx.String(FlagNewWorkdir),
+		Env:               ctx.StringSlice(FlagNewEnv),
+		RemoveHealthcheck: ctx.Bool(FlagRemoveHealthcheck),
 	}
 
 	volumes, err := command.ParseTokenSet(ctx.StringSlice(FlagNewVolume))
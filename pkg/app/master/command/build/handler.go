@@ -21,7 +21,10 @@ import (
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/image"
 	"github.com/slimtoolkit/slim/pkg/app/master/kubernetes"
+	probes "github.com/slimtoolkit/slim/pkg/app/master/probe"
+	execprobe "github.com/slimtoolkit/slim/pkg/app/master/probe/exec"
 	"github.com/slimtoolkit/slim/pkg/app/master/probe/http"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe/tcp"
 	"github.com/slimtoolkit/slim/pkg/app/master/version"
 	cmd "github.com/slimtoolkit/slim/pkg/command"
 	"github.com/slimtoolkit/slim/pkg/consts"
@@ -93,6 +96,8 @@ func OnCommand(
 	outputTags []string,
 
 	httpProbeOpts config.HTTPProbeOptions,
+	tcpProbeOpts config.TCPProbeOptions,
+	execProbeOpts config.ExecProbeOptions,
 
 	portBindings map[dockerapi.Port][]dockerapi.PortBinding,
 	doPublishExposedPorts bool,
@@ -1173,6 +1178,8 @@ func OnCommand(
 		execCmd,
 		execFileCmd,
 		httpProbeOpts,
+		tcpProbeOpts,
+		execProbeOpts,
 		hostExecProbes,
 		depServicesExe,
 		containerProbeComposeSvc,
@@ -1266,6 +1273,8 @@ func monitorContainer(
 	execCmd string,
 	execFileCmd string,
 	httpProbeOpts config.HTTPProbeOptions,
+	tcpProbeOpts config.TCPProbeOptions,
+	execProbeOpts config.ExecProbeOptions,
 	hostExecProbes []string,
 	depServicesExe *compose.Execution,
 	containerProbeComposeSvc string,
@@ -1274,7 +1283,7 @@ func monitorContainer(
 	cmdReport *report.BuildCommand,
 	printState bool,
 ) {
-	if hasContinueAfterMode(continueAfter.Mode, config.CAMProbe) {
+	if hasContinueAfterMode(continueAfter.Mode, config.CAMProbe) && len(tcpProbeOpts.Specs) == 0 {
 		httpProbeOpts.Do = true
 	}
 
@@ -1306,10 +1315,48 @@ func monitorContainer(
 			xc.Exit(exitCode)
 		}
 
-		probe.Start()
+		//interrupting the command cancels the probe (the probe outputs are still saved)
+		probe.StartContext(command.ProbeInterruptContext(probe.DoneChan()))
 		continueAfter.ContinueChan = probe.DoneChan()
 	}
 
+	//the TCP and exec probes run next to the HTTP probe
+	var targetProbes []probes.Probe
+	if len(tcpProbeOpts.Specs) > 0 {
+		tcpProbe, err := tcp.NewContainerProbe(xc, containerInspector, tcpProbeOpts, printState)
+		xc.FailOn(err)
+
+		if len(tcpProbe.Ports()) == 0 {
+			xc.Out.State("tcp.probe.error",
+				ovars{
+					"error":   "NO EXPOSED PORTS",
+					"message": "expose the TCP probe ports with --expose or remove the --tcp-probe flags",
+				})
+
+			exitCode := command.ECTBuild | ecbImageBuildError
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": exitCode,
+				})
+
+			cmdReport.Error = "no.exposed.ports"
+			xc.Exit(exitCode)
+		}
+
+		targetProbes = append(targetProbes, tcpProbe)
+	}
+
+	if len(execProbeOpts.Cmds) > 0 {
+		execProbe, err := execprobe.NewContainerProbe(xc, containerInspector, execProbeOpts, printState)
+		xc.FailOn(err)
+
+		targetProbes = append(targetProbes, execProbe)
+	}
+
+	if len(targetProbes) > 0 {
+		continueAfter.ContinueChan = command.ProbesDoneChan(continueAfter.ContinueChan, probes.StartAll(targetProbes...))
+	}
+
 	continueAfterMsg := "provide the expected input to allow the container inspector to continue its execution"
 	if continueAfter.Mode == config.CAMTimeout {
 		continueAfterMsg = "no input required, execution will resume after the timeout"
@@ -1460,8 +1507,16 @@ func monitorContainer(
 					"message": "HTTP probe is done",
 				})
 
-			if probe != nil && probe.CallCount > 0 && probe.OkCount == 0 && httpProbeOpts.ExitOnFailure {
-				xc.Out.Error("probe.error", "no.successful.calls")
+			if probe != nil && probe.Error() != nil {
+				xc.Out.Error("probe.error", probe.Error().Error())
+
+				containerInspector.ShowContainerLogs()
+				xc.Out.State("exited", ovars{"exit.code": -1})
+				xc.Exit(-1)
+			}
+
+			if probe != nil && probe.ResultError() != "" {
+				xc.Out.Error("probe.error", probe.ResultError())
 
 				containerInspector.ShowContainerLogs()
 				xc.Out.State("exited", ovars{"exit.code": -1})
@@ -566,6 +566,10 @@ func UpdateBuildOptionsWithNewInstructions(
 
 			options.ImageConfig.Config.Env = newEnv
 		}
+
+		if instructions.RemoveHealthcheck {
+			options.ImageConfig.Config.Healthcheck = nil
+		}
 	}
 }
 
@@ -640,6 +644,16 @@ func UpdateBuildOptionsWithSrcImageInfo(
 	options.ImageConfig.Config.OnBuild = imageInfo.Config.OnBuild
 	options.ImageConfig.Config.StopSignal = imageInfo.Config.StopSignal
 
+	if hc := imageInfo.Config.Healthcheck; hc != nil && len(hc.Test) > 0 {
+		options.ImageConfig.Config.Healthcheck = &imagebuilder.HealthConfig{
+			Test:        hc.Test,
+			Interval:    hc.Interval,
+			Timeout:     hc.Timeout,
+			StartPeriod: hc.StartPeriod,
+			Retries:     hc.Retries,
+		}
+	}
+
 	for k, v := range imageInfo.Config.ExposedPorts {
 		options.ImageConfig.Config.ExposedPorts[string(k)] = v
 	}
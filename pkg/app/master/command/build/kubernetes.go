@@ -327,7 +327,8 @@ func (h *kubeHandler) monitorPod(
 			h.Exit(exitCode)
 		}
 
-		probe.Start()
+		//interrupting the command cancels the probe (the probe outputs are still saved)
+		probe.StartContext(command.ProbeInterruptContext(probe.DoneChan()))
 		opts.continueAfter.ContinueChan = probe.DoneChan()
 	}
 
@@ -378,8 +379,16 @@ func (h *kubeHandler) monitorPod(
 			<-opts.continueAfter.ContinueChan
 			h.Out.Info("event", ovars{"message": "HTTP probe is done"})
 
-			if probe.CallCount > 0 && probe.OkCount == 0 && opts.httpProbeOpts.ExitOnFailure {
-				h.Out.Error("probe.error", "no.successful.calls")
+			if probe != nil && probe.Error() != nil {
+				h.Out.Error("probe.error", probe.Error().Error())
+
+				podInspector.ShowPodLogs()
+				h.Out.State("exited", ovars{"exit.code": -1})
+				h.Exit(-1)
+			}
+
+			if probe != nil && probe.ResultError() != "" {
+				h.Out.Error("probe.error", probe.ResultError())
 
 				podInspector.ShowPodLogs()
 				h.Out.State("exited", ovars{"exit.code": -1})
@@ -105,29 +105,88 @@ const (
 	FlagRemoveFileArtifacts = "remove-file-artifacts"
 	FlagCopyMetaArtifacts   = "copy-meta-artifacts"
 
-	FlagHTTPProbe                 = "http-probe"
-	FlagHTTPProbeOff              = "http-probe-off" //alternative way to disable http probing
-	FlagHTTPProbeCmd              = "http-probe-cmd"
-	FlagHTTPProbeCmdFile          = "http-probe-cmd-file"
-	FlagHTTPProbeStartWait        = "http-probe-start-wait"
-	FlagHTTPProbeRetryCount       = "http-probe-retry-count"
-	FlagHTTPProbeRetryWait        = "http-probe-retry-wait"
-	FlagHTTPProbePorts            = "http-probe-ports"
-	FlagHTTPProbeFull             = "http-probe-full"
-	FlagHTTPProbeExitOnFailure    = "http-probe-exit-on-failure"
-	FlagHTTPProbeCrawl            = "http-probe-crawl"
-	FlagHTTPCrawlMaxDepth         = "http-crawl-max-depth"
-	FlagHTTPCrawlMaxPageCount     = "http-crawl-max-page-count"
-	FlagHTTPCrawlConcurrency      = "http-crawl-concurrency"
-	FlagHTTPMaxConcurrentCrawlers = "http-max-concurrent-crawlers"
-	FlagHTTPProbeAPISpec          = "http-probe-apispec"
-	FlagHTTPProbeAPISpecFile      = "http-probe-apispec-file"
-	FlagHTTPProbeProxyEndpoint    = "http-probe-proxy-endpoint"
-	FlagHTTPProbeProxyPort        = "http-probe-proxy-port"
+	FlagHTTPProbe                       = "http-probe"
+	FlagHTTPProbeOff                    = "http-probe-off" //alternative way to disable http probing
+	FlagHTTPProbeCmd                    = "http-probe-cmd"
+	FlagHTTPProbeCmdFile                = "http-probe-cmd-file"
+	FlagHTTPProbeStartWait              = "http-probe-start-wait"
+	FlagHTTPProbeRetryCount             = "http-probe-retry-count"
+	FlagHTTPProbeRetryWait              = "http-probe-retry-wait"
+	FlagHTTPProbePorts                  = "http-probe-ports"
+	FlagHTTPProbeFull                   = "http-probe-full"
+	FlagHTTPProbeExitOnFailure          = "http-probe-exit-on-failure"
+	FlagHTTPProbeCrawl                  = "http-probe-crawl"
+	FlagHTTPCrawlMaxDepth               = "http-crawl-max-depth"
+	FlagHTTPCrawlMaxPageCount           = "http-crawl-max-page-count"
+	FlagHTTPCrawlConcurrency            = "http-crawl-concurrency"
+	FlagHTTPMaxConcurrentCrawlers       = "http-max-concurrent-crawlers"
+	FlagHTTPProbeAPISpec                = "http-probe-apispec"
+	FlagHTTPProbeAPISpecFile            = "http-probe-apispec-file"
+	FlagHTTPProbeProxyEndpoint          = "http-probe-proxy-endpoint"
+	FlagHTTPProbeProxyPort              = "http-probe-proxy-port"
+	FlagHTTPProbeDNSServer              = "http-probe-dns-server"
+	FlagHTTPProbeCSVOutput              = "http-probe-csv-output"
+	FlagHTTPProbeCassette               = "http-probe-cassette"
+	FlagHTTPProbeCassetteMode           = "http-probe-cassette-mode"
+	FlagHTTPProbeRetryBackoffReset      = "http-probe-retry-backoff-reset"
+	FlagHTTPProbeAllAddresses           = "http-probe-all-addresses"
+	FlagHTTPProbeRoutesEndpoint         = "http-probe-routes-endpoint"
+	FlagHTTPProbeRoutesDestructive      = "http-probe-routes-destructive"
+	FlagHTTPProbeRequestID              = "http-probe-request-id"
+	FlagHTTPProbeCPUThrottle            = "http-probe-cpu-throttle"
+	FlagHTTPProbeCPUThrottleThreshold   = "http-probe-cpu-throttle-threshold"
+	FlagHTTPProbeRetryOn                = "http-probe-retry-on"
+	FlagHTTPProbeConcurrency            = "http-probe-concurrency"
+	FlagHTTPProbePcapOutput             = "http-probe-pcap-output"
+	FlagHTTPProbeDeadline               = "http-probe-deadline"
+	FlagHTTPProbeRetryBudget            = "http-probe-retry-budget"
+	FlagHTTPProbeFailOnDuplicates       = "http-probe-fail-on-duplicates"
+	FlagHTTPProbeMetricsInterval        = "http-probe-metrics-interval"
+	FlagHTTPProbeRequireExplicitPorts   = "http-probe-require-explicit-ports"
+	FlagHTTPProbeEventLog               = "http-probe-event-log"
+	FlagHTTPProbeEventLogMaxMessages    = "http-probe-event-log-max-messages"
+	FlagHTTPProbeCredentialsFile        = "http-probe-credentials-file"
+	FlagHTTPProbeTLSServerName          = "http-probe-tls-server-name"
+	FlagHTTPProbeDataSeed               = "http-probe-data-seed"
+	FlagHTTPProbeScreenshotOnFailure    = "http-probe-screenshot-on-failure"
+	FlagHTTPProbeScreenshotBrowser      = "http-probe-screenshot-browser"
+	FlagHTTPProbeSeedExec               = "http-probe-seed-exec"
+	FlagHTTPProbeSeedEndpoint           = "http-probe-seed-endpoint"
+	FlagHTTPProbeSeverityRule           = "http-probe-severity-rule"
+	FlagHTTPProbeMaxResponseHeaderBytes = "http-probe-max-response-header-bytes"
+	FlagHTTPProbeResultAssert           = "http-probe-result-assert"
+	FlagHTTPProbePortReadyTimeout       = "http-probe-port-ready-timeout"
+	FlagHTTPProbeExpectStatus           = "http-probe-expect-status"
+	FlagHTTPProbeAPISpecCmds            = "http-probe-apispec-cmds"
+	FlagHTTPProbeReport                 = "http-probe-report"
+	FlagHTTPProbeRetryMaxWait           = "http-probe-retry-max-wait"
+	FlagHTTPProbeMaxRedirects           = "http-probe-max-redirects"
+	FlagHTTPProbeNoCookieJar            = "http-probe-no-cookie-jar"
+	FlagHTTPProbeCallTimeout            = "http-probe-call-timeout"
+	FlagHTTPProbeConnectTimeout         = "http-probe-connect-timeout"
+	FlagHTTPProbeProxy                  = "http-probe-proxy"
+	FlagHTTPProbeHost                   = "http-probe-host"
+	FlagHTTPProbeUseHealthcheck         = "http-probe-use-healthcheck"
+	FlagHTTPProbeVerifyTLS              = "http-probe-verify-tls"
+	FlagHTTPProbeCACert                 = "http-probe-ca-cert"
+	FlagHTTPProbeRepeat                 = "http-probe-repeat"
+	FlagHTTPProbeDryRun                 = "http-probe-dry-run"
+	FlagHTTPProbeVerbose                = "http-probe-verbose"
+	FlagHTTPProbeUserAgent              = "http-probe-user-agent"
+	FlagHTTPProbeHeader                 = "http-probe-header"
+	FlagHTTPProbePreflightCmd           = "http-probe-preflight-cmd"
+	FlagHTTPProbePreflightAbort         = "http-probe-preflight-abort"
+	FlagHTTPProbeMaxTime                = "http-probe-max-time"
+	FlagHTTPProbeContainerIP            = "http-probe-container-ip"
+	FlagHTTPProbePortReadyInterval      = "http-probe-port-ready-interval"
+	FlagHTTPProbeRateLimit              = "http-probe-rate-limit"
 
 	FlagHostExec     = "host-exec"
 	FlagHostExecFile = "host-exec-file"
 
+	FlagTCPProbe  = "tcp-probe"
+	FlagExecProbe = "exec-probe"
+
 	FlagPublishPort         = "publish-port"
 	FlagPublishExposedPorts = "publish-exposed-ports"
 
@@ -218,29 +277,88 @@ const (
 	FlagRemoveFileArtifactsUsage = "remove file artifacts when command is done"
 	FlagCopyMetaArtifactsUsage   = "copy metadata artifacts to the selected location when command is done"
 
-	FlagHTTPProbeUsage                 = "Enable or disable HTTP probing"
-	FlagHTTPProbeOffUsage              = "Alternative way to disable HTTP probing"
-	FlagHTTPProbeCmdUsage              = "User defined HTTP probe(s) as [[[[\"crawl\":]PROTO:]METHOD:]PATH]"
-	FlagHTTPProbeCmdFileUsage          = "File with user defined HTTP probes"
-	FlagHTTPProbeStartWaitUsage        = "Number of seconds to wait before starting HTTP probing"
-	FlagHTTPProbeRetryCountUsage       = "Number of retries for each HTTP probe"
-	FlagHTTPProbeRetryWaitUsage        = "Number of seconds to wait before retrying HTTP probe (doubles when target is not ready)"
-	FlagHTTPProbePortsUsage            = "Explicit list of ports to probe (in the order you want them to be probed)"
-	FlagHTTPProbeFullUsage             = "Do full HTTP probe for all selected ports (if false, finish after first successful scan)"
-	FlagHTTPProbeExitOnFailureUsage    = "Exit when all HTTP probe commands fail"
-	FlagHTTPProbeCrawlUsage            = "Enable crawling for the default HTTP probe command"
-	FlagHTTPCrawlMaxDepthUsage         = "Max depth to use for the HTTP probe crawler"
-	FlagHTTPCrawlMaxPageCountUsage     = "Max number of pages to visit for the HTTP probe crawler"
-	FlagHTTPCrawlConcurrencyUsage      = "Number of concurrent workers when crawling an HTTP target"
-	FlagHTTPMaxConcurrentCrawlersUsage = "Number of concurrent crawlers in the HTTP probe"
-	FlagHTTPProbeAPISpecUsage          = "Run HTTP probes for API spec"
-	FlagHTTPProbeAPISpecFileUsage      = "Run HTTP probes for API spec from file"
-	FlagHTTPProbeProxyEndpointUsage    = "Endpoint to proxy HTTP probes"
-	FlagHTTPProbeProxyPortUsage        = "Port to proxy HTTP probes (used with HTTP probe proxy endpoint)"
+	FlagHTTPProbeUsage                       = "Enable or disable HTTP probing"
+	FlagHTTPProbeOffUsage                    = "Alternative way to disable HTTP probing"
+	FlagHTTPProbeCmdUsage                    = "User defined HTTP probe(s) as [[[[\"crawl\":]PROTO:]METHOD:]PATH]"
+	FlagHTTPProbeCmdFileUsage                = "File with user defined HTTP probes"
+	FlagHTTPProbeStartWaitUsage              = "Time to wait before starting HTTP probing (a duration like 15s or a number of seconds)"
+	FlagHTTPProbeRetryCountUsage             = "Number of retries for each HTTP probe"
+	FlagHTTPProbeRetryWaitUsage              = "Number of seconds to wait before retrying HTTP probe (doubles when target is not ready)"
+	FlagHTTPProbePortsUsage                  = "Explicit list of ports to probe (in the order you want them to be probed)"
+	FlagHTTPProbeFullUsage                   = "Do full HTTP probe for all selected ports (if false, finish after first successful scan)"
+	FlagHTTPProbeExitOnFailureUsage          = "Exit when all HTTP probe commands fail"
+	FlagHTTPProbeCrawlUsage                  = "Enable crawling for the default HTTP probe command"
+	FlagHTTPCrawlMaxDepthUsage               = "Max depth to use for the HTTP probe crawler"
+	FlagHTTPCrawlMaxPageCountUsage           = "Max number of pages to visit for the HTTP probe crawler"
+	FlagHTTPCrawlConcurrencyUsage            = "Number of concurrent workers when crawling an HTTP target"
+	FlagHTTPMaxConcurrentCrawlersUsage       = "Number of concurrent crawlers in the HTTP probe"
+	FlagHTTPProbeAPISpecUsage                = "Run HTTP probes for API spec"
+	FlagHTTPProbeAPISpecFileUsage            = "Run HTTP probes for API spec from file"
+	FlagHTTPProbeProxyEndpointUsage          = "Endpoint to proxy HTTP probes"
+	FlagHTTPProbeProxyPortUsage              = "Port to proxy HTTP probes (used with HTTP probe proxy endpoint)"
+	FlagHTTPProbeDNSServerUsage              = "DNS server (host[:port]) to use when resolving the HTTP probe target names"
+	FlagHTTPProbeCSVOutputUsage              = "Save the HTTP probe call results (one row per call) to a CSV file"
+	FlagHTTPProbeCassetteUsage               = "Cassette file to record the HTTP probe interactions to or to play them back from"
+	FlagHTTPProbeCassetteModeUsage           = "HTTP probe cassette mode: record or playback (default: playback)"
+	FlagHTTPProbeRetryBackoffResetUsage      = "Reset the HTTP probe retry backoff when the target responds between the failed attempts"
+	FlagHTTPProbeAllAddressesUsage           = "Probe the target using all container network addresses (not just the primary address)"
+	FlagHTTPProbeRoutesEndpointUsage         = "App route table endpoint path used to generate HTTP probe calls for each route"
+	FlagHTTPProbeRoutesDestructiveUsage      = "Include the routes with destructive HTTP methods (POST, PUT, PATCH, DELETE) when probing the app route table"
+	FlagHTTPProbeRequestIDUsage              = "Add a request ID header (X-Request-ID) to each HTTP probe call (random or deterministic)"
+	FlagHTTPProbeCPUThrottleUsage            = "Delay the HTTP probe calls while the target container CPU usage is above the threshold"
+	FlagHTTPProbeCPUThrottleThresholdUsage   = "Container CPU usage percentage (of one CPU, like in 'docker stats') that delays the HTTP probe calls"
+	FlagHTTPProbeRetryOnUsage                = "Error categories retried by the HTTP probe (dns, refused, reset, timeout, eof, tls, response, other or all)"
+	FlagHTTPProbeConcurrencyUsage            = "Maximum number of independent HTTP probe commands executed in parallel (the target ports are also probed in parallel if it's greater than one)"
+	FlagHTTPProbePcapOutputUsage             = "Save the HTTP probe traffic to a (synthetic) pcap file"
+	FlagHTTPProbeDeadlineUsage               = "Absolute time when the HTTP probe stops (RFC3339 timestamp or local time of day like 14:30)"
+	FlagHTTPProbeRetryBudgetUsage            = "Total number of HTTP probe retries shared by the probe commands (based on the command weights, 0 means no limit)"
+	FlagHTTPProbeFailOnDuplicatesUsage       = "Fail if the HTTP probe command list has duplicate commands"
+	FlagHTTPProbeMetricsIntervalUsage        = "How often to print the HTTP probe scheduler gauges (active, queued and completed commands for each host)"
+	FlagHTTPProbeRequireExplicitPortsUsage   = "Fail instead of guessing the ports to probe when the image has no exposed ports and no target ports are set"
+	FlagHTTPProbeEventLogUsage               = "Save the HTTP calls and the streaming (websocket) messages to an event log file (JSON lines)"
+	FlagHTTPProbeEventLogMaxMessagesUsage    = "Maximum number of events saved in the HTTP probe event log"
+	FlagHTTPProbeCredentialsFileUsage        = "JSON file with the credential sets (name, username and password or token) tried in order when the HTTP probe call is unauthorized (401)"
+	FlagHTTPProbeTLSServerNameUsage          = "TLS server name (SNI) for the HTTP probe https calls to the targets probed by IP"
+	FlagHTTPProbeDataSeedUsage               = "Seed for the HTTP probe command data generators (e.g., '{{email}}' or '{{int 1 100}}'), random if not set"
+	FlagHTTPProbeScreenshotOnFailureUsage    = "Save the headless browser (Chrome or Chromium) screenshots of the failed HTTP probe page calls to this directory"
+	FlagHTTPProbeScreenshotBrowserUsage      = "Headless browser (Chrome or Chromium) executable for the HTTP probe failure screenshots"
+	FlagHTTPProbeSeedExecUsage               = "Host command to seed the app data before the HTTP probe commands (e.g., to load an SQL file into the database container)"
+	FlagHTTPProbeSeedEndpointUsage           = "App endpoint called to seed the app data before the HTTP probe commands ('[METHOD ]RESOURCE', POST by default)"
+	FlagHTTPProbeSeverityRuleUsage           = "HTTP probe result severity rule ('METRIC[>|>=|<|<=]THRESHOLD:SEVERITY', e.g., 'failure_rate>5:warn')"
+	FlagHTTPProbeMaxResponseHeaderBytesUsage = "Maximum response header size for the HTTP probe calls (Go's default limit if it's zero)"
+	FlagHTTPProbeResultAssertUsage           = "HTTP probe result assertion evaluated after the probe run ('ok_per_port', 'no_failed_commands', 'cmd_ok:NAME' or 'expr:EXPRESSION')"
+	FlagHTTPProbePortReadyTimeoutUsage       = "How long to wait for the target ports to accept connections before the HTTP probe calls (default: 60s; the port probe commands are skipped after the timeout, a negative value disables the port polling)"
+	FlagHTTPProbeExpectStatusUsage           = "Status codes for the successful HTTP probe calls (used for the probe commands without expected status codes, any response is successful by default)"
+	FlagHTTPProbeAPISpecCmdsUsage            = "OpenAPI/Swagger spec file or URL used to generate the HTTP probe commands (one command for each path and method)"
+	FlagHTTPProbeReportUsage                 = "Save the HTTP probe call records and the probe summary to a JSON file"
+	FlagHTTPProbeRetryMaxWaitUsage           = "Maximum HTTP probe retry wait time (the retry wait doubles with each failed attempt up to this limit; default: 60s)"
+	FlagHTTPProbeMaxRedirectsUsage           = "Maximum number of redirects followed by the HTTP probe calls (the calls fail when the limit is reached, so the redirect loops don't hang the probe)"
+	FlagHTTPProbeNoCookieJarUsage            = "Don't share the cookies between the HTTP probe command calls (each call is isolated)"
+	FlagHTTPProbeCallTimeoutUsage            = "Timeout for each HTTP probe call, including the response body (default: 30s)"
+	FlagHTTPProbeConnectTimeoutUsage         = "Connect timeout for the HTTP probe calls, so the unreachable targets fail fast (default: 5s)"
+	FlagHTTPProbeProxyUsage                  = "Proxy URL for the HTTP probe calls (http, https or socks5; default: the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables)"
+	FlagHTTPProbeHostUsage                   = "Target host or IP address for the HTTP probe calls (default: the Docker host IP or the container IP)"
+	FlagHTTPProbeUseHealthcheckUsage         = "Add a probe command for the URL in the image HEALTHCHECK instruction"
+	FlagHTTPProbeVerifyTLSUsage              = "Verify the server certificates for the HTTP probe calls"
+	FlagHTTPProbeCACertUsage                 = "CA certificate bundle file (PEM) to verify the server certificates for the HTTP probe calls"
+	FlagHTTPProbeRepeatUsage                 = "Number of the additional calls for each probe command after its first successful call (to load the lazily loaded code)"
+	FlagHTTPProbeDryRunUsage                 = "Print the HTTP probe plan (the probed URLs for the probe commands) without making the probe calls"
+	FlagHTTPProbeVerboseUsage                = "Print each HTTP probe call attempt (only the final call outcome for each command target is printed by default)"
+	FlagHTTPProbeUserAgentUsage              = "User-Agent header for all HTTP probe calls (the probe command headers can override it)"
+	FlagHTTPProbeHeaderUsage                 = "Default header ('Name: Value') for all HTTP probe calls (the probe command headers override the default headers)"
+	FlagHTTPProbePreflightCmdUsage           = "User defined HTTP probe command that runs once and must succeed before the probe commands (same format as --http-probe-cmd)"
+	FlagHTTPProbePreflightAbortUsage         = "Skip the HTTP probe commands and fail the probe if a preflight command fails"
+	FlagHTTPProbeMaxTimeUsage                = "Maximum total HTTP probe duration starting when the probe starts (not limited if it's zero)"
+	FlagHTTPProbeContainerIPUsage            = "Also probe the container IP address (with the container ports) when the published host ports are not reachable"
+	FlagHTTPProbePortReadyIntervalUsage      = "How often the target ports are polled while waiting for them to accept connections (default: 500ms)"
+	FlagHTTPProbeRateLimitUsage              = "Maximum number of HTTP probe calls per second (across all probe workers, 0 means no limit)"
 
 	FlagHostExecUsage     = "Host commands to execute (aka host commands probes)"
 	FlagHostExecFileUsage = "Host commands to execute loaded from file (aka host commands probes)"
 
+	FlagTCPProbeUsage  = "TCP probe for a target port that doesn't serve HTTP (format => port | port:send | port:send:expect, where expect is the response prefix)"
+	FlagExecProbeUsage = "Command executed in the target container (with docker exec) when it's probed, e.g., to trigger the code paths not reachable with HTTP (the command arguments are split like in a shell)"
+
 	FlagPublishPortUsage         = "Map container port to host port (format => port | hostPort:containerPort | hostIP:hostPort:containerPort | hostIP::containerPort )"
 	FlagPublishExposedPortsUsage = "Map all exposed ports to the same host ports"
 
@@ -619,9 +737,9 @@ var CommonFlags = map[string]cli.Flag{
 		Usage:   FlagHTTPProbeAPISpecFileUsage,
 		EnvVars: []string{"DSLIM_HTTP_PROBE_API_SPEC_FILE"},
 	},
-	FlagHTTPProbeStartWait: &cli.IntFlag{
+	FlagHTTPProbeStartWait: &cli.StringFlag{
 		Name:    FlagHTTPProbeStartWait,
-		Value:   0,
+		Value:   "",
 		Usage:   FlagHTTPProbeStartWaitUsage,
 		EnvVars: []string{"DSLIM_HTTP_PROBE_START_WAIT"},
 	},
@@ -657,7 +775,7 @@ var CommonFlags = map[string]cli.Flag{
 	FlagHTTPProbeCrawl: &cli.BoolFlag{
 		Name:    FlagHTTPProbeCrawl,
 		Value:   true,
-		Usage:   FlagHTTPProbeCrawl,
+		Usage:   FlagHTTPProbeCrawlUsage,
 		EnvVars: []string{"DSLIM_HTTP_PROBE_CRAWL"},
 	},
 	FlagHTTPCrawlMaxDepth: &cli.IntFlag{
@@ -696,6 +814,327 @@ var CommonFlags = map[string]cli.Flag{
 		Usage:   FlagHTTPProbeProxyPortUsage,
 		EnvVars: []string{"DSLIM_HTTP_PROBE_PROXY_PORT"},
 	},
+	FlagHTTPProbeDNSServer: &cli.StringFlag{
+		Name:    FlagHTTPProbeDNSServer,
+		Value:   "",
+		Usage:   FlagHTTPProbeDNSServerUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_DNS_SERVER"},
+	},
+	FlagHTTPProbeCSVOutput: &cli.StringFlag{
+		Name:    FlagHTTPProbeCSVOutput,
+		Value:   "",
+		Usage:   FlagHTTPProbeCSVOutputUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CSV_OUTPUT"},
+	},
+	FlagHTTPProbeCassette: &cli.StringFlag{
+		Name:    FlagHTTPProbeCassette,
+		Value:   "",
+		Usage:   FlagHTTPProbeCassetteUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CASSETTE"},
+	},
+	FlagHTTPProbeCassetteMode: &cli.StringFlag{
+		Name:    FlagHTTPProbeCassetteMode,
+		Value:   "",
+		Usage:   FlagHTTPProbeCassetteModeUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CASSETTE_MODE"},
+	},
+	FlagHTTPProbeRetryBackoffReset: &cli.BoolFlag{
+		Name:    FlagHTTPProbeRetryBackoffReset,
+		Value:   true,
+		Usage:   FlagHTTPProbeRetryBackoffResetUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RETRY_BACKOFF_RESET"},
+	},
+	FlagHTTPProbeAllAddresses: &cli.BoolFlag{
+		Name:    FlagHTTPProbeAllAddresses,
+		Value:   false,
+		Usage:   FlagHTTPProbeAllAddressesUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_ALL_ADDRESSES"},
+	},
+	FlagHTTPProbeRoutesEndpoint: &cli.StringFlag{
+		Name:    FlagHTTPProbeRoutesEndpoint,
+		Usage:   FlagHTTPProbeRoutesEndpointUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_ROUTES_ENDPOINT"},
+	},
+	FlagHTTPProbeRoutesDestructive: &cli.BoolFlag{
+		Name:    FlagHTTPProbeRoutesDestructive,
+		Value:   false,
+		Usage:   FlagHTTPProbeRoutesDestructiveUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_ROUTES_DESTRUCTIVE"},
+	},
+	FlagHTTPProbeRequestID: &cli.StringFlag{
+		Name:    FlagHTTPProbeRequestID,
+		Usage:   FlagHTTPProbeRequestIDUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_REQUEST_ID"},
+	},
+	FlagHTTPProbeCPUThrottle: &cli.BoolFlag{
+		Name:    FlagHTTPProbeCPUThrottle,
+		Value:   false,
+		Usage:   FlagHTTPProbeCPUThrottleUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CPU_THROTTLE"},
+	},
+	FlagHTTPProbeCPUThrottleThreshold: &cli.IntFlag{
+		Name:    FlagHTTPProbeCPUThrottleThreshold,
+		Value:   90,
+		Usage:   FlagHTTPProbeCPUThrottleThresholdUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CPU_THROTTLE_THRESHOLD"},
+	},
+	FlagHTTPProbeRetryOn: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeRetryOn,
+		Usage:   FlagHTTPProbeRetryOnUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RETRY_ON"},
+	},
+	FlagHTTPProbeConcurrency: &cli.IntFlag{
+		Name:    FlagHTTPProbeConcurrency,
+		Value:   1,
+		Usage:   FlagHTTPProbeConcurrencyUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CONCURRENCY"},
+	},
+	FlagHTTPProbePcapOutput: &cli.StringFlag{
+		Name:    FlagHTTPProbePcapOutput,
+		Value:   "",
+		Usage:   FlagHTTPProbePcapOutputUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PCAP_OUTPUT"},
+	},
+	FlagHTTPProbeDeadline: &cli.StringFlag{
+		Name:    FlagHTTPProbeDeadline,
+		Value:   "",
+		Usage:   FlagHTTPProbeDeadlineUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_DEADLINE"},
+	},
+	FlagHTTPProbeRetryBudget: &cli.IntFlag{
+		Name:    FlagHTTPProbeRetryBudget,
+		Value:   0,
+		Usage:   FlagHTTPProbeRetryBudgetUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RETRY_BUDGET"},
+	},
+	FlagHTTPProbeFailOnDuplicates: &cli.BoolFlag{
+		Name:    FlagHTTPProbeFailOnDuplicates,
+		Value:   false,
+		Usage:   FlagHTTPProbeFailOnDuplicatesUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_FAIL_ON_DUPLICATES"},
+	},
+	FlagHTTPProbeMetricsInterval: &cli.DurationFlag{
+		Name:    FlagHTTPProbeMetricsInterval,
+		Value:   0,
+		Usage:   FlagHTTPProbeMetricsIntervalUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_METRICS_INTERVAL"},
+	},
+	FlagHTTPProbeRequireExplicitPorts: &cli.BoolFlag{
+		Name:    FlagHTTPProbeRequireExplicitPorts,
+		Value:   false,
+		Usage:   FlagHTTPProbeRequireExplicitPortsUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_REQUIRE_EXPLICIT_PORTS"},
+	},
+	FlagHTTPProbeEventLog: &cli.StringFlag{
+		Name:    FlagHTTPProbeEventLog,
+		Value:   "",
+		Usage:   FlagHTTPProbeEventLogUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_EVENT_LOG"},
+	},
+	FlagHTTPProbeEventLogMaxMessages: &cli.IntFlag{
+		Name:    FlagHTTPProbeEventLogMaxMessages,
+		Value:   1000,
+		Usage:   FlagHTTPProbeEventLogMaxMessagesUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_EVENT_LOG_MAX_MESSAGES"},
+	},
+	FlagHTTPProbeCredentialsFile: &cli.StringFlag{
+		Name:    FlagHTTPProbeCredentialsFile,
+		Value:   "",
+		Usage:   FlagHTTPProbeCredentialsFileUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CREDENTIALS_FILE"},
+	},
+	FlagHTTPProbeTLSServerName: &cli.StringFlag{
+		Name:    FlagHTTPProbeTLSServerName,
+		Value:   "",
+		Usage:   FlagHTTPProbeTLSServerNameUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_TLS_SERVER_NAME"},
+	},
+	FlagHTTPProbeDataSeed: &cli.Int64Flag{
+		Name:    FlagHTTPProbeDataSeed,
+		Value:   0,
+		Usage:   FlagHTTPProbeDataSeedUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_DATA_SEED"},
+	},
+	FlagHTTPProbeScreenshotOnFailure: &cli.StringFlag{
+		Name:    FlagHTTPProbeScreenshotOnFailure,
+		Value:   "",
+		Usage:   FlagHTTPProbeScreenshotOnFailureUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_SCREENSHOT_ON_FAILURE"},
+	},
+	FlagHTTPProbeScreenshotBrowser: &cli.StringFlag{
+		Name:    FlagHTTPProbeScreenshotBrowser,
+		Value:   "",
+		Usage:   FlagHTTPProbeScreenshotBrowserUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_SCREENSHOT_BROWSER"},
+	},
+	FlagHTTPProbeSeedExec: &cli.StringFlag{
+		Name:    FlagHTTPProbeSeedExec,
+		Value:   "",
+		Usage:   FlagHTTPProbeSeedExecUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_SEED_EXEC"},
+	},
+	FlagHTTPProbeSeedEndpoint: &cli.StringFlag{
+		Name:    FlagHTTPProbeSeedEndpoint,
+		Value:   "",
+		Usage:   FlagHTTPProbeSeedEndpointUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_SEED_ENDPOINT"},
+	},
+	FlagHTTPProbeSeverityRule: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeSeverityRule,
+		Usage:   FlagHTTPProbeSeverityRuleUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_SEVERITY_RULE"},
+	},
+	FlagHTTPProbeMaxResponseHeaderBytes: &cli.Int64Flag{
+		Name:    FlagHTTPProbeMaxResponseHeaderBytes,
+		Value:   0,
+		Usage:   FlagHTTPProbeMaxResponseHeaderBytesUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_MAX_RESPONSE_HEADER_BYTES"},
+	},
+	FlagHTTPProbeResultAssert: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeResultAssert,
+		Usage:   FlagHTTPProbeResultAssertUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RESULT_ASSERT"},
+	},
+	FlagHTTPProbePortReadyTimeout: &cli.DurationFlag{
+		Name:    FlagHTTPProbePortReadyTimeout,
+		Value:   0,
+		Usage:   FlagHTTPProbePortReadyTimeoutUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PORT_READY_TIMEOUT"},
+	},
+	FlagHTTPProbeExpectStatus: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeExpectStatus,
+		Usage:   FlagHTTPProbeExpectStatusUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_EXPECT_STATUS"},
+	},
+	FlagHTTPProbeAPISpecCmds: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeAPISpecCmds,
+		Usage:   FlagHTTPProbeAPISpecCmdsUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_APISPEC_CMDS"},
+	},
+	FlagHTTPProbeReport: &cli.StringFlag{
+		Name:    FlagHTTPProbeReport,
+		Value:   "",
+		Usage:   FlagHTTPProbeReportUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_REPORT"},
+	},
+	FlagHTTPProbeRetryMaxWait: &cli.DurationFlag{
+		Name:    FlagHTTPProbeRetryMaxWait,
+		Value:   0,
+		Usage:   FlagHTTPProbeRetryMaxWaitUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RETRY_MAX_WAIT"},
+	},
+	FlagHTTPProbeMaxRedirects: &cli.IntFlag{
+		Name:    FlagHTTPProbeMaxRedirects,
+		Value:   10,
+		Usage:   FlagHTTPProbeMaxRedirectsUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_MAX_REDIRECTS"},
+	},
+	FlagHTTPProbeNoCookieJar: &cli.BoolFlag{
+		Name:    FlagHTTPProbeNoCookieJar,
+		Usage:   FlagHTTPProbeNoCookieJarUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_NO_COOKIE_JAR"},
+	},
+	FlagHTTPProbeCallTimeout: &cli.DurationFlag{
+		Name:    FlagHTTPProbeCallTimeout,
+		Value:   0,
+		Usage:   FlagHTTPProbeCallTimeoutUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CALL_TIMEOUT"},
+	},
+	FlagHTTPProbeConnectTimeout: &cli.DurationFlag{
+		Name:    FlagHTTPProbeConnectTimeout,
+		Value:   0,
+		Usage:   FlagHTTPProbeConnectTimeoutUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CONNECT_TIMEOUT"},
+	},
+	FlagHTTPProbeProxy: &cli.StringFlag{
+		Name:    FlagHTTPProbeProxy,
+		Usage:   FlagHTTPProbeProxyUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PROXY"},
+	},
+	FlagHTTPProbeHost: &cli.StringFlag{
+		Name:    FlagHTTPProbeHost,
+		Usage:   FlagHTTPProbeHostUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_HOST"},
+	},
+	FlagHTTPProbeUseHealthcheck: &cli.BoolFlag{
+		Name:    FlagHTTPProbeUseHealthcheck,
+		Value:   false,
+		Usage:   FlagHTTPProbeUseHealthcheckUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_USE_HEALTHCHECK"},
+	},
+	FlagHTTPProbeVerifyTLS: &cli.BoolFlag{
+		Name:    FlagHTTPProbeVerifyTLS,
+		Value:   false,
+		Usage:   FlagHTTPProbeVerifyTLSUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_VERIFY_TLS"},
+	},
+	FlagHTTPProbeCACert: &cli.StringFlag{
+		Name:    FlagHTTPProbeCACert,
+		Usage:   FlagHTTPProbeCACertUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CA_CERT"},
+	},
+	FlagHTTPProbeRepeat: &cli.IntFlag{
+		Name:    FlagHTTPProbeRepeat,
+		Value:   0,
+		Usage:   FlagHTTPProbeRepeatUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_REPEAT"},
+	},
+	FlagHTTPProbeDryRun: &cli.BoolFlag{
+		Name:    FlagHTTPProbeDryRun,
+		Value:   false,
+		Usage:   FlagHTTPProbeDryRunUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_DRY_RUN"},
+	},
+	FlagHTTPProbeVerbose: &cli.BoolFlag{
+		Name:    FlagHTTPProbeVerbose,
+		Value:   false,
+		Usage:   FlagHTTPProbeVerboseUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_VERBOSE"},
+	},
+	FlagHTTPProbeUserAgent: &cli.StringFlag{
+		Name:    FlagHTTPProbeUserAgent,
+		Value:   "",
+		Usage:   FlagHTTPProbeUserAgentUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_USER_AGENT"},
+	},
+	FlagHTTPProbeHeader: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbeHeader,
+		Value:   cli.NewStringSlice(),
+		Usage:   FlagHTTPProbeHeaderUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_HEADER"},
+	},
+	FlagHTTPProbePreflightCmd: &cli.StringSliceFlag{
+		Name:    FlagHTTPProbePreflightCmd,
+		Value:   cli.NewStringSlice(),
+		Usage:   FlagHTTPProbePreflightCmdUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PREFLIGHT_CMD"},
+	},
+	FlagHTTPProbePreflightAbort: &cli.BoolFlag{
+		Name:    FlagHTTPProbePreflightAbort,
+		Value:   false,
+		Usage:   FlagHTTPProbePreflightAbortUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PREFLIGHT_ABORT"},
+	},
+	FlagHTTPProbeMaxTime: &cli.DurationFlag{
+		Name:    FlagHTTPProbeMaxTime,
+		Usage:   FlagHTTPProbeMaxTimeUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_MAX_TIME"},
+	},
+	FlagHTTPProbeContainerIP: &cli.BoolFlag{
+		Name:    FlagHTTPProbeContainerIP,
+		Usage:   FlagHTTPProbeContainerIPUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_CONTAINER_IP"},
+	},
+	FlagHTTPProbePortReadyInterval: &cli.DurationFlag{
+		Name:    FlagHTTPProbePortReadyInterval,
+		Usage:   FlagHTTPProbePortReadyIntervalUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_PORT_READY_INTERVAL"},
+	},
+	FlagHTTPProbeRateLimit: &cli.Float64Flag{
+		Name:    FlagHTTPProbeRateLimit,
+		Usage:   FlagHTTPProbeRateLimitUsage,
+		EnvVars: []string{"DSLIM_HTTP_PROBE_RATE_LIMIT"},
+	},
 	FlagHostExec: &cli.StringSliceFlag{
 		Name:    FlagHostExec,
 		Value:   cli.NewStringSlice(),
@@ -708,6 +1147,18 @@ var CommonFlags = map[string]cli.Flag{
 		Usage:   FlagHostExecFileUsage,
 		EnvVars: []string{"DSLIM_HOST_EXEC_FILE"},
 	},
+	FlagTCPProbe: &cli.StringSliceFlag{
+		Name:    FlagTCPProbe,
+		Value:   cli.NewStringSlice(),
+		Usage:   FlagTCPProbeUsage,
+		EnvVars: []string{"DSLIM_TCP_PROBE"},
+	},
+	FlagExecProbe: &cli.StringSliceFlag{
+		Name:    FlagExecProbe,
+		Value:   cli.NewStringSlice(),
+		Usage:   FlagExecProbeUsage,
+		EnvVars: []string{"DSLIM_EXEC_PROBE"},
+	},
 	FlagPublishPort: &cli.StringSliceFlag{
 		Name:    FlagPublishPort,
 		Value:   cli.NewStringSlice(),
@@ -950,6 +1401,62 @@ func HTTPProbeFlagsBasic() []cli.Flag {
 		Cflag(FlagHTTPMaxConcurrentCrawlers),
 		Cflag(FlagHTTPProbeAPISpec),
 		Cflag(FlagHTTPProbeAPISpecFile),
+		Cflag(FlagHTTPProbeDNSServer),
+		Cflag(FlagHTTPProbeCSVOutput),
+		Cflag(FlagHTTPProbeCassette),
+		Cflag(FlagHTTPProbeCassetteMode),
+		Cflag(FlagHTTPProbeRetryBackoffReset),
+		Cflag(FlagHTTPProbeAllAddresses),
+		Cflag(FlagHTTPProbeRoutesEndpoint),
+		Cflag(FlagHTTPProbeRoutesDestructive),
+		Cflag(FlagHTTPProbeRequestID),
+		Cflag(FlagHTTPProbeCPUThrottle),
+		Cflag(FlagHTTPProbeCPUThrottleThreshold),
+		Cflag(FlagHTTPProbeRetryOn),
+		Cflag(FlagHTTPProbeConcurrency),
+		Cflag(FlagHTTPProbePcapOutput),
+		Cflag(FlagHTTPProbeDeadline),
+		Cflag(FlagHTTPProbeRetryBudget),
+		Cflag(FlagHTTPProbeFailOnDuplicates),
+		Cflag(FlagHTTPProbeMetricsInterval),
+		Cflag(FlagHTTPProbeRequireExplicitPorts),
+		Cflag(FlagHTTPProbeEventLog),
+		Cflag(FlagHTTPProbeEventLogMaxMessages),
+		Cflag(FlagHTTPProbeCredentialsFile),
+		Cflag(FlagHTTPProbeTLSServerName),
+		Cflag(FlagHTTPProbeDataSeed),
+		Cflag(FlagHTTPProbeScreenshotOnFailure),
+		Cflag(FlagHTTPProbeScreenshotBrowser),
+		Cflag(FlagHTTPProbeSeedExec),
+		Cflag(FlagHTTPProbeSeedEndpoint),
+		Cflag(FlagHTTPProbeSeverityRule),
+		Cflag(FlagHTTPProbeMaxResponseHeaderBytes),
+		Cflag(FlagHTTPProbeResultAssert),
+		Cflag(FlagHTTPProbePortReadyTimeout),
+		Cflag(FlagHTTPProbeExpectStatus),
+		Cflag(FlagHTTPProbeAPISpecCmds),
+		Cflag(FlagHTTPProbeReport),
+		Cflag(FlagHTTPProbeRetryMaxWait),
+		Cflag(FlagHTTPProbeMaxRedirects),
+		Cflag(FlagHTTPProbeNoCookieJar),
+		Cflag(FlagHTTPProbeCallTimeout),
+		Cflag(FlagHTTPProbeConnectTimeout),
+		Cflag(FlagHTTPProbeProxy),
+		Cflag(FlagHTTPProbeHost),
+		Cflag(FlagHTTPProbeUseHealthcheck),
+		Cflag(FlagHTTPProbeVerifyTLS),
+		Cflag(FlagHTTPProbeCACert),
+		Cflag(FlagHTTPProbeRepeat),
+		Cflag(FlagHTTPProbeDryRun),
+		Cflag(FlagHTTPProbeVerbose),
+		Cflag(FlagHTTPProbeUserAgent),
+		Cflag(FlagHTTPProbeHeader),
+		Cflag(FlagHTTPProbePreflightCmd),
+		Cflag(FlagHTTPProbePreflightAbort),
+		Cflag(FlagHTTPProbeMaxTime),
+		Cflag(FlagHTTPProbeContainerIP),
+		Cflag(FlagHTTPProbePortReadyInterval),
+		Cflag(FlagHTTPProbeRateLimit),
 	}
 }
 
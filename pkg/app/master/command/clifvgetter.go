@@ -5,6 +5,7 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -58,14 +59,48 @@ func GetHTTPProbeOptions(xc *app.ExecutionContext, ctx *cli.Context, doProbe boo
 	opts := config.HTTPProbeOptions{
 		Full: ctx.Bool(FlagHTTPProbeFull),
 
-		StartWait:  ctx.Int(FlagHTTPProbeStartWait),
-		RetryCount: ctx.Int(FlagHTTPProbeRetryCount),
-		RetryWait:  ctx.Int(FlagHTTPProbeRetryWait),
+		RetryCount:        ctx.Int(FlagHTTPProbeRetryCount),
+		RetryWait:         ctx.Int(FlagHTTPProbeRetryWait),
+		RetryBackoffReset: ctx.Bool(FlagHTTPProbeRetryBackoffReset),
+		RetryMaxWait:      ctx.Duration(FlagHTTPProbeRetryMaxWait),
+		TotalRetryBudget:  ctx.Int(FlagHTTPProbeRetryBudget),
+
+		Concurrency: ctx.Int(FlagHTTPProbeConcurrency),
+
+		FailOnDuplicateCmds:  ctx.Bool(FlagHTTPProbeFailOnDuplicates),
+		RequireExplicitPorts: ctx.Bool(FlagHTTPProbeRequireExplicitPorts),
+		MetricsInterval:      ctx.Duration(FlagHTTPProbeMetricsInterval),
+		DataSeed:             ctx.Int64(FlagHTTPProbeDataSeed),
+
+		SeedExec:     ctx.String(FlagHTTPProbeSeedExec),
+		SeedEndpoint: ctx.String(FlagHTTPProbeSeedEndpoint),
+
+		ScreenshotOnFailure: ctx.String(FlagHTTPProbeScreenshotOnFailure),
+		ScreenshotBrowser:   ctx.String(FlagHTTPProbeScreenshotBrowser),
 
 		CrawlMaxDepth:       ctx.Int(FlagHTTPCrawlMaxDepth),
 		CrawlMaxPageCount:   ctx.Int(FlagHTTPCrawlMaxPageCount),
 		CrawlConcurrency:    ctx.Int(FlagHTTPCrawlConcurrency),
 		CrawlConcurrencyMax: ctx.Int(FlagHTTPMaxConcurrentCrawlers),
+
+		RoutesEndpoint:    ctx.String(FlagHTTPProbeRoutesEndpoint),
+		RoutesDestructive: ctx.Bool(FlagHTTPProbeRoutesDestructive),
+
+		AllAddresses: ctx.Bool(FlagHTTPProbeAllAddresses),
+
+		CPUThrottle:          ctx.Bool(FlagHTTPProbeCPUThrottle),
+		CPUThrottleThreshold: ctx.Int(FlagHTTPProbeCPUThrottleThreshold),
+
+		CSVOutput: ctx.String(FlagHTTPProbeCSVOutput),
+
+		ReportOutput: ctx.String(FlagHTTPProbeReport),
+
+		PcapOutput: ctx.String(FlagHTTPProbePcapOutput),
+
+		NoCookieJar: ctx.Bool(FlagHTTPProbeNoCookieJar),
+
+		EventLogOutput:      ctx.String(FlagHTTPProbeEventLog),
+		EventLogMaxMessages: ctx.Int(FlagHTTPProbeEventLogMaxMessages),
 	}
 
 	if doProbe {
@@ -84,7 +119,30 @@ func GetHTTPProbeOptions(xc *app.ExecutionContext, ctx *cli.Context, doProbe boo
 			})
 		xc.Exit(-1)
 	}
-	opts.Cmds = cmds
+
+	preflightCmds, err := ParseHTTPProbes(ctx.StringSlice(FlagHTTPProbePreflightCmd))
+	if err != nil {
+		xc.Out.Error("param.http.probe.preflight", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.PreflightCmds = preflightCmds
+	for _, cmd := range cmds {
+		//the preflight commands from the commands file
+		if cmd.GroupStage == config.CmdStagePreflight {
+			cmd.GroupStage = ""
+			opts.PreflightCmds = append(opts.PreflightCmds, cmd)
+			continue
+		}
+
+		opts.Cmds = append(opts.Cmds, cmd)
+	}
+
+	opts.PreflightAbort = ctx.Bool(FlagHTTPProbePreflightAbort)
 
 	if opts.Do && len(opts.Cmds) == 0 {
 		//add default probe cmd if the "http-probe" flag is set
@@ -116,6 +174,282 @@ func GetHTTPProbeOptions(xc *app.ExecutionContext, ctx *cli.Context, doProbe boo
 	}
 	opts.Ports = ports
 
+	if opts.SeedEndpoint != "" {
+		if _, _, err := config.ParseProbeSeedEndpoint(opts.SeedEndpoint); err != nil {
+			xc.Out.Error("param.http.probe.seed.endpoint", err.Error())
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+	}
+
+	opts.TLSServerName = ctx.String(FlagHTTPProbeTLSServerName)
+	if opts.TLSServerName != "" && net.ParseIP(opts.TLSServerName) != nil {
+		xc.Out.Error("param.http.probe.tls.server.name", "the TLS server name must be a host name (not an IP address)")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.MaxResponseHeaderBytes = ctx.Int64(FlagHTTPProbeMaxResponseHeaderBytes)
+	if opts.MaxResponseHeaderBytes < 0 {
+		xc.Out.Error("param.http.probe.max.response.header.bytes", "the response header size limit can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.MaxRedirects = ctx.Int(FlagHTTPProbeMaxRedirects)
+	if opts.MaxRedirects < 1 {
+		xc.Out.Error("param.http.probe.max.redirects", "the redirect limit must be at least 1")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	dnsServer, err := ParseDNSServer(ctx.String(FlagHTTPProbeDNSServer))
+	if err != nil {
+		xc.Out.Error("param.http.probe.dns.server", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+	opts.DNSServer = dnsServer
+
+	opts.CassetteFile = ctx.String(FlagHTTPProbeCassette)
+	opts.CassetteMode = strings.ToLower(ctx.String(FlagHTTPProbeCassetteMode))
+	if opts.CassetteFile != "" {
+		if opts.CassetteMode == "" {
+			opts.CassetteMode = config.CassetteModePlayback
+		}
+
+		switch opts.CassetteMode {
+		case config.CassetteModeRecord:
+		case config.CassetteModePlayback:
+			if _, err := os.Stat(opts.CassetteFile); err != nil {
+				xc.Out.Error("param.http.probe.cassette", err.Error())
+				xc.Out.State("exited",
+					ovars{
+						"exit.code": -1,
+					})
+				xc.Exit(-1)
+			}
+		default:
+			xc.Out.Error("param.http.probe.cassette.mode", fmt.Sprintf("unknown mode - %s", opts.CassetteMode))
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+	} else {
+		opts.CassetteMode = ""
+	}
+
+	opts.RequestIDMode = strings.ToLower(ctx.String(FlagHTTPProbeRequestID))
+	switch opts.RequestIDMode {
+	case "", config.RequestIDModeRandom, config.RequestIDModeDeterministic:
+	default:
+		xc.Out.Error("param.http.probe.request.id", fmt.Sprintf("unknown mode - %s", opts.RequestIDMode))
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.RetryOn = config.DefaultProbeRetryOn
+	if retryOn := ctx.StringSlice(FlagHTTPProbeRetryOn); len(retryOn) > 0 {
+		opts.RetryOn = nil
+		for _, category := range retryOn {
+			category = strings.ToLower(strings.TrimSpace(category))
+			if !config.IsProbeErrorCategory(category) {
+				xc.Out.Error("param.http.probe.retry.on", fmt.Sprintf("unknown error category - %s", category))
+				xc.Out.State("exited",
+					ovars{
+						"exit.code": -1,
+					})
+				xc.Exit(-1)
+			}
+
+			opts.RetryOn = append(opts.RetryOn, category)
+		}
+	}
+
+	opts.ExpectStatus, err = ParseHTTPProbeExpectStatus(ctx.StringSlice(FlagHTTPProbeExpectStatus))
+	if err != nil {
+		xc.Out.Error("param.http.probe.expect.status", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.FallbackCredentials, err = ParseHTTPProbeCredentialsFile(ctx.String(FlagHTTPProbeCredentialsFile))
+	if err != nil {
+		xc.Out.Error("param.http.probe.credentials.file", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.StartWait, err = ParseProbeStartWait(ctx.String(FlagHTTPProbeStartWait))
+	if err != nil {
+		xc.Out.Error("param.http.probe.start.wait", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.PortReadyTimeout = ctx.Duration(FlagHTTPProbePortReadyTimeout)
+	opts.PortReadyInterval = ctx.Duration(FlagHTTPProbePortReadyInterval)
+	if opts.PortReadyInterval < 0 {
+		xc.Out.Error("param.http.probe.port.ready.interval", "the HTTP probe port polling interval can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.RateLimit = ctx.Float64(FlagHTTPProbeRateLimit)
+	if opts.RateLimit < 0 {
+		xc.Out.Error("param.http.probe.rate.limit", "the HTTP probe rate limit can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	opts.CallTimeout = ctx.Duration(FlagHTTPProbeCallTimeout)
+	opts.ConnectTimeout = ctx.Duration(FlagHTTPProbeConnectTimeout)
+	opts.RepeatCount = ctx.Int(FlagHTTPProbeRepeat)
+	if opts.RepeatCount < 0 {
+		xc.Out.Error("param.http.probe.repeat", "the HTTP probe repeat count can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	if opts.CallTimeout < 0 || opts.ConnectTimeout < 0 {
+		xc.Out.Error("param.http.probe.timeout", "the HTTP probe call and connect timeouts can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	if host := ctx.String(FlagHTTPProbeHost); host != "" {
+		opts.Host, err = config.ParseProbeHost(host)
+		if err != nil {
+			xc.Out.Error("param.http.probe.host", err.Error())
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+	}
+
+	opts.UseHealthcheck = ctx.Bool(FlagHTTPProbeUseHealthcheck)
+	opts.VerifyTLS = ctx.Bool(FlagHTTPProbeVerifyTLS)
+	opts.CACert = ctx.String(FlagHTTPProbeCACert)
+	opts.DryRun = ctx.Bool(FlagHTTPProbeDryRun)
+	opts.Verbose = ctx.Bool(FlagHTTPProbeVerbose)
+	opts.UserAgent = ctx.String(FlagHTTPProbeUserAgent)
+	opts.ContainerIP = ctx.Bool(FlagHTTPProbeContainerIP)
+
+	for _, header := range ctx.StringSlice(FlagHTTPProbeHeader) {
+		if hparts := strings.SplitN(header, ":", 2); len(hparts) != 2 || strings.TrimSpace(hparts[0]) == "" {
+			xc.Out.Error("param.http.probe.header", fmt.Sprintf("malformed header (expected 'Name: Value'): %s", header))
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+
+		opts.Headers = append(opts.Headers, header)
+	}
+
+	if proxy := ctx.String(FlagHTTPProbeProxy); proxy != "" {
+		if _, err := config.ParseProbeProxy(proxy); err != nil {
+			xc.Out.Error("param.http.probe.proxy", err.Error())
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+
+		opts.Proxy = proxy
+	}
+
+	opts.SeverityRules, err = ParseHTTPProbeSeverityRules(ctx.StringSlice(FlagHTTPProbeSeverityRule))
+	if err != nil {
+		xc.Out.Error("param.http.probe.severity.rule", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	for _, assertion := range ctx.StringSlice(FlagHTTPProbeResultAssert) {
+		assertion = strings.TrimSpace(assertion)
+		if !config.IsProbeResultAssertion(assertion) {
+			xc.Out.Error("param.http.probe.result.assert", fmt.Sprintf("unknown probe result assertion: '%s'", assertion))
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+
+		opts.ResultAssertions = append(opts.ResultAssertions, assertion)
+	}
+
+	opts.MaxTime = ctx.Duration(FlagHTTPProbeMaxTime)
+	if opts.MaxTime < 0 {
+		xc.Out.Error("param.http.probe.max.time", "the HTTP probe max time can't be negative")
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	if deadline := ctx.String(FlagHTTPProbeDeadline); deadline != "" {
+		opts.Deadline, err = ParseProbeDeadline(deadline, time.Now())
+		if err != nil {
+			xc.Out.Error("param.http.probe.deadline", err.Error())
+			xc.Out.State("exited",
+				ovars{
+					"exit.code": -1,
+				})
+			xc.Exit(-1)
+		}
+	}
+
 	opts.APISpecs = ctx.StringSlice(FlagHTTPProbeAPISpec)
 	apiSpecFiles, fileErrors := ValidateFiles(ctx.StringSlice(FlagHTTPProbeAPISpecFile))
 	if len(fileErrors) > 0 {
@@ -136,8 +470,9 @@ func GetHTTPProbeOptions(xc *app.ExecutionContext, ctx *cli.Context, doProbe boo
 		}
 	}
 	opts.APISpecFiles = apiSpecFiles
+	opts.APISpecCmds = ctx.StringSlice(FlagHTTPProbeAPISpecCmds)
 
-	if len(opts.APISpecs)+len(opts.APISpecFiles) > 0 {
+	if len(opts.APISpecs)+len(opts.APISpecFiles)+len(opts.APISpecCmds) > 0 {
 		opts.Do = true
 	}
 
@@ -170,6 +505,34 @@ func GetHTTPProbes(ctx *cli.Context) ([]config.HTTPProbeCmd, error) {
 	return httpProbeCmds, nil
 }
 
+func GetTCPProbeOptions(xc *app.ExecutionContext, ctx *cli.Context) config.TCPProbeOptions {
+	specs, err := ParseTCPProbeSpecs(ctx.StringSlice(FlagTCPProbe))
+	if err != nil {
+		xc.Out.Error("param.tcp.probe", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	return config.TCPProbeOptions{Specs: specs}
+}
+
+func GetExecProbeOptions(xc *app.ExecutionContext, ctx *cli.Context) config.ExecProbeOptions {
+	cmds, err := ParseExecProbeCmds(ctx.StringSlice(FlagExecProbe))
+	if err != nil {
+		xc.Out.Error("param.exec.probe", err.Error())
+		xc.Out.State("exited",
+			ovars{
+				"exit.code": -1,
+			})
+		xc.Exit(-1)
+	}
+
+	return config.ExecProbeOptions{Cmds: cmds}
+}
+
 func GetContinueAfter(ctx *cli.Context) (*config.ContinueAfter, error) {
 	info := &config.ContinueAfter{
 		Mode: config.CAMEnter,
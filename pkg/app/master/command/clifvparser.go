@@ -6,10 +6,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -617,7 +620,7 @@ func ParseHTTPProbes(values []string) ([]config.HTTPProbeCmd, error) {
 				return nil, fmt.Errorf("invalid HTTP probe command protocol: %+v", raw)
 			}
 
-			proto = strings.ToLower(parts[0])
+			proto = config.NormalizeProto(parts[0])
 
 			if parts[1] != "" && !isMethod(parts[1]) {
 				return nil, fmt.Errorf("invalid HTTP probe command method: %+v", raw)
@@ -672,17 +675,81 @@ func ParseHTTPProbesFile(filePath string) ([]config.HTTPProbeCmd, error) {
 			return nil, err
 		}
 
+		var cmds []config.HTTPProbeCmd
 		for _, cmd := range configs.Commands {
+			//the group fields are set only for the command group commands
+			cmd.Group = ""
+			cmd.GroupStage = ""
+			cmds = append(cmds, cmd)
+		}
+
+		groupNames := map[string]bool{}
+		for _, group := range configs.Groups {
+			if group.Name == "" || groupNames[group.Name] {
+				return nil, fmt.Errorf("invalid HTTP probe command group name: '%s'", group.Name)
+			}
+
+			groupNames[group.Name] = true
+			cmds = append(cmds, flattenCmdGroup(group)...)
+		}
+
+		//the preflight commands are returned with the regular commands (marked with the preflight stage)
+		for _, cmd := range configs.Preflight {
+			cmd.Group = ""
+			cmd.GroupStage = config.CmdStagePreflight
+			cmds = append(cmds, cmd)
+		}
+
+		for _, cmd := range cmds {
 			if cmd.Protocol != "" && !config.IsProto(cmd.Protocol) {
 				return nil, fmt.Errorf("invalid HTTP probe command protocol: %+v", cmd)
 			}
 
+			if cmd.GroupStage == config.CmdStagePreflight && len(cmd.DependsOn) > 0 {
+				return nil, fmt.Errorf("HTTP probe preflight command with dependencies: %+v", cmd)
+			}
+
+			if len(cmd.Exec) > 0 {
+				//the exec commands run in the target container (they have no HTTP call parameters)
+				if err := checkExecProbeCmd(cmd); err != nil {
+					return nil, fmt.Errorf("invalid HTTP probe exec command (%v): %+v", err, cmd)
+				}
+
+				probes = append(probes, cmd)
+				continue
+			}
+
+			cmd.Protocol = config.NormalizeProto(cmd.Protocol)
+
+			if len(cmd.ProtocolChain) > 0 {
+				if cmd.Protocol != "" {
+					return nil, fmt.Errorf("HTTP probe command with a protocol and a protocol chain: %+v", cmd)
+				}
+
+				chain, err := parseProtocolChain(cmd.ProtocolChain)
+				if err != nil {
+					return nil, fmt.Errorf("invalid HTTP probe command protocol chain (%v): %+v", err, cmd)
+				}
+
+				cmd.ProtocolChain = chain
+			}
+
 			if cmd.Method != "" && !isMethod(cmd.Method) {
 				return nil, fmt.Errorf("invalid HTTP probe command method: %+v", cmd)
 			}
 
 			if cmd.Method == "" {
 				cmd.Method = "GET"
+				switch strings.ToLower(cmd.Mode) {
+				case config.ProbeModeUpload:
+					cmd.Method = "POST"
+				case config.ProbeModeConnect:
+					cmd.Method = "CONNECT"
+				}
+
+				if cmd.Protocol == config.ProtoGraphQL || cmd.Protocol == config.ProtoGRPC {
+					cmd.Method = "POST"
+				}
 			}
 
 			cmd.Method = strings.ToUpper(cmd.Method)
@@ -695,6 +762,123 @@ func ParseHTTPProbesFile(filePath string) ([]config.HTTPProbeCmd, error) {
 				return nil, fmt.Errorf("invalid HTTP probe command port: %v", cmd)
 			}
 
+			for _, port := range cmd.Ports {
+				if port == 0 {
+					return nil, fmt.Errorf("invalid HTTP probe command ports: %+v", cmd)
+				}
+			}
+
+			//the deprecated 'port' field is the same as a single port in 'ports'
+			if cmd.Port != 0 {
+				cmd.Ports = foldHTTPProbeCmdPort(cmd.Port, cmd.Ports)
+				cmd.Port = 0
+			}
+
+			if cmd.BaseURL != "" {
+				if _, err := config.ParseProbeBaseURL(cmd.BaseURL); err != nil {
+					return nil, fmt.Errorf("invalid HTTP probe command base URL (%v): %+v", err, cmd)
+				}
+			}
+
+			if cmd.Mode != "" && !config.IsProbeMode(cmd.Mode) {
+				return nil, fmt.Errorf("invalid HTTP probe command mode: %+v", cmd)
+			}
+
+			cmd.Mode = strings.ToLower(cmd.Mode)
+
+			//the WebDAV MOVE calls relocate the target resource (there's no default destination for them)
+			if cmd.Mode == config.ProbeModeWebDAV && cmd.Method == "MOVE" && !hasProbeCmdHeader(cmd.Headers, "Destination") {
+				return nil, fmt.Errorf("HTTP probe WebDAV MOVE command without the Destination header: %+v", cmd)
+			}
+
+			if cmd.UnixSocket != "" {
+				if _, err := config.ParseProbeUnixSocket(cmd.UnixSocket); err != nil {
+					return nil, fmt.Errorf("invalid HTTP probe command Unix socket (%v): %+v", err, cmd)
+				}
+
+				//the Unix socket calls don't use the target address and port and they are HTTP only
+				if cmd.BaseURL != "" || cmd.Port != 0 || len(cmd.Ports) > 0 || cmd.FastCGI != nil || cmd.Crawl ||
+					cmd.Mode == config.ProbeModeConnect || cmd.Protocol == config.ProtoWS || cmd.Protocol == config.ProtoWSS {
+					return nil, fmt.Errorf("unsupported HTTP probe command options for the Unix socket: %+v", cmd)
+				}
+			}
+
+			for _, platform := range cmd.Platforms {
+				if !isPlatformCondition(platform) {
+					return nil, fmt.Errorf("invalid HTTP probe command platform condition: %+v", cmd)
+				}
+			}
+
+			if cmd.UploadSize < 0 || cmd.UploadChunkSize < 0 {
+				return nil, fmt.Errorf("invalid HTTP probe command upload parameters: %+v", cmd)
+			}
+
+			if cmd.KeepAliveRequests < 0 {
+				return nil, fmt.Errorf("invalid HTTP probe command keepalive requests: %+v", cmd)
+			}
+
+			if cmd.RepeatCount < 0 {
+				return nil, fmt.Errorf("invalid HTTP probe command repeat count: %+v", cmd)
+			}
+
+			for _, code := range cmd.ExpectStatus {
+				if !isHTTPStatusCode(code) {
+					return nil, fmt.Errorf("invalid HTTP probe command expected status code: %+v", cmd)
+				}
+			}
+
+			if cmd.ExpectBodyMatch != "" {
+				if _, err := regexp.Compile(cmd.ExpectBodyMatch); err != nil {
+					return nil, fmt.Errorf("invalid HTTP probe command expected body expression (%v): %+v", err, cmd)
+				}
+			}
+
+			if cmd.Weight < 0 {
+				return nil, fmt.Errorf("invalid HTTP probe command weight: %+v", cmd)
+			}
+
+			for _, val := range cmd.AcceptLanguages {
+				if strings.TrimSpace(val) == "" {
+					return nil, fmt.Errorf("invalid HTTP probe command accept language: %+v", cmd)
+				}
+			}
+
+			for _, val := range cmd.Ranges {
+				if !isByteRange(val) {
+					return nil, fmt.Errorf("invalid HTTP probe command range: %+v", cmd)
+				}
+			}
+
+			switch cmd.CachePolicy {
+			case "", config.CachePolicyCacheable, config.CachePolicyNoStore:
+			default:
+				return nil, fmt.Errorf("invalid HTTP probe command cache policy: %+v", cmd)
+			}
+
+			for _, val := range cmd.CacheDirectives {
+				if !isCacheDirective(val) {
+					return nil, fmt.Errorf("invalid HTTP probe command cache directive: %+v", cmd)
+				}
+			}
+
+			if (cmd.ClientCert == "") != (cmd.ClientKey == "") {
+				return nil, fmt.Errorf("invalid HTTP probe command client certificate (need both cert and key): %+v", cmd)
+			}
+
+			for _, tlsFile := range []string{cmd.ClientCert, cmd.ClientKey, cmd.CACert} {
+				if tlsFile == "" {
+					continue
+				}
+
+				if _, err := os.Stat(tlsFile); err != nil {
+					return nil, err
+				}
+			}
+
+			if cmd.MinCompressionRatio < 0 {
+				return nil, fmt.Errorf("invalid HTTP probe command compression ratio: %+v", cmd)
+			}
+
 			if cmd.BodyFile != "" {
 				bfFullPath, err := filepath.Abs(cmd.BodyFile)
 				if err != nil {
@@ -719,9 +903,187 @@ func ParseHTTPProbesFile(filePath string) ([]config.HTTPProbeCmd, error) {
 	return probes, nil
 }
 
+// hasProbeCmdHeader returns true if the probe command has the header ("name: value" header lines)
+func hasProbeCmdHeader(headers []string, name string) bool {
+	for _, hline := range headers {
+		if hname := strings.SplitN(hline, ":", 2)[0]; strings.EqualFold(strings.TrimSpace(hname), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// foldHTTPProbeCmdPort adds the (deprecated) command port to the command ports
+func foldHTTPProbeCmdPort(port int, ports []uint16) []uint16 {
+	for _, p := range ports {
+		if int(p) == port {
+			return ports
+		}
+	}
+
+	return append(ports, uint16(port))
+}
+
+// checkExecProbeCmd checks the exec probe command
+// (only the command flow, platform and expected output fields can be used with the exec commands)
+func checkExecProbeCmd(cmd config.HTTPProbeCmd) error {
+	if strings.TrimSpace(cmd.Exec[0]) == "" {
+		return fmt.Errorf("empty command")
+	}
+
+	if cmd.Method != "" || cmd.Resource != "" || cmd.Protocol != "" || len(cmd.ProtocolChain) > 0 ||
+		cmd.Port != 0 || len(cmd.Ports) > 0 || cmd.BaseURL != "" || cmd.UnixSocket != "" ||
+		cmd.Mode != "" || len(cmd.Headers) > 0 || cmd.Body != "" || cmd.BodyFile != "" ||
+		cmd.Crawl || cmd.FastCGI != nil || len(cmd.ExpectStatus) > 0 ||
+		len(cmd.Capture) > 0 || len(cmd.Assert) > 0 {
+		return fmt.Errorf("unsupported HTTP call options")
+	}
+
+	for _, platform := range cmd.Platforms {
+		if !isPlatformCondition(platform) {
+			return fmt.Errorf("invalid platform condition '%s'", platform)
+		}
+	}
+
+	if cmd.ExpectBodyMatch != "" {
+		if _, err := regexp.Compile(cmd.ExpectBodyMatch); err != nil {
+			return fmt.Errorf("invalid expected output expression - %v", err)
+		}
+	}
+
+	return nil
+}
+
+// flattenCmdGroup returns the group commands in the execution order (setup, commands, teardown)
+func flattenCmdGroup(group config.HTTPProbeCmdGroup) []config.HTTPProbeCmd {
+	var cmds []config.HTTPProbeCmd
+	if group.Setup != nil {
+		cmd := *group.Setup
+		cmd.Group = group.Name
+		cmd.GroupStage = config.CmdGroupStageSetup
+		cmds = append(cmds, cmd)
+	}
+
+	for _, cmd := range group.Commands {
+		cmd.Group = group.Name
+		cmd.GroupStage = ""
+		cmds = append(cmds, cmd)
+	}
+
+	if group.Teardown != nil {
+		cmd := *group.Teardown
+		cmd.Group = group.Name
+		cmd.GroupStage = config.CmdGroupStageTeardown
+		cmds = append(cmds, cmd)
+	}
+
+	return cmds
+}
+
+// ParseProbeStartWait parses the probe start wait value
+// (a duration, e.g., 15s, or a number of seconds)
+func ParseProbeStartWait(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	wait, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, serr := strconv.Atoi(value)
+		if serr != nil {
+			return 0, fmt.Errorf("invalid start wait - %s (expected a duration like 15s or a number of seconds)", value)
+		}
+
+		wait = time.Duration(seconds) * time.Second
+	}
+
+	if wait < 0 {
+		return 0, fmt.Errorf("invalid start wait - %s (negative wait)", value)
+	}
+
+	return wait, nil
+}
+
+// ParseProbeDeadline parses the probe deadline value
+// (an RFC3339 timestamp or a local time of day, e.g., 14:30 or 14:30:15, for the current day)
+func ParseProbeDeadline(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return deadline, nil
+	}
+
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		tod, err := time.ParseInLocation(layout, value, now.Location())
+		if err != nil {
+			continue
+		}
+
+		return time.Date(now.Year(), now.Month(), now.Day(),
+			tod.Hour(), tod.Minute(), tod.Second(), 0, now.Location()), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid deadline - %s (expected an RFC3339 timestamp or a time of day like 14:30)", value)
+}
+
+// isPlatformCondition checks the "os" or "os/arch" platform condition format
+func isPlatformCondition(value string) bool {
+	parts := strings.Split(value, "/")
+	if len(parts) > 2 {
+		return false
+	}
+
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+var byteRangeRE = regexp.MustCompile(`^(\d+-\d*|-\d+)$`)
+
+func isByteRange(value string) bool {
+	return byteRangeRE.MatchString(strings.TrimSpace(value))
+}
+
+// parseProtocolChain normalizes the protocol chain (the HTTP protocols only)
+// (e.g., "h2c" is an alias for "http2c")
+func parseProtocolChain(values []string) ([]string, error) {
+	var chain []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		proto := config.NormalizeProto(value)
+
+		switch proto {
+		case config.ProtoHTTP, config.ProtoHTTPS, config.ProtoHTTP2, config.ProtoHTTP2C:
+		default:
+			return nil, fmt.Errorf("unsupported protocol '%s'", value)
+		}
+
+		if seen[proto] {
+			return nil, fmt.Errorf("duplicate protocol '%s'", value)
+		}
+
+		seen[proto] = true
+		chain = append(chain, proto)
+	}
+
+	return chain, nil
+}
+
+var cacheDirectiveRE = regexp.MustCompile(`^[A-Za-z][A-Za-z-]*(=[^,]+|[<>]=\d+)?$`)
+
+func isCacheDirective(value string) bool {
+	return cacheDirectiveRE.MatchString(strings.TrimSpace(value))
+}
+
 func isMethod(value string) bool {
 	switch strings.ToUpper(value) {
-	case "HEAD", "GET", "POST", "PUT", "DELETE", "PATCH":
+	case "HEAD", "GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "CONNECT",
+		"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK":
 		return true
 	default:
 		return false
@@ -764,6 +1126,246 @@ func ParseHTTPProbesPorts(portList string) ([]uint16, error) {
 	return ports, nil
 }
 
+// ParseDNSServer validates the DNS server address (host[:port])
+// and returns it with the default DNS port (53) if no port is provided
+func ParseDNSServer(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		//no port (IPv6 addresses may be bracketed)
+		host = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		port = "53"
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("invalid DNS server address: %s", value)
+	}
+
+	if net.ParseIP(host) == nil {
+		return "", fmt.Errorf("invalid DNS server IP address: %s", value)
+	}
+
+	pnum, err := strconv.Atoi(port)
+	if err != nil || !isPortNum(pnum) {
+		return "", fmt.Errorf("invalid DNS server port: %s", value)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// ParseHTTPProbeCredentialsFile loads the fallback credential sets (JSON list)
+func ParseHTTPProbeCredentialsFile(filePath string) ([]config.HTTPProbeCredential, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []config.HTTPProbeCredential
+	if err := json.Unmarshal(fileData, &creds); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for idx := range creds {
+		if creds[idx].Username == "" && creds[idx].Token == "" {
+			return nil, fmt.Errorf("no username or token in the HTTP probe credential set %d", idx+1)
+		}
+
+		if creds[idx].Name == "" {
+			creds[idx].Name = fmt.Sprintf("credential.%d", idx+1)
+		}
+
+		if names[creds[idx].Name] {
+			return nil, fmt.Errorf("duplicate HTTP probe credential set name: '%s'", creds[idx].Name)
+		}
+
+		names[creds[idx].Name] = true
+	}
+
+	return creds, nil
+}
+
+// ParseHTTPProbeExpectStatus parses the expected HTTP probe call status codes
+// (each value can have multiple comma separated codes)
+func ParseHTTPProbeExpectStatus(values []string) ([]int, error) {
+	var codes []int
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			code, err := strconv.Atoi(part)
+			if err != nil || !isHTTPStatusCode(code) {
+				return nil, fmt.Errorf("invalid status code - '%s'", part)
+			}
+
+			codes = append(codes, code)
+		}
+	}
+
+	return codes, nil
+}
+
+func isHTTPStatusCode(code int) bool {
+	return code >= 100 && code <= 599
+}
+
+// ParseHTTPProbeSeverityRules parses the probe result severity rules ('METRIC[>|>=|<|<=]THRESHOLD:SEVERITY')
+func ParseHTTPProbeSeverityRules(values []string) ([]config.HTTPProbeSeverityRule, error) {
+	var rules []config.HTTPProbeSeverityRule
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		cond, severity, found := strings.Cut(value, ":")
+		if !found {
+			return nil, fmt.Errorf("no severity in the HTTP probe severity rule: '%s'", value)
+		}
+
+		rule := config.HTTPProbeSeverityRule{
+			Rule:     value,
+			Severity: strings.ToLower(strings.TrimSpace(severity)),
+		}
+
+		switch rule.Severity {
+		case config.ProbeSeverityInfo, config.ProbeSeverityWarn, config.ProbeSeverityError:
+		default:
+			return nil, fmt.Errorf("unknown severity in the HTTP probe severity rule: '%s'", value)
+		}
+
+		opIdx := strings.IndexAny(cond, "<>")
+		if opIdx == -1 {
+			return nil, fmt.Errorf("no condition operator in the HTTP probe severity rule: '%s'", value)
+		}
+
+		rule.Metric = strings.ToLower(strings.TrimSpace(cond[:opIdx]))
+		rule.Op = cond[opIdx : opIdx+1]
+		threshold := cond[opIdx+1:]
+		if strings.HasPrefix(threshold, "=") {
+			rule.Op += "="
+			threshold = threshold[1:]
+		}
+
+		switch rule.Metric {
+		case config.ProbeMetricCalls,
+			config.ProbeMetricFailures,
+			config.ProbeMetricFailureRate,
+			config.ProbeMetricSuccessRate,
+			config.ProbeMetricNotFoundRoutes,
+			config.ProbeMetricFailedCommands,
+			config.ProbeMetricFailedCommandRate:
+		default:
+			return nil, fmt.Errorf("unknown metric in the HTTP probe severity rule: '%s'", value)
+		}
+
+		var err error
+		threshold = strings.TrimSuffix(strings.TrimSpace(threshold), "%")
+		if rule.Threshold, err = strconv.ParseFloat(threshold, 64); err != nil {
+			return nil, fmt.Errorf("malformed threshold in the HTTP probe severity rule: '%s'", value)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// ParseTCPProbeSpecs parses the TCP probe specs ('PORT[:SEND[:EXPECT]]')
+// The data to send and the expected response prefix support the Go string escapes (e.g., '\r\n' or '\x00').
+func ParseTCPProbeSpecs(values []string) ([]config.TCPProbeSpec, error) {
+	var specs []config.TCPProbeSpec
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		parts := strings.SplitN(value, ":", 3)
+		port, err := strconv.Atoi(parts[0])
+		if err != nil || !isPortNum(port) {
+			return nil, fmt.Errorf("malformed port in the TCP probe spec: '%s'", value)
+		}
+
+		spec := config.TCPProbeSpec{Port: uint16(port)}
+		if len(parts) > 1 {
+			if spec.Send, err = unescapeTCPProbeData(parts[1]); err != nil {
+				return nil, fmt.Errorf("malformed data in the TCP probe spec: '%s'", value)
+			}
+		}
+
+		if len(parts) > 2 {
+			if spec.Expect, err = unescapeTCPProbeData(parts[2]); err != nil {
+				return nil, fmt.Errorf("malformed expected response in the TCP probe spec: '%s'", value)
+			}
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// ParseExecProbeCmds parses the exec probe commands
+// (the command arguments are split like in a shell, e.g., 'app jobs run --name "daily report"')
+func ParseExecProbeCmds(values []string) ([][]string, error) {
+	var cmds [][]string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		args, err := shlex.Split(value)
+		if err != nil {
+			return nil, fmt.Errorf("malformed exec probe command (%v): '%s'", err, value)
+		}
+
+		if len(args) == 0 {
+			return nil, fmt.Errorf("empty exec probe command: '%s'", value)
+		}
+
+		cmds = append(cmds, args)
+	}
+
+	return cmds, nil
+}
+
+// unescapeTCPProbeData expands the Go string escapes in the TCP probe data
+// (the unescaped double quotes are kept as-is and the escaped ones are unescaped)
+func unescapeTCPProbeData(value string) (string, error) {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			quoted.WriteByte(value[i])
+			if i+1 < len(value) {
+				i++
+				quoted.WriteByte(value[i])
+			}
+		case '"':
+			quoted.WriteString(`\"`)
+		default:
+			quoted.WriteByte(value[i])
+		}
+	}
+	quoted.WriteByte('"')
+
+	return strconv.Unquote(quoted.String())
+}
+
 func ParseHTTPProbeExecFile(filePath string) ([]string, error) {
 	var appCalls []string
 
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseHTTPProbesFileCmdPort(t *testing.T) {
+	tt := []struct {
+		data     string
+		expected []uint16
+	}{
+		{data: `{"commands":[{"resource":"/","port":8080}]}`, expected: []uint16{8080}},
+		{data: `{"commands":[{"resource":"/","port":8080,"ports":[9000]}]}`, expected: []uint16{9000, 8080}},
+		{data: `{"commands":[{"resource":"/","port":9000,"ports":[9000]}]}`, expected: []uint16{9000}},
+		{data: `{"commands":[{"resource":"/","ports":[9000]}]}`, expected: []uint16{9000}},
+		{data: `{"commands":[{"resource":"/"}]}`},
+	}
+
+	for _, test := range tt {
+		cmdsFile := filepath.Join(t.TempDir(), "probes.json")
+		if err := os.WriteFile(cmdsFile, []byte(test.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmds, err := ParseHTTPProbesFile(cmdsFile)
+		if err != nil {
+			t.Fatalf("%s: unexpected error - %v", test.data, err)
+		}
+
+		if len(cmds) != 1 {
+			t.Fatalf("%s: got %d commands expected 1", test.data, len(cmds))
+		}
+
+		if cmds[0].Port != 0 {
+			t.Errorf("%s: got port %d expected it in the ports", test.data, cmds[0].Port)
+		}
+
+		ports := cmds[0].Ports
+		if len(ports) != len(test.expected) {
+			t.Errorf("%s: got ports %v expected %v", test.data, ports, test.expected)
+			continue
+		}
+
+		for i := range ports {
+			if ports[i] != test.expected[i] {
+				t.Errorf("%s: got ports %v expected %v", test.data, ports, test.expected)
+				break
+			}
+		}
+	}
+
+	cmdsFile := filepath.Join(t.TempDir(), "probes.json")
+	if err := os.WriteFile(cmdsFile, []byte(`{"commands":[{"resource":"/","port":70000}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseHTTPProbesFile(cmdsFile); err == nil {
+		t.Errorf("invalid port: got no error")
+	}
+}
+
+func TestParseHTTPProbesFileWebDAVMove(t *testing.T) {
+	tt := []struct {
+		data string
+		ok   bool
+	}{
+		{data: `{"commands":[{"method":"MOVE","resource":"/files/a.txt","mode":"webdav","headers":["Destination: /files/b.txt"]}]}`, ok: true},
+		{data: `{"commands":[{"method":"move","resource":"/files/a.txt","mode":"WebDAV","headers":["destination: /files/b.txt"]}]}`, ok: true},
+		{data: `{"commands":[{"method":"COPY","resource":"/files/a.txt","mode":"webdav"}]}`, ok: true},
+		{data: `{"commands":[{"method":"MOVE","resource":"/files/a.txt","mode":"webdav"}]}`},
+		{data: `{"commands":[{"method":"move","resource":"/files/a.txt","mode":"webdav","headers":["Overwrite: T"]}]}`},
+	}
+
+	for _, test := range tt {
+		cmdsFile := filepath.Join(t.TempDir(), "probes.json")
+		if err := os.WriteFile(cmdsFile, []byte(test.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := ParseHTTPProbesFile(cmdsFile)
+		if test.ok && err != nil {
+			t.Errorf("%s: unexpected error - %v", test.data, err)
+		}
+
+		if !test.ok && err == nil {
+			t.Errorf("%s: got no error for the MOVE command without a destination", test.data)
+		}
+	}
+}
+
+func TestParseTCPProbeSpecsData(t *testing.T) {
+	tt := []struct {
+		spec   string
+		send   string
+		expect string
+	}{
+		{spec: `6379:PING\r\n:+PONG`, send: "PING\r\n", expect: "+PONG"},
+		{spec: `9000:\x00\x01`, send: "\x00\x01"},
+		{spec: `9000:say "hi"`, send: `say "hi"`},
+		{spec: `9000:say \"hi\":"ok"`, send: `say "hi"`, expect: `"ok"`},
+		{spec: `9000:C:\\data`, send: `C`, expect: `\data`},
+	}
+
+	for _, test := range tt {
+		specs, err := ParseTCPProbeSpecs([]string{test.spec})
+		if err != nil {
+			t.Fatalf("%s: unexpected error - %v", test.spec, err)
+		}
+
+		if len(specs) != 1 {
+			t.Fatalf("%s: got %d specs expected 1", test.spec, len(specs))
+		}
+
+		if specs[0].Send != test.send || specs[0].Expect != test.expect {
+			t.Errorf("%s: got send=%q expect=%q expected send=%q expect=%q",
+				test.spec, specs[0].Send, specs[0].Expect, test.send, test.expect)
+		}
+	}
+
+	if _, err := ParseTCPProbeSpecs([]string{`9000:bad\`}); err == nil {
+		t.Errorf("trailing backslash: got no error")
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/c-bata/go-prompt"
@@ -267,6 +269,39 @@ func RunHostExecProbes(printState bool, xc *app.ExecutionContext, hostExecProbes
 	}
 }
 
+// ProbesDoneChan returns a channel closed when all probes are done
+// (the nil channels are ignored, e.g., when one of the probes is disabled)
+func ProbesDoneChan(doneChans ...<-chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for _, ch := range doneChans {
+			if ch != nil {
+				<-ch
+			}
+		}
+
+		close(done)
+	}()
+
+	return done
+}
+
+// ProbeInterruptContext returns the probe context canceled when the command is interrupted (SIGINT or SIGTERM),
+// so the in-flight probe calls are aborted (the signal notifications stop when the probe is done)
+func ProbeInterruptContext(probeDone <-chan struct{}) context.Context {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-probeDone:
+		case <-ctx.Done():
+		}
+
+		stop()
+	}()
+
+	return ctx
+}
+
 func exeAppCall(appCall string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Second)
 	defer cancel()
@@ -74,7 +74,8 @@ func OnCommand(
 	probe, err := http.NewEndpointProbe(xc, targetEndpoint, targetPorts, httpProbeOpts, printState)
 	xc.FailOn(err)
 
-	probe.Start()
+	//interrupting the command cancels the probe (the probe outputs are still saved)
+	probe.StartContext(command.ProbeInterruptContext(probe.DoneChan()))
 
 	xc.Out.Prompt("waiting for the HTTP probe to finish")
 	<-probe.DoneChan()
@@ -83,10 +84,22 @@ func OnCommand(
 			"message": "HTTP probe is done",
 		})
 
-	if probe != nil && probe.CallCount > 0 && probe.OkCount == 0 {
+	if summary := probe.Summary(); summary.CallCount > 0 && summary.NoSuccessfulCalls {
 		xc.Out.Error("probe.error", "no.successful.calls")
 	}
 
+	//the severity rules, the result assertions and the failed preflight commands (aborting the probe)
+	//can fail the probe command (after the report is saved)
+	resultError := probe.ResultError()
+	if err := probe.Error(); err != nil {
+		resultError = err.Error()
+	}
+
+	if resultError != "" {
+		xc.Out.Error("probe.error", resultError)
+		cmdReport.Error = "probe." + resultError
+	}
+
 	xc.Out.State(cmd.StateCompleted)
 	cmdReport.State = cmd.StateCompleted
 	xc.Out.State(cmd.StateDone)
@@ -101,4 +114,9 @@ func OnCommand(
 				"file": cmdReport.ReportLocation(),
 			})
 	}
+
+	if resultError != "" {
+		xc.Out.State("exited", ovars{"exit.code": -1})
+		xc.Exit(-1)
+	}
 }
@@ -30,11 +30,76 @@ var CommandFlagSuggestions = &command.FlagSuggestions{
 		{Text: command.FullFlagName(command.FlagHTTPMaxConcurrentCrawlers), Description: command.FlagHTTPMaxConcurrentCrawlersUsage},
 		{Text: command.FullFlagName(command.FlagHTTPProbeAPISpec), Description: command.FlagHTTPProbeAPISpecUsage},
 		{Text: command.FullFlagName(command.FlagHTTPProbeAPISpecFile), Description: command.FlagHTTPProbeAPISpecFileUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeDNSServer), Description: command.FlagHTTPProbeDNSServerUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCSVOutput), Description: command.FlagHTTPProbeCSVOutputUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCassette), Description: command.FlagHTTPProbeCassetteUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCassetteMode), Description: command.FlagHTTPProbeCassetteModeUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRetryBackoffReset), Description: command.FlagHTTPProbeRetryBackoffResetUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeAllAddresses), Description: command.FlagHTTPProbeAllAddressesUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRoutesEndpoint), Description: command.FlagHTTPProbeRoutesEndpointUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRoutesDestructive), Description: command.FlagHTTPProbeRoutesDestructiveUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRequestID), Description: command.FlagHTTPProbeRequestIDUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCPUThrottle), Description: command.FlagHTTPProbeCPUThrottleUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCPUThrottleThreshold), Description: command.FlagHTTPProbeCPUThrottleThresholdUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRetryOn), Description: command.FlagHTTPProbeRetryOnUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeConcurrency), Description: command.FlagHTTPProbeConcurrencyUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbePcapOutput), Description: command.FlagHTTPProbePcapOutputUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeDeadline), Description: command.FlagHTTPProbeDeadlineUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRetryBudget), Description: command.FlagHTTPProbeRetryBudgetUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeFailOnDuplicates), Description: command.FlagHTTPProbeFailOnDuplicatesUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeMetricsInterval), Description: command.FlagHTTPProbeMetricsIntervalUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRequireExplicitPorts), Description: command.FlagHTTPProbeRequireExplicitPortsUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeEventLog), Description: command.FlagHTTPProbeEventLogUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeEventLogMaxMessages), Description: command.FlagHTTPProbeEventLogMaxMessagesUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCredentialsFile), Description: command.FlagHTTPProbeCredentialsFileUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeTLSServerName), Description: command.FlagHTTPProbeTLSServerNameUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeDataSeed), Description: command.FlagHTTPProbeDataSeedUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeScreenshotOnFailure), Description: command.FlagHTTPProbeScreenshotOnFailureUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeScreenshotBrowser), Description: command.FlagHTTPProbeScreenshotBrowserUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeSeedExec), Description: command.FlagHTTPProbeSeedExecUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeSeedEndpoint), Description: command.FlagHTTPProbeSeedEndpointUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeSeverityRule), Description: command.FlagHTTPProbeSeverityRuleUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeMaxResponseHeaderBytes), Description: command.FlagHTTPProbeMaxResponseHeaderBytesUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeResultAssert), Description: command.FlagHTTPProbeResultAssertUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbePortReadyTimeout), Description: command.FlagHTTPProbePortReadyTimeoutUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeExpectStatus), Description: command.FlagHTTPProbeExpectStatusUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeAPISpecCmds), Description: command.FlagHTTPProbeAPISpecCmdsUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeReport), Description: command.FlagHTTPProbeReportUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRetryMaxWait), Description: command.FlagHTTPProbeRetryMaxWaitUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeMaxRedirects), Description: command.FlagHTTPProbeMaxRedirectsUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeNoCookieJar), Description: command.FlagHTTPProbeNoCookieJarUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCallTimeout), Description: command.FlagHTTPProbeCallTimeoutUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeConnectTimeout), Description: command.FlagHTTPProbeConnectTimeoutUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeProxy), Description: command.FlagHTTPProbeProxyUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeHost), Description: command.FlagHTTPProbeHostUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeUseHealthcheck), Description: command.FlagHTTPProbeUseHealthcheckUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeVerifyTLS), Description: command.FlagHTTPProbeVerifyTLSUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeCACert), Description: command.FlagHTTPProbeCACertUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRepeat), Description: command.FlagHTTPProbeRepeatUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeDryRun), Description: command.FlagHTTPProbeDryRunUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeVerbose), Description: command.FlagHTTPProbeVerboseUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeUserAgent), Description: command.FlagHTTPProbeUserAgentUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeHeader), Description: command.FlagHTTPProbeHeaderUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbePreflightCmd), Description: command.FlagHTTPProbePreflightCmdUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbePreflightAbort), Description: command.FlagHTTPProbePreflightAbortUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeMaxTime), Description: command.FlagHTTPProbeMaxTimeUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeContainerIP), Description: command.FlagHTTPProbeContainerIPUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbePortReadyInterval), Description: command.FlagHTTPProbePortReadyIntervalUsage},
+		{Text: command.FullFlagName(command.FlagHTTPProbeRateLimit), Description: command.FlagHTTPProbeRateLimitUsage},
 	},
 	Values: map[string]command.CompleteValue{
-		command.FullFlagName(command.FlagHTTPProbeCmdFile):     command.CompleteFile,
-		command.FullFlagName(command.FlagHTTPProbeFull):        command.CompleteBool,
-		command.FullFlagName(command.FlagHTTPProbeCrawl):       command.CompleteTBool,
-		command.FullFlagName(command.FlagHTTPProbeAPISpecFile): command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeCmdFile):           command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeFull):              command.CompleteBool,
+		command.FullFlagName(command.FlagHTTPProbeCrawl):             command.CompleteTBool,
+		command.FullFlagName(command.FlagHTTPProbeAPISpecFile):       command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeCSVOutput):         command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeCassette):          command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeRetryBackoffReset): command.CompleteTBool,
+		command.FullFlagName(command.FlagHTTPProbeAllAddresses):      command.CompleteBool,
+		command.FullFlagName(command.FlagHTTPProbeRoutesDestructive): command.CompleteBool,
+		command.FullFlagName(command.FlagHTTPProbeCPUThrottle):       command.CompleteBool,
+		command.FullFlagName(command.FlagHTTPProbeNoCookieJar):       command.CompleteBool,
+		command.FullFlagName(command.FlagHTTPProbeCACert):            command.CompleteFile,
+		command.FullFlagName(command.FlagHTTPProbeContainerIP):       command.CompleteBool,
 	},
 }
@@ -48,6 +48,8 @@ var CLI = &cli.Command{
 		command.Cflag(command.FlagPublishExposedPorts),
 		command.Cflag(command.FlagHostExec),
 		command.Cflag(command.FlagHostExecFile),
+		command.Cflag(command.FlagTCPProbe),
+		command.Cflag(command.FlagExecProbe),
 		//command.Cflag(command.FlagKeepPerms),
 		command.Cflag(command.FlagRunTargetAsUser),
 		command.Cflag(command.FlagShowContainerLogs),
@@ -136,6 +138,8 @@ var CLI = &cli.Command{
 		doPublishExposedPorts := ctx.Bool(command.FlagPublishExposedPorts)
 
 		httpProbeOpts := command.GetHTTPProbeOptions(xc, ctx, false)
+		tcpProbeOpts := command.GetTCPProbeOptions(xc, ctx)
+		execProbeOpts := command.GetExecProbeOptions(xc, ctx)
 
 		continueAfter, err := command.GetContinueAfter(ctx)
 		if err != nil {
@@ -147,7 +151,7 @@ var CLI = &cli.Command{
 			xc.Exit(-1)
 		}
 
-		if !httpProbeOpts.Do && continueAfter.Mode == "probe" {
+		if !httpProbeOpts.Do && len(tcpProbeOpts.Specs) == 0 && len(execProbeOpts.Cmds) == 0 && continueAfter.Mode == "probe" {
 			continueAfter.Mode = "enter"
 			xc.Out.Info("enter",
 				ovars{
@@ -295,6 +299,8 @@ var CLI = &cli.Command{
 			doShowPullLogs,
 			crOpts,
 			httpProbeOpts,
+			tcpProbeOpts,
+			execProbeOpts,
 			portBindings,
 			doPublishExposedPorts,
 			hostExecProbes,
@@ -16,7 +16,10 @@ import (
 	"github.com/slimtoolkit/slim/pkg/app/master/config"
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/image"
+	probes "github.com/slimtoolkit/slim/pkg/app/master/probe"
+	execprobe "github.com/slimtoolkit/slim/pkg/app/master/probe/exec"
 	"github.com/slimtoolkit/slim/pkg/app/master/probe/http"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe/tcp"
 	"github.com/slimtoolkit/slim/pkg/app/master/version"
 	cmd "github.com/slimtoolkit/slim/pkg/command"
 	"github.com/slimtoolkit/slim/pkg/docker/dockerclient"
@@ -52,6 +55,8 @@ func OnCommand(
 	doShowPullLogs bool,
 	crOpts *config.ContainerRunOptions,
 	httpProbeOpts config.HTTPProbeOptions,
+	tcpProbeOpts config.TCPProbeOptions,
+	execProbeOpts config.ExecProbeOptions,
 	portBindings map[docker.Port][]docker.PortBinding,
 	doPublishExposedPorts bool,
 	hostExecProbes []string,
@@ -311,7 +316,7 @@ func OnCommand(
 
 	logger.Info("watching container monitor...")
 
-	if config.CAMProbe == continueAfter.Mode {
+	if config.CAMProbe == continueAfter.Mode && len(tcpProbeOpts.Specs) == 0 {
 		httpProbeOpts.Do = true
 	}
 
@@ -336,10 +341,46 @@ func OnCommand(
 			xc.Exit(-1)
 		}
 
-		probe.Start()
+		//interrupting the command cancels the probe (the probe outputs are still saved)
+		probe.StartContext(command.ProbeInterruptContext(probe.DoneChan()))
 		continueAfter.ContinueChan = probe.DoneChan()
 	}
 
+	//the TCP and exec probes run next to the HTTP probe
+	var targetProbes []probes.Probe
+	if len(tcpProbeOpts.Specs) > 0 {
+		tcpProbe, err := tcp.NewContainerProbe(xc, containerInspector, tcpProbeOpts, printState)
+		errutil.FailOn(err)
+
+		if len(tcpProbe.Ports()) == 0 {
+			xc.Out.State("tcp.probe.error",
+				ovars{
+					"error":   "NO EXPOSED PORTS",
+					"message": "expose the TCP probe ports with --expose or remove the --tcp-probe flags",
+				})
+
+			logger.Info("shutting down 'fat' container...")
+			containerInspector.FinishMonitoring()
+			_ = containerInspector.ShutdownContainer(false)
+
+			xc.Out.State("exited", ovars{"exit.code": -1})
+			xc.Exit(-1)
+		}
+
+		targetProbes = append(targetProbes, tcpProbe)
+	}
+
+	if len(execProbeOpts.Cmds) > 0 {
+		execProbe, err := execprobe.NewContainerProbe(xc, containerInspector, execProbeOpts, printState)
+		errutil.FailOn(err)
+
+		targetProbes = append(targetProbes, execProbe)
+	}
+
+	if len(targetProbes) > 0 {
+		continueAfter.ContinueChan = command.ProbesDoneChan(continueAfter.ContinueChan, probes.StartAll(targetProbes...))
+	}
+
 	continueAfterMsg := "provide the expected input to allow the container inspector to continue its execution"
 	switch continueAfter.Mode {
 	case config.CAMTimeout:
@@ -438,8 +479,16 @@ func OnCommand(
 					"message": "HTTP probe is done",
 				})
 
-			if probe != nil && probe.CallCount > 0 && probe.OkCount == 0 && httpProbeOpts.ExitOnFailure {
-				xc.Out.Error("probe.error", "no.successful.calls")
+			if probe != nil && probe.Error() != nil {
+				xc.Out.Error("probe.error", probe.Error().Error())
+
+				containerInspector.ShowContainerLogs()
+				xc.Out.State("exited", ovars{"exit.code": -1})
+				xc.Exit(-1)
+			}
+
+			if probe != nil && probe.ResultError() != "" {
+				xc.Out.Error("probe.error", probe.ResultError())
 
 				containerInspector.ShowContainerLogs()
 				xc.Out.State("exited", ovars{"exit.code": -1})
@@ -2,6 +2,9 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -95,6 +98,7 @@ type ImageNewInstructions struct {
 	RemoveVolumes      map[string]struct{}
 	RemoveExposedPorts map[docker.Port]struct{}
 	RemoveLabels       map[string]struct{}
+	RemoveHealthcheck  bool
 }
 
 // ContainerBuildOptions provides the options to use when
@@ -141,16 +145,243 @@ const (
 	ProtoHTTP2C = "http2c"
 	ProtoWS     = "ws"
 	ProtoWSS    = "wss"
+	//GraphQL requests (using the HTTP transport)
+	ProtoGraphQL = "graphql"
+	//gRPC calls (using the HTTP/2 cleartext transport)
+	ProtoGRPC = "grpc"
 )
 
+// protocol aliases (the ALPN protocol IDs)
+var protoAliases = map[string]string{
+	"http/1.1": ProtoHTTP,
+	"h2":       ProtoHTTP2,
+	"h2c":      ProtoHTTP2C,
+}
+
+// NormalizeProto returns the lowercase protocol name for the protocol names and their aliases
+func NormalizeProto(value string) string {
+	proto := strings.ToLower(strings.TrimSpace(value))
+	if alias, ok := protoAliases[proto]; ok {
+		return alias
+	}
+
+	return proto
+}
+
 func IsProto(value string) bool {
-	switch strings.ToLower(value) {
+	switch NormalizeProto(value) {
 	case ProtoHTTP,
 		ProtoHTTPS,
 		ProtoHTTP2,
 		ProtoHTTP2C,
 		ProtoWS,
-		ProtoWSS:
+		ProtoWSS,
+		ProtoGraphQL,
+		ProtoGRPC:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseProbeBaseURL parses and validates the probe command base URL
+// (an absolute http, https, ws or wss URL without a query or a fragment)
+func ParseProbeBaseURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case ProtoHTTP, ProtoHTTPS, ProtoWS, ProtoWSS:
+	default:
+		return nil, fmt.Errorf("unsupported base URL scheme: '%s'", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("no base URL host: '%s'", value)
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("base URL with a query or a fragment: '%s'", value)
+	}
+
+	return u, nil
+}
+
+// ParseProbeHost parses and validates the probe target host
+// (a host name or an IP address without a port, the IPv6 addresses can be bracketed)
+func ParseProbeHost(value string) (string, error) {
+	host := strings.TrimSpace(value)
+	if host == "" || strings.ContainsAny(host, "/?#@ ") {
+		return "", fmt.Errorf("malformed host: '%s'", value)
+	}
+
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return "", fmt.Errorf("host with a port: '%s'", value)
+	}
+
+	host = strings.Trim(host, "[]")
+	if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+		return "", fmt.Errorf("malformed IPv6 address: '%s'", value)
+	}
+
+	return host, nil
+}
+
+// ParseProbeProxy parses and validates the probe proxy URL
+// (an http, https or socks5 URL with a proxy host)
+func ParseProbeProxy(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case ProtoHTTP, ProtoHTTPS, "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy URL scheme: '%s'", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("no proxy URL host: '%s'", value)
+	}
+
+	return u, nil
+}
+
+// ParseProbeUnixSocket parses and validates the probe command Unix socket
+// (an absolute socket path or a 'unix://' URL with an absolute socket path)
+func ParseProbeUnixSocket(value string) (string, error) {
+	path := strings.TrimPrefix(value, "unix://")
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("not an absolute Unix socket path: '%s'", value)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// ParseProbeSeedEndpoint parses and validates the probe seed endpoint
+// ('[METHOD ]RESOURCE' where the resource is a target path or an absolute http/https URL)
+func ParseProbeSeedEndpoint(value string) (method, resource string, err error) {
+	method = "POST"
+	resource = strings.TrimSpace(value)
+	if parts := strings.Fields(resource); len(parts) == 2 {
+		method = strings.ToUpper(parts[0])
+		resource = parts[1]
+	} else if len(parts) != 1 {
+		return "", "", fmt.Errorf("malformed seed endpoint: '%s'", value)
+	}
+
+	if strings.HasPrefix(resource, "/") {
+		return method, resource, nil
+	}
+
+	u, err := url.Parse(resource)
+	if err != nil {
+		return "", "", err
+	}
+
+	if (u.Scheme != ProtoHTTP && u.Scheme != ProtoHTTPS) || u.Hostname() == "" {
+		return "", "", fmt.Errorf("seed endpoint is not a target path or an http/https URL: '%s'", value)
+	}
+
+	return method, resource, nil
+}
+
+const (
+	// ProbeModeETag runs a conditional (If-None-Match) follow up call
+	// using the ETag from the first call expecting a 304 response
+	ProbeModeETag = "etag"
+	// ProbeModeWebDAV fills in the conventional WebDAV headers and bodies
+	// and validates the responses using the expected WebDAV status codes
+	ProbeModeWebDAV = "webdav"
+	// ProbeModeUpload streams generated data (in chunks)
+	// as the request body and checks the upload response
+	ProbeModeUpload = "upload"
+	// ProbeModeCompression fetches the resource with and without compression
+	// and checks the compression ratio
+	ProbeModeCompression = "compression"
+	// ProbeModeSecurityHeaders checks the security headers in the responses
+	ProbeModeSecurityHeaders = "security-headers"
+	// ProbeModeConnect sends a CONNECT request to establish a tunnel
+	// through the target and checks the response
+	ProbeModeConnect = "connect"
+	// ProbeModeRange sends byte range requests
+	// and checks the '206 Partial Content' responses
+	ProbeModeRange = "range"
+	// ProbeModeKeepAlive makes sequential follow up calls
+	// checking that the connections are reused (HTTP keep-alive)
+	ProbeModeKeepAlive = "keepalive"
+	// ProbeModeCacheHeaders checks the caching directives in the responses
+	// (Cache-Control and Expires) using the command cache policy
+	ProbeModeCacheHeaders = "cache-headers"
+)
+
+// Cache policies for the 'cache-headers' probe mode
+const (
+	// CachePolicyCacheable expects a response that can be cached
+	// (a positive max-age/s-maxage or a future Expires and no 'no-store')
+	CachePolicyCacheable = "cacheable"
+	// CachePolicyNoStore expects a 'no-store' response (for the sensitive resources)
+	CachePolicyNoStore = "no-store"
+)
+
+func IsProbeMode(value string) bool {
+	switch strings.ToLower(value) {
+	case ProbeModeETag,
+		ProbeModeWebDAV,
+		ProbeModeUpload,
+		ProbeModeCompression,
+		ProbeModeSecurityHeaders,
+		ProbeModeConnect,
+		ProbeModeRange,
+		ProbeModeKeepAlive,
+		ProbeModeCacheHeaders:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTP probe call error categories
+const (
+	ProbeErrorDNS      = "dns"
+	ProbeErrorRefused  = "refused"
+	ProbeErrorReset    = "reset"
+	ProbeErrorTimeout  = "timeout"
+	ProbeErrorEOF      = "eof"
+	ProbeErrorTLS      = "tls"
+	ProbeErrorResponse = "response"
+	//the route doesn't exist (404)
+	ProbeErrorNotFound = "not_found"
+	//the response headers exceeded the configured limit
+	ProbeErrorHeaderLimit = "header_limit"
+	ProbeErrorOther       = "other"
+	ProbeErrorAll         = "all"
+)
+
+// DefaultProbeRetryOn is the list of the transient error categories retried by default
+var DefaultProbeRetryOn = []string{
+	ProbeErrorRefused,
+	ProbeErrorReset,
+	ProbeErrorTimeout,
+	ProbeErrorEOF,
+}
+
+func IsProbeErrorCategory(value string) bool {
+	switch strings.ToLower(value) {
+	case ProbeErrorDNS,
+		ProbeErrorRefused,
+		ProbeErrorReset,
+		ProbeErrorTimeout,
+		ProbeErrorEOF,
+		ProbeErrorTLS,
+		ProbeErrorResponse,
+		ProbeErrorNotFound,
+		ProbeErrorHeaderLimit,
+		ProbeErrorOther,
+		ProbeErrorAll:
 		return true
 	default:
 		return false
@@ -161,7 +392,7 @@ func IsProto(value string) bool {
 type HTTPProbeCmd struct {
 	Method   string   `json:"method"`
 	Resource string   `json:"resource"`
-	Port     int      `json:"port"`
+	Port     int      `json:"port"` //deprecated: use 'ports' (it's added to 'ports' when the commands file is loaded)
 	Protocol string   `json:"protocol"`
 	Headers  []string `json:"headers"`
 	Body     string   `json:"body"`
@@ -169,10 +400,169 @@ type HTTPProbeCmd struct {
 	Username string   `json:"username"`
 	Password string   `json:"password"`
 	Crawl    bool     `json:"crawl"`
+	Mode     string   `json:"mode"`
+	//Content-Type for the command body (detected from the body if it's not set)
+	ContentType string `json:"content_type,omitempty"`
+	//command name (used to reference the command in 'depends_on')
+	Name string `json:"name,omitempty"`
+	//names of the commands that must succeed before this command
+	DependsOn []string `json:"depends_on,omitempty"`
+	//command group name and stage (set for the commands from the command groups,
+	//the preflight commands from the commands file have the preflight stage without a group)
+	Group      string `json:"group,omitempty"`
+	GroupStage string `json:"group_stage,omitempty"`
+	//response values saved to variables (name -> source)
+	Capture map[string]string `json:"capture,omitempty"`
+	//assertion expressions (using the captured variables)
+	Assert []string `json:"assert,omitempty"`
+	//target image platform conditions ("os" or "os/arch", "*" matches any value)
+	Platforms []string `json:"platforms,omitempty"`
+	//target container ports for the command (the command runs on all probed ports if it's empty)
+	Ports []uint16 `json:"ports,omitempty"`
+	//share of the total retry budget (relative to the other commands, the default is 1)
+	Weight int `json:"weight,omitempty"`
+	//the probe fails if the command fails for all probed targets (with the 'exit on failure' option)
+	Required bool `json:"required,omitempty"`
+	//absolute URL (scheme, host, optional port and path prefix) for the command calls
+	//(overrides the target address and port, the port and address discovery is bypassed)
+	BaseURL string `json:"base_url,omitempty"`
+	//Unix domain socket for the command calls ('unix:///var/run/app.sock' or '/var/run/app.sock')
+	//(the socket is probed instead of the target address and port)
+	UnixSocket string `json:"unix_socket,omitempty"`
+	//check that the redirect chain ends at an https URL
+	ExpectHTTPS bool `json:"expect_https,omitempty"`
+	//follow the redirects for the command calls (the redirects are followed if it's not set)
+	FollowRedirects *bool `json:"follow_redirects,omitempty"`
+	//status codes for the successful calls (any response is successful by default)
+	ExpectStatus []int `json:"expect_status,omitempty"`
+	//expected response body string and regular expression (the body is not checked by default)
+	ExpectBodyContains string `json:"expect_body_contains,omitempty"`
+	ExpectBodyMatch    string `json:"expect_body_match,omitempty"`
+	//client certificate and key files (mutual TLS) and the CA certificate file
+	//(the server certificate is verified only if the CA certificate is set)
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	CACert     string `json:"ca_cert,omitempty"`
+	//ordered protocol preference (the protocols are tried in order until a call is successful)
+	ProtocolChain []string `json:"protocol_chain,omitempty"`
+	//Accept-Language values (the command is executed once for each value)
+	AcceptLanguages []string `json:"accept_languages,omitempty"`
+	//basic auth password file (used if the password is not set, the trailing newline is ignored)
+	PasswordFile string `json:"password_file,omitempty"`
+
+	//gzip parameters (the gzip responses are decoded before the body checks)
+	AcceptGzip bool `json:"accept_gzip,omitempty"`
+	GzipBody   bool `json:"gzip_body,omitempty"`
+
+	//graphql protocol parameters (the basic queries are generated
+	//from the introspected schema if the query is not set)
+	GraphQLQuery     string                 `json:"graphql_query,omitempty"`
+	GraphQLVariables map[string]interface{} `json:"graphql_variables,omitempty"`
+	GraphQLOperation string                 `json:"graphql_operation,omitempty"`
+
+	//upload mode parameters
+	UploadSize      int64 `json:"upload_size,omitempty"`
+	UploadChunkSize int   `json:"upload_chunk_size,omitempty"`
+	UploadVerify    bool  `json:"upload_verify,omitempty"`
+
+	//compression mode parameters
+	MinCompressionRatio float64 `json:"min_compression_ratio,omitempty"`
+
+	//security-headers mode parameters
+	SecurityHeaders     []string `json:"security_headers,omitempty"`
+	SecurityHeadersWarn bool     `json:"security_headers_warn,omitempty"`
+
+	//range mode parameters (e.g., "0-1023", "1024-" or "-512")
+	Ranges []string `json:"ranges,omitempty"`
+
+	//keepalive mode parameters (the number of the follow up calls)
+	KeepAliveRequests int `json:"keepalive_requests,omitempty"`
+
+	//number of the additional calls after the first successful call (overrides the probe repeat count)
+	RepeatCount int `json:"repeat_count,omitempty"`
+
+	//cache-headers mode parameters (the expected Cache-Control directives
+	//are 'name', 'name=value', 'name>=seconds' or 'name<=seconds')
+	CachePolicy     string   `json:"cache_policy,omitempty"`
+	CacheDirectives []string `json:"cache_directives,omitempty"`
+
+	//connect mode parameters (tunnel target host:port)
+	ConnectTarget string `json:"connect_target,omitempty"`
+
+	//command executed in the target container instead of the HTTP call (the command arguments).
+	//The command runs once for each probe run (it's successful if its exit code is zero)
+	//and the expected body checks use its output.
+	Exec []string `json:"exec,omitempty"`
 
 	FastCGI *FastCGIProbeWrapperConfig `json:"fastcgi,omitempty"`
 }
 
+// HTTPProbeCredential is a credential set used when the probe call is unauthorized (401)
+type HTTPProbeCredential struct {
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	//bearer token (used instead of the basic auth username and password)
+	Token string `json:"token,omitempty"`
+}
+
+// Probe result severities (ordered from the lowest to the highest)
+const (
+	ProbeSeverityInfo  = "info"
+	ProbeSeverityWarn  = "warn"
+	ProbeSeverityError = "error"
+)
+
+// Probe result metrics for the severity rules
+const (
+	ProbeMetricCalls          = "calls"
+	ProbeMetricFailures       = "failures"
+	ProbeMetricFailureRate    = "failure_rate" //percent of the failed calls
+	ProbeMetricSuccessRate    = "success_rate" //percent of the successful calls
+	ProbeMetricNotFoundRoutes = "not_found_routes"
+
+	//the failed probe commands (the commands without a successful call)
+	ProbeMetricFailedCommands    = "failed_commands"
+	ProbeMetricFailedCommandRate = "failed_command_rate"
+)
+
+// Built-in probe result assertions (evaluated after the probe run)
+const (
+	// ProbeAssertOKPerPort checks that each probed port has at least one 2xx response
+	ProbeAssertOKPerPort = "ok_per_port"
+	// ProbeAssertNoFailedCommands checks that all probe commands have a successful call
+	ProbeAssertNoFailedCommands = "no_failed_commands"
+	// ProbeAssertCmdOKPrefix checks that the named probe command (e.g., a login flow step) succeeded
+	ProbeAssertCmdOKPrefix = "cmd_ok:"
+	// ProbeAssertExprPrefix evaluates an expression using the probe result metrics
+	ProbeAssertExprPrefix = "expr:"
+)
+
+// IsProbeResultAssertion returns true if the value is a known probe result assertion
+func IsProbeResultAssertion(value string) bool {
+	switch {
+	case value == ProbeAssertOKPerPort,
+		value == ProbeAssertNoFailedCommands:
+		return true
+	case strings.HasPrefix(value, ProbeAssertCmdOKPrefix):
+		return strings.TrimSpace(strings.TrimPrefix(value, ProbeAssertCmdOKPrefix)) != ""
+	case strings.HasPrefix(value, ProbeAssertExprPrefix):
+		return strings.TrimSpace(strings.TrimPrefix(value, ProbeAssertExprPrefix)) != ""
+	default:
+		return false
+	}
+}
+
+// HTTPProbeSeverityRule maps a probe result metric condition to a severity
+// (e.g., 'failure_rate>5:warn')
+type HTTPProbeSeverityRule struct {
+	Rule      string
+	Metric    string
+	Op        string
+	Threshold float64
+	Severity  string
+}
+
 // FastCGI permits fine-grained configuration of the fastcgi RoundTripper.
 type FastCGIProbeWrapperConfig struct {
 	// Root is the fastcgi root directory.
@@ -200,7 +590,28 @@ type FastCGIProbeWrapperConfig struct {
 
 // HTTPProbeCmds is a list of HTTPProbeCmd instances
 type HTTPProbeCmds struct {
+	Commands []HTTPProbeCmd      `json:"commands"`
+	Groups   []HTTPProbeCmdGroup `json:"groups,omitempty"`
+	//commands that run once (in order) and must succeed before the probe commands
+	Preflight []HTTPProbeCmd `json:"preflight,omitempty"`
+}
+
+const (
+	CmdGroupStageSetup    = "setup"
+	CmdGroupStageTeardown = "teardown"
+	//the stage for the preflight commands from the commands file (they have no group)
+	CmdStagePreflight = "preflight"
+)
+
+// HTTPProbeCmdGroup is a group of HTTP probe commands
+// with the optional setup and teardown commands.
+// The setup command runs before the group commands (they are skipped if it fails)
+// and the teardown command runs after them (even if they fail).
+type HTTPProbeCmdGroup struct {
+	Name     string         `json:"name"`
+	Setup    *HTTPProbeCmd  `json:"setup,omitempty"`
 	Commands []HTTPProbeCmd `json:"commands"`
+	Teardown *HTTPProbeCmd  `json:"teardown,omitempty"`
 }
 
 // DockerClient provides Docker client parameters
@@ -231,6 +642,16 @@ type ContinueAfter struct {
 	ContinueChan <-chan struct{}
 }
 
+const (
+	CassetteModeRecord   = "record"
+	CassetteModePlayback = "playback"
+)
+
+const (
+	RequestIDModeRandom        = "random"
+	RequestIDModeDeterministic = "deterministic"
+)
+
 type HTTPProbeOptions struct {
 	Do            bool
 	Full          bool
@@ -239,9 +660,55 @@ type HTTPProbeOptions struct {
 	Cmds  []HTTPProbeCmd
 	Ports []uint16
 
-	StartWait  int
-	RetryCount int
-	RetryWait  int
+	//wait time before the probe calls (no wait if it's zero or negative)
+	StartWait time.Duration
+	//how long to wait for the target ports to accept connections
+	//(the default timeout is used if it's zero, a negative value disables the port polling)
+	PortReadyTimeout time.Duration
+	//how often the target ports are polled (the default interval is used if it's zero)
+	PortReadyInterval time.Duration
+
+	RetryCount        int
+	RetryWait         int
+	RetryBackoffReset bool
+	RetryOn           []string
+	//number of the additional calls for each command after its first successful call
+	RepeatCount int
+	//default status codes for the successful calls (used if the command has no expected status codes)
+	ExpectStatus []int
+	//retry wait time limit for the exponential backoff (the default limit if it's zero)
+	RetryMaxWait time.Duration
+	//total number of retries shared by the probe commands (not limited if it's zero)
+	TotalRetryBudget int
+
+	//absolute wall-clock time when the probe stops (not set if it's zero)
+	Deadline time.Time
+	//maximum total probe duration starting when the probe starts (not limited if it's zero)
+	MaxTime time.Duration
+
+	Concurrency int
+	//maximum number of probe calls per second (shared by all probe workers, not limited if it's zero)
+	RateLimit float64
+
+	FailOnDuplicateCmds bool
+
+	//fail if there are no exposed or target ports and the container has multiple ports
+	RequireExplicitPorts bool
+
+	//data generator seed for the probe command templates (random if it's zero)
+	DataSeed int64
+
+	//credential sets tried in order when the probe call is unauthorized (401)
+	FallbackCredentials []HTTPProbeCredential
+
+	//rules to compute the probe result severity (the highest triggered severity is reported)
+	SeverityRules []HTTPProbeSeverityRule
+
+	//assertions evaluated over the aggregated probe result (after the probe run)
+	ResultAssertions []string
+
+	//how often the probe scheduler gauges are printed (not printed if it's zero)
+	MetricsInterval time.Duration
 
 	CrawlMaxDepth       int
 	CrawlMaxPageCount   int
@@ -250,9 +717,103 @@ type HTTPProbeOptions struct {
 
 	APISpecs     []string
 	APISpecFiles []string
+	//spec files or URLs used to generate the probe commands
+	APISpecCmds []string
+
+	RoutesEndpoint    string
+	RoutesDestructive bool
 
 	ProxyEndpoint string
 	ProxyPort     int
+
+	AllAddresses bool
+	//also probe the container IP address (with the container ports) when the published host ports fail
+	ContainerIP bool
+
+	DNSServer string
+
+	//TLS server name (SNI) for the https calls to the targets probed by IP
+	TLSServerName string
+
+	//response header size limit for the probe calls (Go's default limit if it's zero)
+	MaxResponseHeaderBytes int64
+	//redirect limit for the probe calls (the default limit if it's zero)
+	MaxRedirects int
+	//probe call and connect timeouts (the default timeouts if they are zero)
+	CallTimeout    time.Duration
+	ConnectTimeout time.Duration
+	//isolated probe calls (no shared cookie jar for the command calls)
+	NoCookieJar bool
+	//proxy URL for the probe calls (the proxy environment variables are used if it's empty)
+	Proxy string
+	//target host for the probe calls (the probe target host is used if it's empty)
+	Host string
+	//add the probe command for the URL in the image HEALTHCHECK instruction
+	UseHealthcheck bool
+	//verify the server certificates (the certificates are not verified by default)
+	VerifyTLS bool
+	//CA certificate bundle file for the server certificate verification (enables the verification)
+	CACert string
+	//print the probe plan (the probed URLs) without making the probe calls
+	DryRun bool
+	//print all call attempts (only the final call outcomes are printed by default)
+	Verbose bool
+	//User-Agent header for all probe calls
+	UserAgent string
+	//default headers for all probe calls ('Name: Value', the command headers override them)
+	Headers []string
+
+	CSVOutput string
+	//JSON probe report file (the probe call records and the summary)
+	ReportOutput string
+
+	PcapOutput string
+
+	EventLogOutput      string
+	EventLogMaxMessages int
+
+	//host command executed before the probe commands to seed the app data
+	SeedExec string
+	//app endpoint called before the probe commands to seed the app data ('[METHOD ]RESOURCE')
+	SeedEndpoint string
+	//commands that run once before the probe commands (they must succeed)
+	PreflightCmds []HTTPProbeCmd
+	//skip the probe commands if a preflight command fails (the probe fails then)
+	PreflightAbort bool
+
+	//directory for the headless browser screenshots of the failed page probes (disabled if it's empty)
+	ScreenshotOnFailure string
+	//headless browser (Chrome or Chromium) executable for the screenshots
+	ScreenshotBrowser string
+
+	CassetteFile string
+	CassetteMode string
+
+	RequestIDMode string
+
+	CPUThrottle          bool
+	CPUThrottleThreshold int
+}
+
+// TCPProbeOptions provides the TCP probe options (for the target ports that don't serve HTTP)
+type TCPProbeOptions struct {
+	Specs []TCPProbeSpec
+}
+
+// TCPProbeSpec is a TCP probe target port
+// (the probe connects to the port and, optionally, sends the data and checks the response prefix)
+type TCPProbeSpec struct {
+	Port   uint16
+	Send   string
+	Expect string
+}
+
+// ExecProbeOptions provides the exec probe options (for the commands executed in the target container).
+// The exec probe runs the commands on its own (without the checks), the HTTP probe exec commands (HTTPProbeCmd.Exec)
+// run the commands in the probe plan (with the expected body checks). Both use the same exec command runner.
+type ExecProbeOptions struct {
+	//the command arguments for each command (the commands run in order)
+	Cmds [][]string
 }
 
 type AppNodejsInspectOptions struct {
@@ -0,0 +1,145 @@
+package exec
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe"
+)
+
+type ovars = app.OutVars
+
+// ExecProbe is a probe that runs the commands in the target container
+// (e.g., to trigger the cron job or the CLI subcommand code paths that are not reachable with HTTP)
+type ExecProbe struct {
+	xc *app.ExecutionContext
+
+	opts   config.ExecProbeOptions
+	runner Runner
+
+	printState bool
+
+	CallCount uint64
+	ErrCount  uint64
+	OkCount   uint64
+
+	doneChan chan struct{}
+}
+
+var _ probe.Probe = (*ExecProbe)(nil)
+
+// NewContainerProbe creates a new exec probe for the target container
+func NewContainerProbe(
+	xc *app.ExecutionContext,
+	inspector *container.Inspector,
+	opts config.ExecProbeOptions,
+	printState bool,
+) (*ExecProbe, error) {
+	runner, err := NewContainerRunner(inspector.APIClient, inspector.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProbe(xc, runner, opts, printState), nil
+}
+
+// NewProbe creates a new exec probe using the exec command runner
+func NewProbe(
+	xc *app.ExecutionContext,
+	runner Runner,
+	opts config.ExecProbeOptions,
+	printState bool,
+) *ExecProbe {
+	return &ExecProbe{
+		xc:         xc,
+		opts:       opts,
+		runner:     runner,
+		printState: printState,
+		doneChan:   make(chan struct{}),
+	}
+}
+
+// Start starts the exec probe instance execution
+func (p *ExecProbe) Start() {
+	if p.printState {
+		p.xc.Out.State("exec.probe.starting",
+			ovars{
+				"message": "WAIT FOR EXEC PROBE TO FINISH",
+			})
+
+		p.xc.Out.Info("exec.probe.commands",
+			ovars{
+				"count": len(p.opts.Cmds),
+			})
+	}
+
+	go func() {
+		log.Info("Exec probe started...")
+
+		//the commands run one after another (in order)
+		for _, cmd := range p.opts.Cmds {
+			p.call(cmd)
+		}
+
+		log.Info("Exec probe done.")
+
+		if p.printState {
+			p.xc.Out.Info("exec.probe.summary",
+				ovars{
+					"total":      atomic.LoadUint64(&p.CallCount),
+					"failures":   atomic.LoadUint64(&p.ErrCount),
+					"successful": atomic.LoadUint64(&p.OkCount),
+				})
+
+			outVars := ovars{}
+			if atomic.LoadUint64(&p.OkCount) == 0 {
+				outVars["warning"] = "no.successful.calls"
+			}
+
+			p.xc.Out.State("exec.probe.done", outVars)
+		}
+
+		close(p.doneChan)
+	}()
+}
+
+func (p *ExecProbe) call(cmd []string) {
+	result, err := Call(context.Background(), p.runner, cmd)
+	atomic.AddUint64(&p.CallCount, 1)
+	if err == nil {
+		atomic.AddUint64(&p.OkCount, 1)
+	} else {
+		atomic.AddUint64(&p.ErrCount, 1)
+		log.Debugf("Exec probe - command error (%s) - %v", strings.Join(cmd, " "), err)
+	}
+
+	if p.printState {
+		info := ovars{
+			"command": strings.Join(cmd, " "),
+			"error":   "none",
+		}
+
+		if result != nil {
+			info["exit.code"] = result.ExitCode
+			info["duration"] = result.Duration.Round(time.Millisecond).String()
+		}
+
+		if err != nil {
+			info["error"] = err.Error()
+		}
+
+		p.xc.Out.Info("exec.probe.call", info)
+	}
+}
+
+// DoneChan returns the 'done' channel for the exec probe instance
+func (p *ExecProbe) DoneChan() <-chan struct{} {
+	return p.doneChan
+}
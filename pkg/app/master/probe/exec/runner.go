@@ -0,0 +1,149 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+)
+
+const (
+	defaultTimeout = 1 * time.Minute
+
+	//the exec command output saved in the results (the rest of the output is discarded)
+	maxOutputSize = 64 * 1024
+)
+
+var (
+	ErrNoContainer = errors.New("no target container")
+	ErrExitCode    = errors.New("non-zero exit code")
+)
+
+// Result is the exec command result
+type Result struct {
+	ExitCode int
+	//combined stdout and stderr output (limited to the first 64KB)
+	Output []byte
+	//the output is truncated (it's larger than the output limit)
+	Truncated bool
+	Duration  time.Duration
+}
+
+// Err returns the error for the failed commands (the commands with a non-zero exit code)
+func (r *Result) Err() error {
+	if r.ExitCode != 0 {
+		return fmt.Errorf("%w (%d)", ErrExitCode, r.ExitCode)
+	}
+
+	return nil
+}
+
+// Runner runs the commands in the target container
+type Runner interface {
+	Run(ctx context.Context, cmd []string) (*Result, error)
+}
+
+// Call runs the command with the runner (the exec probe and the HTTP probe exec commands share it).
+// The commands with a non-zero exit code return the result and ErrExitCode.
+func Call(ctx context.Context, runner Runner, cmd []string) (*Result, error) {
+	if runner == nil {
+		return nil, ErrNoContainer
+	}
+
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, result.Err()
+}
+
+// ContainerRunner runs the commands in the target container with the Docker exec API
+type ContainerRunner struct {
+	apiClient   *dockerapi.Client
+	containerID string
+	timeout     time.Duration
+}
+
+// NewContainerRunner creates a new exec command runner for the target container
+func NewContainerRunner(apiClient *dockerapi.Client, containerID string) (*ContainerRunner, error) {
+	if apiClient == nil || containerID == "" {
+		return nil, ErrNoContainer
+	}
+
+	return &ContainerRunner{
+		apiClient:   apiClient,
+		containerID: containerID,
+		timeout:     defaultTimeout,
+	}, nil
+}
+
+// Run runs the command in the target container and waits for it to finish
+// (it stops waiting when the context is canceled or when the command times out)
+func (r *ContainerRunner) Run(ctx context.Context, cmd []string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	exec, err := r.apiClient.CreateExec(dockerapi.CreateExecOptions{
+		Context:      ctx,
+		Container:    r.containerID,
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &limitedBuffer{limit: maxOutputSize}
+	err = r.apiClient.StartExec(exec.ID, dockerapi.StartExecOptions{
+		OutputStream: output,
+		ErrorStream:  output,
+		Context:      ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inspect, err := r.apiClient.InspectExec(exec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inspect.Running {
+		return nil, fmt.Errorf("exec command is still running (%s)", exec.ID)
+	}
+
+	return &Result{
+		ExitCode:  inspect.ExitCode,
+		Output:    output.data,
+		Truncated: output.truncated,
+		Duration:  time.Since(start),
+	}, nil
+}
+
+// limitedBuffer keeps the first 'limit' bytes of the output
+// (the exec output streams are written one after another, so no locking)
+type limitedBuffer struct {
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.data); room > 0 {
+		if len(p) > room {
+			b.data = append(b.data, p[:room]...)
+			b.truncated = true
+		} else {
+			b.data = append(b.data, p...)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+
+	return len(p), nil
+}
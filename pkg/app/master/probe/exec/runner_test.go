@@ -0,0 +1,133 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+type testRunner struct {
+	results map[string]*Result
+	calls   []string
+}
+
+var errTestRun = errors.New("exec api error")
+
+func (r *testRunner) Run(ctx context.Context, cmd []string) (*Result, error) {
+	r.calls = append(r.calls, cmd[0])
+	result, found := r.results[cmd[0]]
+	if !found {
+		return nil, errTestRun
+	}
+
+	return result, nil
+}
+
+func TestCall(t *testing.T) {
+	runner := &testRunner{
+		results: map[string]*Result{
+			"ok":   {ExitCode: 0, Output: []byte("done")},
+			"fail": {ExitCode: 2, Output: []byte("failed")},
+		},
+	}
+
+	tt := []struct {
+		cmd      string
+		exitCode int
+		err      error
+	}{
+		{cmd: "ok", exitCode: 0},
+		{cmd: "fail", exitCode: 2, err: ErrExitCode},
+		{cmd: "missing", err: errTestRun},
+	}
+
+	for _, test := range tt {
+		result, err := Call(context.Background(), runner, []string{test.cmd})
+		if !errors.Is(err, test.err) {
+			t.Errorf("Call(%s): got error %v expected %v", test.cmd, err, test.err)
+		}
+
+		if test.err == errTestRun {
+			if result != nil {
+				t.Errorf("Call(%s): got result %+v expected none", test.cmd, result)
+			}
+
+			continue
+		}
+
+		if result == nil || result.ExitCode != test.exitCode {
+			t.Errorf("Call(%s): got result %+v expected exit code %d", test.cmd, result, test.exitCode)
+		}
+	}
+
+	if _, err := Call(context.Background(), nil, []string{"ok"}); err != ErrNoContainer {
+		t.Errorf("Call(nil runner): got error %v expected %v", err, ErrNoContainer)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	tt := []struct {
+		writes    []string
+		limit     int
+		data      string
+		truncated bool
+	}{
+		{writes: []string{"abc"}, limit: 5, data: "abc"},
+		{writes: []string{"abcde"}, limit: 5, data: "abcde"},
+		{writes: []string{"abcdef"}, limit: 5, data: "abcde", truncated: true},
+		{writes: []string{"abc", "def"}, limit: 5, data: "abcde", truncated: true},
+		{writes: []string{"abcde", "f"}, limit: 5, data: "abcde", truncated: true},
+		{writes: []string{"abcde", ""}, limit: 5, data: "abcde"},
+	}
+
+	for _, test := range tt {
+		b := &limitedBuffer{limit: test.limit}
+		for _, w := range test.writes {
+			if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+				t.Errorf("limitedBuffer.Write(%q): got %d, %v expected %d, nil", w, n, err, len(w))
+			}
+		}
+
+		if !bytes.Equal(b.data, []byte(test.data)) || b.truncated != test.truncated {
+			t.Errorf("limitedBuffer %q: got %q (truncated=%v) expected %q (truncated=%v)",
+				test.writes, b.data, b.truncated, test.data, test.truncated)
+		}
+	}
+}
+
+func TestExecProbeCounts(t *testing.T) {
+	runner := &testRunner{
+		results: map[string]*Result{
+			"ok":   {ExitCode: 0},
+			"fail": {ExitCode: 1},
+		},
+	}
+
+	opts := config.ExecProbeOptions{
+		Cmds: [][]string{{"ok"}, {"fail"}, {"missing"}, {"ok"}},
+	}
+
+	xc := app.NewExecutionContext("probe", false, "text")
+	p := NewProbe(xc, runner, opts, false)
+	p.Start()
+	<-p.DoneChan()
+
+	if p.CallCount != 4 || p.OkCount != 2 || p.ErrCount != 2 {
+		t.Errorf("got calls/ok/errors %d/%d/%d expected 4/2/2", p.CallCount, p.OkCount, p.ErrCount)
+	}
+
+	if expected := []string{"ok", "fail", "missing", "ok"}; len(runner.calls) != len(expected) {
+		t.Errorf("got commands %q expected %q", runner.calls, expected)
+	} else {
+		for i := range expected {
+			if runner.calls[i] != expected[i] {
+				t.Errorf("got commands %q expected %q (in order)", runner.calls, expected)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"net"
+	"net/url"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
+)
+
+type probeTarget struct {
+	host string
+	port string
+	//Unix socket path (for the Unix socket command targets)
+	socket string
+	//the published host port for the container IP address targets
+	//(the container IP address target is the fallback for the host port target)
+	hostPort string
+}
+
+// containerIP returns the container IP address
+// (the default network address or the first network address if it's not set)
+func containerIP(inspector *container.Inspector) string {
+	if inspector.ContainerInfo == nil || inspector.ContainerInfo.NetworkSettings == nil {
+		return ""
+	}
+
+	settings := inspector.ContainerInfo.NetworkSettings
+	if settings.IPAddress != "" {
+		return settings.IPAddress
+	}
+
+	var names []string
+	for name := range settings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if addr := settings.Networks[name].IPAddress; addr != "" {
+			return addr
+		}
+	}
+
+	return ""
+}
+
+// containerAddresses returns the unique IP addresses
+// from all container networks (the primary address is always first)
+func containerAddresses(primary string, inspector *container.Inspector) []string {
+	addrs := []string{primary}
+	if inspector.ContainerInfo == nil || inspector.ContainerInfo.NetworkSettings == nil {
+		return addrs
+	}
+
+	settings := inspector.ContainerInfo.NetworkSettings
+
+	var names []string
+	for name := range settings.Networks {
+		names = append(names, name)
+	}
+	//predictable probe order
+	sort.Strings(names)
+
+	candidates := []string{settings.IPAddress}
+	for _, name := range names {
+		network := settings.Networks[name]
+		log.Debugf("HTTP probe - container network '%s' (ip='%s' aliases=%v)",
+			name, network.IPAddress, network.Aliases)
+		candidates = append(candidates, network.IPAddress)
+	}
+
+	seen := map[string]struct{}{primary: {}}
+	for _, addr := range candidates {
+		if addr == "" {
+			continue
+		}
+
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+
+		seen[addr] = struct{}{}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs
+}
+
+// probeTargets returns the host/port pairs to probe (grouped by host)
+// and the Unix sockets for the socket commands (after the host/port pairs)
+func (p *CustomProbe) probeTargets() []probeTarget {
+	hosts := p.targetHosts
+	if len(hosts) == 0 {
+		hosts = []string{p.targetHost}
+	}
+
+	var targets []probeTarget
+	if !socketOnlyCmds(p.opts.Cmds) {
+		for _, host := range hosts {
+			for _, port := range p.ports {
+				targets = append(targets, probeTarget{host: host, port: port})
+				if target, ok := p.containerIPTarget(port); ok {
+					targets = append(targets, target)
+				}
+			}
+		}
+	}
+
+	return append(targets, socketTargets(p.opts.Cmds)...)
+}
+
+// containerIPTarget returns the container IP address target for the host port
+// (the container IP address is probed with the container port)
+func (p *CustomProbe) containerIPTarget(hostPort string) (probeTarget, bool) {
+	if p.containerIP == "" {
+		return probeTarget{}, false
+	}
+
+	cport, ok := p.containerPorts[hostPort]
+	if !ok {
+		return probeTarget{}, false
+	}
+
+	return probeTarget{host: p.containerIP, port: cport, hostPort: hostPort}, true
+}
+
+// printTargetAddress prints the address with the successful probe pass for the container port
+// (the published host port or the container IP address)
+func (p *CustomProbe) printTargetAddress(target probeTarget) {
+	if p.containerIP == "" || !p.printState {
+		return
+	}
+
+	info := ovars{
+		"address": net.JoinHostPort(target.host, target.port),
+	}
+
+	if target.hostPort != "" {
+		info["container.port"] = target.port
+		info["type"] = "container.ip"
+	} else {
+		info["container.port"] = p.containerPort(target.port)
+		info["type"] = "host.port"
+	}
+
+	p.xc.Out.Info("http.probe.target.address", info)
+}
+
+// printAddressSummary prints the call results for each probed address
+func (p *CustomProbe) printAddressSummary() {
+	if len(p.targetHosts) < 2 {
+		return
+	}
+
+	type addressCounts struct {
+		total      uint64
+		failures   uint64
+		successful uint64
+	}
+
+	counts := map[string]*addressCounts{}
+	for _, result := range p.CallResults() {
+		turl, err := url.Parse(result.Target)
+		if err != nil {
+			continue
+		}
+
+		host := turl.Hostname()
+		if counts[host] == nil {
+			counts[host] = &addressCounts{}
+		}
+
+		counts[host].total++
+		if result.Error == "" {
+			counts[host].successful++
+		} else {
+			counts[host].failures++
+		}
+	}
+
+	for _, host := range p.targetHosts {
+		info := counts[host]
+		if info == nil {
+			info = &addressCounts{}
+		}
+
+		p.xc.Out.Info("http.probe.address.summary",
+			ovars{
+				"address":    host,
+				"total":      info.total,
+				"failures":   info.failures,
+				"successful": info.successful,
+			})
+	}
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	formContentType = "application/x-www-form-urlencoded"
+
+	//the nesting limit for the generated request bodies (the spec schemas can be recursive)
+	maxAPISpecBodyDepth = 5
+)
+
+// apiSpecRequestBody returns the example request body and its content type for the API spec operation.
+// The JSON bodies are preferred. The body is the media type example (or the first named example)
+// or it's generated from the schema (using its examples, defaults, enums or types).
+// Returns false if there's no request body or if it can't be generated.
+func apiSpecRequestBody(op *openapi3.Operation) (string, string, bool) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", "", false
+	}
+
+	content := op.RequestBody.Value.Content
+	var ctypes []string
+	for ctype := range content {
+		ctypes = append(ctypes, ctype)
+	}
+	//predictable content type selection (when there's no JSON content type)
+	sort.Strings(ctypes)
+
+	for _, ctype := range ctypes {
+		if isJSONContentType(ctype) {
+			if body, ok := apiSpecMediaBody(content[ctype], ctype); ok {
+				return body, ctype, true
+			}
+		}
+	}
+
+	for _, ctype := range ctypes {
+		if !isJSONContentType(ctype) {
+			if body, ok := apiSpecMediaBody(content[ctype], ctype); ok {
+				return body, ctype, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+func isJSONContentType(ctype string) bool {
+	ctype = strings.ToLower(ctype)
+	return ctype == defaultBodyContentType || strings.HasSuffix(ctype, "+json")
+}
+
+func apiSpecMediaBody(media *openapi3.MediaType, ctype string) (string, bool) {
+	if media == nil {
+		return "", false
+	}
+
+	value := media.Example
+	if value == nil && len(media.Examples) > 0 {
+		var names []string
+		for name := range media.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if example := media.Examples[names[0]]; example != nil && example.Value != nil {
+			value = example.Value.Value
+		}
+	}
+
+	if value == nil && media.Schema != nil {
+		value = apiSpecSchemaExample(media.Schema.Value, 0, false)
+	}
+
+	if value == nil {
+		return "", false
+	}
+
+	switch {
+	case isJSONContentType(ctype):
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+
+		return string(data), true
+	case strings.ToLower(ctype) == formContentType:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+
+		form := url.Values{}
+		for name, fvalue := range fields {
+			form.Set(name, fmt.Sprintf("%v", fvalue))
+		}
+
+		return form.Encode(), true
+	}
+
+	//the other content types need the string examples
+	if body, ok := value.(string); ok {
+		return body, true
+	}
+
+	return "", false
+}
+
+// apiSpecSchemaExample generates an example value for the schema
+// (nil if the schema is not resolved or if it's nested too deep).
+// The top level objects get all their writable properties and the nested objects
+// (the property values) get only their required properties.
+func apiSpecSchemaExample(schema *openapi3.Schema, depth int, nested bool) interface{} {
+	if schema == nil || depth > maxAPISpecBodyDepth {
+		return nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	for _, alt := range []openapi3.SchemaRefs{schema.OneOf, schema.AnyOf} {
+		if len(alt) > 0 && alt[0] != nil {
+			return apiSpecSchemaExample(alt[0].Value, depth+1, nested)
+		}
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]interface{}{}
+		for _, sref := range schema.AllOf {
+			if sref == nil {
+				continue
+			}
+
+			if fields, ok := apiSpecSchemaExample(sref.Value, depth+1, nested).(map[string]interface{}); ok {
+				for name, value := range fields {
+					merged[name] = value
+				}
+			}
+		}
+
+		return merged
+	}
+
+	switch schema.Type {
+	case "object", "":
+		if schema.Type == "" && len(schema.Properties) == 0 {
+			break
+		}
+
+		required := map[string]bool{}
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+
+		fields := map[string]interface{}{}
+		for name, sref := range schema.Properties {
+			if sref == nil || (sref.Value != nil && sref.Value.ReadOnly) ||
+				(nested && !required[name]) {
+				continue
+			}
+
+			if value := apiSpecSchemaExample(sref.Value, depth+1, true); value != nil {
+				fields[name] = value
+			}
+		}
+
+		return fields
+	case "array":
+		items := []interface{}{}
+		if schema.Items != nil {
+			if value := apiSpecSchemaExample(schema.Items.Value, depth+1, nested); value != nil {
+				items = append(items, value)
+			}
+		}
+
+		return items
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	}
+
+	switch schema.Format {
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000001"
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "test@example.com"
+	}
+
+	return "test"
+}
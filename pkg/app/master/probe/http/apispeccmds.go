@@ -0,0 +1,167 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the API spec operation methods in the generated command order
+var apiSpecMethods = []string{"get", "head", "options", "post", "put", "patch", "delete", "connect", "trace"}
+
+// LoadCmdsFromAPISpec generates the probe commands for the OpenAPI (v3) or Swagger (v2) spec
+// (a JSON or YAML file or an http/https URL) with one command for each path and method.
+// The path parameters get dummy values for their types and the request bodies
+// are the spec examples (or they are generated from the body schemas).
+// The operations with a required request body that can't be generated are skipped.
+func LoadCmdsFromAPISpec(spec string) ([]config.HTTPProbeCmd, error) {
+	var apiSpec *openapi3.T
+	var err error
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		client, cerr := getHTTPClient(config.ProtoHTTP, nil)
+		if cerr != nil {
+			return nil, cerr
+		}
+
+		apiSpec, err = loadAPISpecFromEndpoint(client, spec)
+	} else {
+		apiSpec, err = loadAPISpecFromFile(spec)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if apiSpec == nil {
+		return nil, fmt.Errorf("unsupported API spec type - %s", spec)
+	}
+
+	prefix, err := apiSpecPrefix(apiSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiSpecCmds(apiSpec, prefix), nil
+}
+
+func apiSpecCmds(spec *openapi3.T, prefix string) []config.HTTPProbeCmd {
+	var paths []string
+	for apiPath := range spec.Paths {
+		paths = append(paths, apiPath)
+	}
+	//predictable command order
+	sort.Strings(paths)
+
+	var cmds []config.HTTPProbeCmd
+	for _, apiPath := range paths {
+		pathInfo := spec.Paths[apiPath]
+		ops := pathOps(pathInfo)
+		for _, method := range apiSpecMethods {
+			op, ok := ops[method]
+			if !ok {
+				continue
+			}
+
+			method = strings.ToUpper(method)
+			body, ctype, hasBody := apiSpecRequestBody(op)
+			if !hasBody && op.RequestBody != nil && op.RequestBody.Value != nil && op.RequestBody.Value.Required {
+				log.Infof("HTTP probe - skipping API spec operation (no example for the required request body) => %s %s", method, apiPath)
+				continue
+			}
+
+			cmd := config.HTTPProbeCmd{
+				Method:   method,
+				Resource: prefix + apiSpecResource(apiPath, pathInfo.Parameters, op.Parameters),
+			}
+
+			if hasBody {
+				cmd.Body = body
+				cmd.ContentType = ctype
+			}
+
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return cmds
+}
+
+// apiSpecResource replaces the path parameters with dummy values
+// (the operation parameters override the path item parameters)
+func apiSpecResource(apiPath string, pathParams, opParams openapi3.Parameters) string {
+	params := map[string]*openapi3.Parameter{}
+	for _, plist := range []openapi3.Parameters{pathParams, opParams} {
+		for _, pref := range plist {
+			if pref != nil && pref.Value != nil && pref.Value.In == openapi3.ParameterInPath {
+				params[pref.Value.Name] = pref.Value
+			}
+		}
+	}
+
+	var resource strings.Builder
+	for {
+		start := strings.Index(apiPath, "{")
+		end := strings.Index(apiPath, "}")
+		if start == -1 || end < start {
+			resource.WriteString(apiPath)
+			break
+		}
+
+		resource.WriteString(apiPath[:start])
+		name := apiPath[start+1 : end]
+		resource.WriteString(url.PathEscape(apiSpecParamValue(params[name])))
+		apiPath = apiPath[end+1:]
+	}
+
+	return resource.String()
+}
+
+// apiSpecParamValue returns a dummy value for the path parameter
+// (its example, default or first enum value if the spec has them)
+func apiSpecParamValue(param *openapi3.Parameter) string {
+	if param == nil {
+		return "test"
+	}
+
+	if param.Example != nil {
+		return fmt.Sprintf("%v", param.Example)
+	}
+
+	if param.Schema == nil || param.Schema.Value == nil {
+		return "test"
+	}
+
+	schema := param.Schema.Value
+	switch {
+	case schema.Example != nil:
+		return fmt.Sprintf("%v", schema.Example)
+	case schema.Default != nil:
+		return fmt.Sprintf("%v", schema.Default)
+	case len(schema.Enum) > 0:
+		return fmt.Sprintf("%v", schema.Enum[0])
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		return "1"
+	case "boolean":
+		return "true"
+	}
+
+	switch schema.Format {
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000001"
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	}
+
+	return "test"
+}
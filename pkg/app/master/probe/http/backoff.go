@@ -0,0 +1,75 @@
+package http
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultMaxRetryBackoffWait = 60 * time.Second
+	//the retry wait time is randomized by up to 20% (in both directions),
+	//so the parallel probe calls don't retry at the same time
+	retryBackoffJitter = 0.2
+)
+
+// retryBackoff tracks the consecutive failed attempts for a probe target
+// to grow the retry wait time exponentially (up to the max wait time)
+type retryBackoff struct {
+	failures       uint
+	resetOnSuccess bool
+	maxWait        time.Duration
+}
+
+func newRetryBackoff(resetOnSuccess bool, maxWait time.Duration) *retryBackoff {
+	if maxWait <= 0 {
+		maxWait = defaultMaxRetryBackoffWait
+	}
+
+	return &retryBackoff{
+		resetOnSuccess: resetOnSuccess,
+		maxWait:        maxWait,
+	}
+}
+
+// next returns the wait time for the next retry based on the base wait time
+// (the wait time doubles with each failed attempt)
+func (b *retryBackoff) next(base time.Duration) time.Duration {
+	//the max wait time doesn't reduce the base wait time
+	maxWait := b.maxWait
+	if base > maxWait {
+		maxWait = base
+	}
+
+	wait := base
+	for i := uint(0); i < b.failures && wait < maxWait; i++ {
+		wait *= 2
+		if wait > maxWait {
+			wait = maxWait
+		}
+	}
+
+	b.failures++
+	return withJitter(wait, maxWait)
+}
+
+// success records a successful (even if intermittent) attempt,
+// which means the target app is coming up, so the backoff starts again
+func (b *retryBackoff) success() {
+	if b.resetOnSuccess {
+		b.failures = 0
+	}
+}
+
+func withJitter(wait, maxWait time.Duration) time.Duration {
+	delta := time.Duration(float64(wait) * retryBackoffJitter)
+	if delta <= 0 {
+		return wait
+	}
+
+	wait = wait - delta + time.Duration(rand.Int63n(int64(2*delta)+1))
+	if wait > maxWait {
+		wait = maxWait
+	}
+
+	return wait
+}
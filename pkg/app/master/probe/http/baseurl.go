@@ -0,0 +1,40 @@
+package http
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// withBaseURL returns the command and its target host and port for the command base URL
+// (the base URL path is the prefix for the command resource)
+func withBaseURL(cmd config.HTTPProbeCmd) (config.HTTPProbeCmd, string, string, error) {
+	u, err := config.ParseProbeBaseURL(cmd.BaseURL)
+	if err != nil {
+		return cmd, "", "", err
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case config.ProtoHTTPS, config.ProtoWSS:
+			port = defaultHTTPSPortStr
+		default:
+			port = defaultHTTPPortStr
+		}
+	}
+
+	if cmd.Protocol == "" {
+		cmd.Protocol = u.Scheme
+	}
+
+	cmd.Resource = strings.TrimSuffix(u.Path, "/") + cmd.Resource
+	return cmd, u.Hostname(), port, nil
+}
+
+// targetOkCount returns the number of the successful calls to the probe targets
+// (excluding the base URL command calls)
+func (p *CustomProbe) targetOkCount() uint64 {
+	return atomic.LoadUint64(&p.OkCount) - atomic.LoadUint64(&p.baseURLOkCount)
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"io"
+	"net/http"
+)
+
+const (
+	headerContentLength = "Content-Length"
+
+	//the response body bytes after the read limit are only counted
+	maxResponseBodyMeasureSize = 64 * 1024 * 1024
+)
+
+// responseBody is the response body (up to the read limit)
+// with the number of body bytes actually received
+type responseBody struct {
+	data      []byte
+	size      int64
+	truncated bool
+	//-1 if the response has no Content-Length
+	//(chunked transfer encoding or the transparently decompressed responses)
+	declaredSize int64
+}
+
+// readResponseBody reads the response body keeping up to 'limit' bytes
+// and counting the rest (so the size doesn't depend on the Content-Length header)
+func readResponseBody(res *http.Response, limit int64) (responseBody, error) {
+	body := responseBody{
+		declaredSize: res.ContentLength,
+	}
+
+	if res.Body == nil {
+		return body, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, limit))
+	body.data = data
+	body.size = int64(len(data))
+	if err != nil {
+		return body, err
+	}
+
+	rest, err := io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseBodyMeasureSize))
+	body.size += rest
+	body.truncated = rest > 0
+	return body, err
+}
+
+// chunked returns true if the response body size is not declared
+func (b responseBody) chunked() bool {
+	return b.declaredSize < 0
+}
+
+// contentLength returns the declared response body size
+// or the measured size when the response has no Content-Length (e.g., chunked responses)
+func (b responseBody) contentLength() int64 {
+	if b.chunked() {
+		return b.size
+	}
+
+	return b.declaredSize
+}
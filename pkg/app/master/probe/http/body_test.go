@@ -0,0 +1,111 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const testBodyChunkCount = 8
+
+func newChunkedServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fixed" {
+			w.Header().Set(headerContentLength, "10")
+			fmt.Fprint(w, "0123456789")
+			return
+		}
+
+		//flushing the writes makes the server use the chunked transfer encoding
+		flusher := w.(http.Flusher)
+		for i := 0; i < testBodyChunkCount; i++ {
+			fmt.Fprint(w, strings.Repeat("x", 1024))
+			flusher.Flush()
+		}
+	}))
+
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getResponseBody(t *testing.T, url string, limit int64) (*http.Response, responseBody) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("call error: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := readResponseBody(res, limit)
+	if err != nil {
+		t.Fatalf("body read error: %v", err)
+	}
+
+	return res, body
+}
+
+func TestReadResponseBodyChunked(t *testing.T) {
+	srv := newChunkedServer(t)
+
+	res, body := getResponseBody(t, srv.URL+"/chunked", maxCaptureBodySize)
+	if len(res.TransferEncoding) == 0 || res.TransferEncoding[0] != "chunked" {
+		t.Fatalf("expected a chunked response: %+v", res.TransferEncoding)
+	}
+
+	expected := int64(testBodyChunkCount * 1024)
+	if !body.chunked() || body.size != expected || body.contentLength() != expected || body.truncated {
+		t.Fatalf("unexpected body info: chunked=%v size=%d content.length=%d truncated=%v",
+			body.chunked(), body.size, body.contentLength(), body.truncated)
+	}
+
+	//the size is measured even if the body is not kept
+	_, body = getResponseBody(t, srv.URL+"/chunked", 100)
+	if len(body.data) != 100 || body.size != expected || !body.truncated {
+		t.Fatalf("unexpected truncated body info: data=%d size=%d truncated=%v",
+			len(body.data), body.size, body.truncated)
+	}
+
+	_, body = getResponseBody(t, srv.URL+"/fixed", maxCaptureBodySize)
+	if body.chunked() || body.size != 10 || body.contentLength() != 10 {
+		t.Fatalf("unexpected fixed body info: chunked=%v size=%d content.length=%d",
+			body.chunked(), body.size, body.contentLength())
+	}
+}
+
+func TestCaptureAndAssertChunked(t *testing.T) {
+	srv := newChunkedServer(t)
+	res, body := getResponseBody(t, srv.URL+"/chunked", maxCaptureBodySize)
+
+	for _, source := range []string{captureSourceSize, "header:Content-Length"} {
+		val, err := captureValue(source, res, body)
+		if err != nil {
+			t.Fatalf("capture error (%s): %v", source, err)
+		}
+
+		if val != "8192" {
+			t.Fatalf("unexpected captured value (%s): %s", source, val)
+		}
+	}
+
+	if _, err := captureValue("header:X-Missing", res, body); err == nil {
+		t.Fatal("expected an error for a missing header")
+	}
+
+	p := &CustomProbe{}
+	cmd := config.HTTPProbeCmd{
+		Capture: map[string]string{"len": "header:Content-Length"},
+		Assert:  []string{"size == 8192", "content_length == size", "len == size"},
+	}
+
+	if err := p.captureAndAssert(0, cmd, srv.URL, res, body); err != nil {
+		t.Fatalf("assert error: %v", err)
+	}
+
+	cmd.Assert = []string{"size < 100"}
+	if err := p.captureAndAssert(0, cmd, srv.URL, res, body); err == nil {
+		t.Fatal("expected an assertion error")
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// body check outcomes (for the commands with the expected body)
+const (
+	bodyCheckPassed = "passed"
+	bodyCheckFailed = "failed"
+)
+
+var ErrUnexpectedBody = errors.New("unexpected response body")
+
+// hasExpectedBody returns true if the command checks the response body
+func hasExpectedBody(cmd config.HTTPProbeCmd) bool {
+	return cmd.ExpectBodyContains != "" || cmd.ExpectBodyMatch != ""
+}
+
+// checkExpectedBody checks that the response body contains the expected string
+// and matches the expected regular expression (only the first part of the large bodies is checked)
+func checkExpectedBody(cmd config.HTTPProbeCmd, body responseBody) error {
+	truncated := ""
+	if body.truncated {
+		truncated = ", truncated body"
+	}
+
+	data := string(body.data)
+	if cmd.ExpectBodyContains != "" && !strings.Contains(data, cmd.ExpectBodyContains) {
+		return fmt.Errorf("%w (no %q%s)", ErrUnexpectedBody, cmd.ExpectBodyContains, truncated)
+	}
+
+	if cmd.ExpectBodyMatch != "" {
+		re, err := regexp.Compile(cmd.ExpectBodyMatch)
+		if err != nil {
+			return fmt.Errorf("%w (bad expression %q: %v)", ErrUnexpectedBody, cmd.ExpectBodyMatch, err)
+		}
+
+		if !re.MatchString(data) {
+			return fmt.Errorf("%w (no match for %q%s)", ErrUnexpectedBody, cmd.ExpectBodyMatch, truncated)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the body file commands send the same body with each call attempt
+func TestProbeCmdBodyFileRetry(t *testing.T) {
+	bodyFile := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(bodyFile, []byte(`{"name":"probe"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		attempt := len(bodies)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pnum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := config.HTTPProbeOptions{
+		RetryCount:   2,
+		RetryWait:    1,
+		ExpectStatus: []int{http.StatusCreated},
+		Cmds: []config.HTTPProbeCmd{
+			{Method: "POST", Resource: "/items", BodyFile: bodyFile},
+		},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	p, err := NewEndpointProbe(xc, "127.0.0.1", []uint{uint(pnum)}, opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Start()
+	<-p.DoneChan()
+
+	if len(bodies) != 2 {
+		t.Fatalf("got %d calls expected 2", len(bodies))
+	}
+
+	for i, body := range bodies {
+		if body != `{"name":"probe"}` {
+			t.Errorf("attempt %d: got body '%s' expected the body file data", i+1, body)
+		}
+	}
+
+	if p.OkCount != 1 {
+		t.Errorf("got %d successful calls expected 1", p.OkCount)
+	}
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	headerCacheControl = "Cache-Control"
+	headerExpires      = "Expires"
+	headerDate         = "Date"
+)
+
+var ErrCacheHeaders = errors.New("cache headers mismatch")
+
+// parseCacheControl returns the Cache-Control directives (name -> value)
+// (the directive names are case-insensitive and the values can be quoted)
+func parseCacheControl(header http.Header) map[string]string {
+	directives := map[string]string{}
+	for _, line := range header.Values(headerCacheControl) {
+		for _, part := range strings.Split(line, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			directives[name] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return directives
+}
+
+// cacheFreshness returns the response freshness lifetime
+// (from s-maxage or max-age, and from Expires relative to Date if there's no max-age)
+func cacheFreshness(header http.Header, directives map[string]string) (time.Duration, bool) {
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if value, ok := directives[name]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	expires := header.Get(headerExpires)
+	if expires == "" {
+		return 0, false
+	}
+
+	expiresTime, err := http.ParseTime(expires)
+	if err != nil {
+		//invalid Expires values (e.g., "0") mean the response is already expired
+		return 0, true
+	}
+
+	base := time.Now()
+	if dateTime, err := http.ParseTime(header.Get(headerDate)); err == nil {
+		base = dateTime
+	}
+
+	return expiresTime.Sub(base), true
+}
+
+// checkCacheDirective checks an expected Cache-Control directive
+// ('name', 'name=value', 'name>=seconds' or 'name<=seconds')
+func checkCacheDirective(directives map[string]string, expected string) bool {
+	expected = strings.TrimSpace(expected)
+	for _, op := range []string{">=", "<="} {
+		name, limit, found := strings.Cut(expected, op)
+		if !found {
+			continue
+		}
+
+		value, ok := directives[strings.ToLower(name)]
+		if !ok {
+			return false
+		}
+
+		seconds, err := strconv.Atoi(value)
+		limitSeconds, lerr := strconv.Atoi(limit)
+		if err != nil || lerr != nil {
+			return false
+		}
+
+		if op == ">=" {
+			return seconds >= limitSeconds
+		}
+
+		return seconds <= limitSeconds
+	}
+
+	name, expectedValue, hasValue := strings.Cut(expected, "=")
+	value, ok := directives[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+
+	return !hasValue || strings.EqualFold(value, strings.Trim(expectedValue, `"`))
+}
+
+// cachePolicy returns the command cache policy
+// (the 'cacheable' policy is used if the command has no cache policy and no directives)
+func cachePolicy(cmd config.HTTPProbeCmd) string {
+	if cmd.CachePolicy == "" && len(cmd.CacheDirectives) == 0 {
+		return config.CachePolicyCacheable
+	}
+
+	return cmd.CachePolicy
+}
+
+// checkCacheHeaders returns the cache header mismatches for the command cache policy and directives
+func checkCacheHeaders(cmd config.HTTPProbeCmd, header http.Header) []string {
+	directives := parseCacheControl(header)
+
+	var mismatches []string
+	switch cachePolicy(cmd) {
+	case config.CachePolicyCacheable:
+		if _, ok := directives["no-store"]; ok {
+			mismatches = append(mismatches, "unexpected no-store")
+		}
+
+		if freshness, ok := cacheFreshness(header, directives); !ok {
+			mismatches = append(mismatches, "no max-age or expires")
+		} else if freshness <= 0 {
+			mismatches = append(mismatches, "expired")
+		}
+	case config.CachePolicyNoStore:
+		if _, ok := directives["no-store"]; !ok {
+			mismatches = append(mismatches, "missing no-store")
+		}
+
+		if _, ok := directives["public"]; ok {
+			mismatches = append(mismatches, "unexpected public")
+		}
+	}
+
+	for _, expected := range cmd.CacheDirectives {
+		if !checkCacheDirective(directives, expected) {
+			mismatches = append(mismatches, "missing "+strings.TrimSpace(expected))
+		}
+	}
+
+	return mismatches
+}
+
+// cacheHeadersCheck reports the caching directives in the response
+// and returns an error for the cache header mismatches
+func (p *CustomProbe) cacheHeadersCheck(cmd config.HTTPProbeCmd, addr string, header http.Header) error {
+	mismatches := checkCacheHeaders(cmd, header)
+
+	status := "ok"
+	if len(mismatches) > 0 {
+		status = "failed"
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.call.cache.headers",
+			ovars{
+				"status":        status,
+				"method":        cmd.Method,
+				"target":        addr,
+				"policy":        cachePolicy(cmd),
+				"cache.control": strings.Join(header.Values(headerCacheControl), ","),
+				"expires":       header.Get(headerExpires),
+				"mismatches":    strings.Join(mismatches, ","),
+				"time":          time.Now().UTC().Format(time.RFC3339),
+			})
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w (%s)", ErrCacheHeaders, strings.Join(mismatches, ","))
+	}
+
+	return nil
+}
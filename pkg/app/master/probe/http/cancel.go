@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// StartContext starts the probe and cancels it when the parent context is done
+// (the in-flight probe calls are aborted and the probe is done after its cleanup)
+func (p *CustomProbe) StartContext(ctx context.Context) {
+	stop := context.AfterFunc(ctx, p.Cancel)
+	go func() {
+		<-p.doneChan
+		stop()
+	}()
+
+	p.Start()
+}
+
+// Cancel stops the probe (the done channel is closed when the probe is done)
+func (p *CustomProbe) Cancel() {
+	select {
+	case <-p.doneChan:
+		return
+	default:
+	}
+
+	if p.ctx.Err() == nil {
+		atomic.StoreUint32(&p.canceled, 1)
+	}
+
+	p.cancel()
+}
+
+// Canceled returns true if the probe was canceled before it finished
+func (p *CustomProbe) Canceled() bool {
+	return atomic.LoadUint32(&p.canceled) == 1
+}
+
+func (p *CustomProbe) printCanceled() {
+	if !p.Canceled() {
+		return
+	}
+
+	log.Debug("HTTP probe - canceled")
+	if p.printState {
+		p.xc.Out.State("http.probe.canceled",
+			ovars{
+				"total": atomic.LoadUint64(&p.CallCount),
+			})
+	}
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"strconv"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// setContainerPorts saves the container ports for the probed host ports
+// (the command ports are the container ports, and they are probed directly in the direct sensor IPC mode)
+func (p *CustomProbe) setContainerPorts(available map[dockerapi.Port]dockerapi.PortBinding) {
+	p.containerPorts = map[string]string{}
+	for portKey, portData := range available {
+		if portKey.Proto() != "tcp" || portData.HostPort == "" {
+			continue
+		}
+
+		p.containerPorts[portData.HostPort] = portKey.Port()
+	}
+}
+
+// containerPort returns the container port for the probed port
+// (the probed port is the container port if it's not a known host port)
+func (p *CustomProbe) containerPort(port string) string {
+	if cport, ok := p.containerPorts[port]; ok {
+		return cport
+	}
+
+	return port
+}
+
+// cmdTargetPort checks if the command runs on the probe target port
+// (the commands without the port list run on all probed ports)
+func (p *CustomProbe) cmdTargetPort(cmd config.HTTPProbeCmd, target probeTarget) bool {
+	if len(cmd.Ports) == 0 || target.socket != "" {
+		return true
+	}
+
+	cport := p.containerPort(target.port)
+	if target.hostPort != "" {
+		//the container IP address targets use the container ports
+		cport = target.port
+	}
+
+	for _, port := range cmd.Ports {
+		if strconv.Itoa(int(port)) == cport {
+			return true
+		}
+	}
+
+	log.Debugf("HTTP probe - skipping command on port %s (ports=%v) => %s %s",
+		target.port, cmd.Ports, cmd.Method, cmd.Resource)
+	return false
+}
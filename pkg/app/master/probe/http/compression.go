@@ -0,0 +1,144 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+
+	defaultCompressionEncodings = "gzip, deflate, br"
+	identityEncoding            = "identity"
+
+	defaultMinCompressionRatio = 1.1
+	maxCompressionResponseSize = 16 * 1024 * 1024
+)
+
+var (
+	ErrResponseTooLarge    = errors.New("response too large")
+	ErrResponseNotEncoded  = errors.New("response not compressed")
+	ErrCompressionTooSmall = errors.New("compression ratio too small")
+)
+
+// fetchEncodedSize makes a call with the given Accept-Encoding header
+// and counts the response body bytes as received (without decoding them).
+// Setting Accept-Encoding explicitly disables the transparent gzip decoding in the transport.
+func fetchEncodedSize(client *http.Client, req *http.Request, acceptEncoding string) (int64, string, int, error) {
+	creq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		creq.Body, _ = req.GetBody()
+	}
+	creq.Header.Set(headerAcceptEncoding, acceptEncoding)
+
+	res, err := client.Do(creq)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	defer res.Body.Close()
+
+	//counting the bytes (no need to keep the response bodies in memory)
+	size, err := io.Copy(io.Discard, io.LimitReader(res.Body, maxCompressionResponseSize+1))
+	if err != nil {
+		return size, "", res.StatusCode, err
+	}
+
+	if size > maxCompressionResponseSize {
+		return size, "", res.StatusCode, fmt.Errorf("%w (limit=%d)", ErrResponseTooLarge, maxCompressionResponseSize)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return size, "", res.StatusCode, &StatusError{StatusCode: res.StatusCode, Expected: []int{http.StatusOK}}
+	}
+
+	return size, res.Header.Get(headerContentEncoding), res.StatusCode, nil
+}
+
+// compressionRoundTrip makes the 'compression' probe mode calls
+// fetching the resource with and without compression
+// to check the observed compression ratio (uncompressed size / compressed size)
+func (p *CustomProbe) compressionRoundTrip(
+	client *http.Client,
+	req *http.Request,
+	cmdIdx int,
+	port string,
+	minRatio float64) {
+	if minRatio <= 0 {
+		minRatio = defaultMinCompressionRatio
+	}
+
+	callStart := time.Now()
+	p.waitCallRate()
+	plainSize, _, _, err := fetchEncodedSize(client, req, identityEncoding)
+	var encodedSize int64
+	var encoding string
+	var statusCode int
+	if err == nil {
+		p.waitCallRate()
+		encodedSize, encoding, statusCode, err = fetchEncodedSize(client, req, defaultCompressionEncodings)
+	}
+	callDuration := time.Since(callStart)
+	atomic.AddUint64(&p.CallCount, 1)
+
+	var ratio float64
+	if err == nil {
+		switch {
+		case encoding == "" || encoding == identityEncoding:
+			err = ErrResponseNotEncoded
+		case encodedSize == 0:
+			err = ErrResponseNotEncoded
+		default:
+			ratio = float64(plainSize) / float64(encodedSize)
+			if ratio < minRatio {
+				err = fmt.Errorf("%w (%.2f < %.2f)", ErrCompressionTooSmall, ratio, minRatio)
+			}
+		}
+	}
+
+	p.addCallResult(CallResult{
+		Time:       callStart,
+		Port:       port,
+		CmdIndex:   cmdIdx,
+		Method:     req.Method,
+		Path:       req.URL.RequestURI(),
+		Target:     req.URL.String(),
+		StatusCode: statusCode,
+		Attempt:    1,
+		Duration:   callDuration,
+		Error:      errorString(err),
+	})
+
+	resultStatus := "ok"
+	callErrorStr := "none"
+	if err != nil {
+		atomic.AddUint64(&p.ErrCount, 1)
+		resultStatus = "failed"
+		callErrorStr = err.Error()
+		log.Debugf("HTTP probe - compression round trip failed (encoding='%s' ratio=%.2f) - %v", encoding, ratio, err)
+	} else {
+		atomic.AddUint64(&p.OkCount, 1)
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.call.compression",
+			ovars{
+				"status":       resultStatus,
+				"method":       req.Method,
+				"target":       req.URL.String(),
+				"encoding":     encoding,
+				"size.plain":   plainSize,
+				"size.encoded": encodedSize,
+				"ratio":        fmt.Sprintf("%.2f", ratio),
+				"min.ratio":    fmt.Sprintf("%.2f", minRatio),
+				"error":        callErrorStr,
+				"time":         time.Now().UTC().Format(time.RFC3339),
+			})
+	}
+}
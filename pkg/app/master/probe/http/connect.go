@@ -0,0 +1,157 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	defaultConnectHost = "127.0.0.1"
+)
+
+// connectCall sends a raw CONNECT request to establish a tunnel through the target
+// and checks the '200 Connection Established' response (the tunnel is closed right away).
+// CONNECT doesn't follow the regular request/response semantics
+// (the connection becomes the tunnel), so the HTTP client can't be used here.
+func (p *CustomProbe) connectCall(proto, targetHost, port, tunnelTarget string) (int, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.clientOpts.callTimeout)
+	defer cancel()
+
+	conn, err := p.clientOpts.dialContext(ctx, "tcp", net.JoinHostPort(targetHost, port))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if proto == config.ProtoHTTPS {
+		tconn := tls.Client(conn, p.clientOpts.clientTLSConfig(targetHost))
+
+		if err := tconn.HandshakeContext(ctx); err != nil {
+			return 0, err
+		}
+
+		conn = tconn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		Host:   tunnelTarget,
+		Header: http.Header{},
+	}
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", tunnelTarget, tunnelTarget); err != nil {
+		return 0, err
+	}
+
+	//only the response head is read (the rest of the connection is the tunnel)
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return res.StatusCode, &StatusError{StatusCode: res.StatusCode, Expected: []int{http.StatusOK}}
+	}
+
+	return res.StatusCode, nil
+}
+
+// connectProbe makes the 'connect' probe mode calls (returns true if the tunnel was established)
+func (p *CustomProbe) connectProbe(
+	cmdIdx int,
+	cmd config.HTTPProbeCmd,
+	proto string,
+	targetHost string,
+	port string,
+	maxRetryCount int,
+	retryWait time.Duration) bool {
+	tunnelTarget := cmd.ConnectTarget
+	if tunnelTarget == "" {
+		tunnelTarget = net.JoinHostPort(defaultConnectHost, port)
+	}
+
+	addr := getHTTPAddr(proto, targetHost, port)
+	backoff := newRetryBackoff(p.opts.RetryBackoffReset, p.opts.RetryMaxWait)
+	for i := 0; i < maxRetryCount && !p.stopped(); i++ {
+		if i > 0 && !p.takeRetry(cmdIdx, cmd) {
+			break
+		}
+
+		p.throttleCPU()
+		p.waitCallRate()
+
+		callStart := time.Now()
+		statusNum, err := p.connectCall(proto, targetHost, port, tunnelTarget)
+		callDuration := time.Since(callStart)
+		atomic.AddUint64(&p.CallCount, 1)
+
+		p.addCallResult(CallResult{
+			Time:       callStart,
+			Port:       port,
+			CmdIndex:   cmdIdx,
+			Method:     http.MethodConnect,
+			Path:       tunnelTarget,
+			Target:     addr,
+			StatusCode: statusNum,
+			Attempt:    i + 1,
+			Duration:   callDuration,
+			Error:      errorString(err),
+		})
+
+		if p.printState {
+			statusCode := "error"
+			if statusNum != 0 {
+				statusCode = fmt.Sprintf("%v", statusNum)
+			}
+
+			callErrorStr := "none"
+			if err != nil {
+				callErrorStr = err.Error()
+			}
+
+			p.xc.Out.Info("http.probe.call.connect",
+				ovars{
+					"status":  statusCode,
+					"target":  addr,
+					"tunnel":  tunnelTarget,
+					"attempt": i + 1,
+					"error":   callErrorStr,
+					"time":    time.Now().UTC().Format(time.RFC3339),
+				})
+		}
+
+		if err == nil {
+			atomic.AddUint64(&p.OkCount, 1)
+			return true
+		}
+
+		atomic.AddUint64(&p.ErrCount, 1)
+		if statusNum != 0 {
+			backoff.success()
+		}
+
+		if !isRetryableError(err, p.opts.RetryOn) {
+			log.Debugf("HTTP probe - not retrying the call (error.category=%s)", errorCategory(err))
+			return false
+		}
+
+		log.Debugf("HTTP probe - connect error (retry again later) - %v", err)
+		p.sleep(backoff.next(retryWait * time.Second))
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// cookieJars holds the probe cookie jars (one jar for each target address and port)
+// The standard cookie jars don't use the ports to scope the cookies,
+// so the separate jars keep the cookies from the unrelated services on the same host apart.
+type cookieJars struct {
+	mu   sync.Mutex
+	jars map[string]http.CookieJar
+}
+
+func (j *cookieJars) get(key string) http.CookieJar {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if jar, ok := j.jars[key]; ok {
+		return jar
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		log.Debugf("HTTP probe - cookie jar error (%s) - %v", key, err)
+		return nil
+	}
+
+	if j.jars == nil {
+		j.jars = map[string]http.CookieJar{}
+	}
+
+	log.Debugf("HTTP probe - new cookie jar (%s)", key)
+	j.jars[key] = jar
+	return jar
+}
+
+// cookieJar returns the cookie jar for the command calls to the target address and port
+// (or the Unix socket), so the cookies set by one command are sent by the later commands.
+// There's no cookie jar if the probe calls are isolated (the NoCookieJar option).
+func (p *CustomProbe) cookieJar(targetHost, port, socket string) http.CookieJar {
+	if p.opts.NoCookieJar {
+		return nil
+	}
+
+	key := net.JoinHostPort(targetHost, port)
+	if socket != "" {
+		key = "unix:" + socket
+	}
+
+	return p.cookies.get(key)
+}
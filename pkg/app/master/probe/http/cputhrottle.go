@@ -0,0 +1,138 @@
+package http
+
+import (
+	"sync"
+	"time"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultCPUThrottleThreshold = 90
+	cpuThrottleCheckWait        = 1 * time.Second
+	maxCPUThrottleWait          = 30 * time.Second
+)
+
+// cpuThrottle delays the probe calls while the target container CPU is saturated.
+// The container CPU usage is tracked with the Docker stats API
+// (the usage is a percentage of one CPU, like in 'docker stats').
+type cpuThrottle struct {
+	threshold float64
+
+	mu    sync.Mutex
+	usage float64
+	known bool
+
+	done chan bool
+}
+
+func newCPUThrottle(apiClient *dockerapi.Client, containerID string, threshold int) *cpuThrottle {
+	if threshold <= 0 {
+		threshold = defaultCPUThrottleThreshold
+	}
+
+	t := &cpuThrottle{
+		threshold: float64(threshold),
+		done:      make(chan bool),
+	}
+
+	statsChan := make(chan *dockerapi.Stats)
+	go func() {
+		err := apiClient.Stats(dockerapi.StatsOptions{
+			ID:     containerID,
+			Stats:  statsChan,
+			Stream: true,
+			Done:   t.done,
+		})
+		if err != nil {
+			log.Debugf("HTTP probe - container stats error - %v", err)
+		}
+	}()
+
+	go func() {
+		for stats := range statsChan {
+			if usage, ok := cpuUsagePercent(stats); ok {
+				t.mu.Lock()
+				t.usage = usage
+				t.known = true
+				t.mu.Unlock()
+			}
+		}
+	}()
+
+	return t
+}
+
+func cpuUsagePercent(stats *dockerapi.Stats) (float64, bool) {
+	if stats == nil {
+		return 0, false
+	}
+
+	cpuTotal := stats.CPUStats.CPUUsage.TotalUsage
+	cpuPreTotal := stats.PreCPUStats.CPUUsage.TotalUsage
+	system := stats.CPUStats.SystemCPUUsage
+	systemPre := stats.PreCPUStats.SystemCPUUsage
+	if cpuTotal < cpuPreTotal || system <= systemPre {
+		return 0, false
+	}
+
+	cpuCount := stats.CPUStats.OnlineCPUs
+	if cpuCount == 0 {
+		cpuCount = uint64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuCount == 0 {
+		cpuCount = 1
+	}
+
+	cpuDelta := float64(cpuTotal - cpuPreTotal)
+	systemDelta := float64(system - systemPre)
+	return cpuDelta / systemDelta * float64(cpuCount) * 100.0, true
+}
+
+func (t *cpuThrottle) current() (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage, t.known
+}
+
+// throttleCPU blocks while the container CPU usage is above the threshold
+// (the wait time is limited, so the probe still makes progress)
+func (p *CustomProbe) throttleCPU() {
+	t := p.cpuThrottle
+	if t == nil {
+		return
+	}
+
+	var waited time.Duration
+	for waited < maxCPUThrottleWait {
+		usage, known := t.current()
+		if !known || usage < t.threshold {
+			break
+		}
+
+		if waited == 0 && p.printState {
+			p.xc.Out.Info("http.probe.cpu.throttle",
+				ovars{
+					"usage":     int(usage),
+					"threshold": int(t.threshold),
+				})
+		}
+
+		if !p.sleep(cpuThrottleCheckWait) {
+			break
+		}
+		waited += cpuThrottleCheckWait
+	}
+
+	if waited > 0 {
+		log.Debugf("HTTP probe - CPU throttle wait => %v", waited)
+	}
+}
+
+func (t *cpuThrottle) stop() {
+	if t != nil {
+		close(t.done)
+	}
+}
@@ -1,15 +1,17 @@
 package http
 
 import (
+	"encoding/json"
+	"mime"
 	"net/http"
 	"net/http/cookiejar"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gocolly/colly/v2"
 	log "github.com/sirupsen/logrus"
-
-	"github.com/slimtoolkit/slim/pkg/app/master/config"
 )
 
 const (
@@ -19,21 +21,83 @@ const (
 	defaultMaxConcurrentCrawlers = 1
 )
 
-func (p *CustomProbe) crawl(proto, domain, addr string) {
+// crawlable returns true if the crawler can follow the links in the call response
+// (the successful GET calls with an HTML or JSON response)
+func crawlable(method string, res *http.Response) bool {
+	if method != http.MethodGet || res == nil {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get(headerContentType))
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml" || isJSONContentType(mediaType)
+}
+
+// jsonLinks returns the link values in the JSON document
+// (the absolute http/https URLs and the absolute paths in the string values)
+func jsonLinks(data []byte) []string {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
 
-	var httpClient *http.Client
-	if strings.HasPrefix(proto, config.ProtoHTTP2) {
-		var err error
-		if httpClient, err = getHTTPClient(proto); err != nil {
-			p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
-			return
+	var links []string
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for _, field := range v {
+				walk(field)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			if isJSONLink(v) {
+				links = append(links, v)
+			}
 		}
+	}
+
+	walk(doc)
+	//predictable crawl order
+	sort.Strings(links)
+	return links
+}
+
+func isJSONLink(value string) bool {
+	if value == "" || strings.ContainsAny(value, " \t\n") {
+		return false
+	}
+
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return true
+	}
+
+	//the protocol relative URLs are not followed (they are usually the other hosts)
+	return strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//")
+}
 
-		httpClient.Timeout = 10 * time.Second //matches the timeout used by Colly
-		jar, _ := cookiejar.New(nil)
-		httpClient.Jar = jar
+// crawl follows the links (pages, scripts, styles and images) discovered
+// in the target HTML pages and the links in the JSON responses
+// (the same host URLs, each URL is visited once)
+func (p *CustomProbe) crawl(proto, domain, addr string) {
+	//the crawler uses the probe client, so the probe client options
+	//(e.g., the TLS, DNS and header limit settings) apply to the crawled pages too
+	httpClient, err := getHTTPClient(proto, p.clientOpts)
+	if err != nil {
+		p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
+		return
 	}
 
+	httpClient.Timeout = 10 * time.Second //matches the timeout used by Colly
+	jar, _ := cookiejar.New(nil)
+	httpClient.Jar = jar
+
 	if p.opts.CrawlConcurrencyMax > 0 &&
 		p.concurrentCrawlers != nil {
 		p.concurrentCrawlers <- struct{}{}
@@ -50,17 +114,19 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 			p.workers.Done()
 		}()
 
-		var pageCount int
+		//the async collector callbacks run in parallel
+		var pageCount int64
 
 		c := colly.NewCollector()
 		c.UserAgent = "ds.crawler"
+		if p.opts.UserAgent != "" {
+			c.UserAgent = p.opts.UserAgent
+		}
 		c.IgnoreRobotsTxt = true
 		c.Async = true
 		c.AllowedDomains = []string{domain}
 		c.AllowURLRevisit = false
-		if httpClient != nil {
-			c.SetClient(httpClient)
-		}
+		c.SetClient(httpClient)
 
 		if p.opts.CrawlMaxDepth > 0 {
 			c.MaxDepth = p.opts.CrawlMaxDepth
@@ -75,7 +141,7 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 
 		c.OnHTML("a[href]", func(e *colly.HTMLElement) {
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				log.Debugf("http.CustomProbe.crawl.OnHTML(a[href]) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
 				return
 			}
@@ -85,7 +151,7 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 
 		c.OnHTML("link[href]", func(e *colly.HTMLElement) {
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				log.Debugf("http.CustomProbe.crawl.OnHTML(link[href]) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
 				return
 			}
@@ -100,7 +166,7 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 
 		c.OnHTML("script[src], source[src], img[src]", func(e *colly.HTMLElement) {
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				log.Debugf("http.CustomProbe.crawl.OnHTML(script/source/img) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
 				return
 			}
@@ -110,7 +176,7 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 
 		c.OnHTML("source[srcset]", func(e *colly.HTMLElement) {
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				log.Debugf("http.CustomProbe.crawl.OnHTML(source[srcset]) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
 				return
 			}
@@ -120,7 +186,7 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 
 		c.OnHTML("[data-src]", func(e *colly.HTMLElement) {
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				log.Debugf("http.CustomProbe.crawl.OnHTML([data-src]) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
 				return
 			}
@@ -128,15 +194,32 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 			e.Request.Visit(e.Attr("data-src"))
 		})
 
+		c.OnResponse(func(r *colly.Response) {
+			mediaType, _, err := mime.ParseMediaType(r.Headers.Get(headerContentType))
+			if err != nil || !isJSONContentType(mediaType) {
+				return
+			}
+
+			for _, link := range jsonLinks(r.Body) {
+				if p.opts.CrawlMaxPageCount > 0 &&
+					atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
+					log.Debugf("http.CustomProbe.crawl.OnResponse(json) - reached max page count, ignoring link (%v)", p.opts.CrawlMaxPageCount)
+					return
+				}
+
+				r.Request.Visit(link)
+			}
+		})
+
 		c.OnRequest(func(r *colly.Request) {
 			p.xc.Out.Info("http.probe.crawler",
 				ovars{
-					"page": pageCount,
+					"page": atomic.LoadInt64(&pageCount),
 					"url":  r.URL,
 				})
 
 			if p.opts.CrawlMaxPageCount > 0 &&
-				pageCount > p.opts.CrawlMaxPageCount {
+				atomic.LoadInt64(&pageCount) > int64(p.opts.CrawlMaxPageCount) {
 				p.xc.Out.Info("http.probe.crawler.stop",
 					ovars{
 						"reason": "reached max visits",
@@ -147,7 +230,15 @@ func (p *CustomProbe) crawl(proto, domain, addr string) {
 				return
 			}
 
-			pageCount++
+			if p.stopped() {
+				log.Debug("http.CustomProbe.crawl.OnRequest - probe stopped")
+				r.Abort()
+				return
+			}
+
+			setDefaultHeaders(*r.Headers, p.defaultHeaders)
+			atomic.AddInt64(&pageCount, 1)
+			p.waitCallRate()
 		})
 
 		c.OnError(func(_ *colly.Response, err error) {
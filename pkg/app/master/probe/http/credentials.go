@@ -0,0 +1,150 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const headerAuthorization = "Authorization"
+
+var ErrUnauthorized = errors.New("unauthorized with all fallback credentials")
+
+// authCall is the result of the call retried with the fallback credentials
+type authCall struct {
+	res        *http.Response
+	err        error
+	credential string
+	start      time.Time
+	duration   time.Duration
+}
+
+// setCredential sets the Authorization header for the credential set
+// (the credential values can be the secret references)
+func setCredential(req *http.Request, cred config.HTTPProbeCredential) {
+	req.Header.Del(headerAuthorization)
+	if cred.Token != "" {
+		token, err := resolveSecretRefs(cred.Token)
+		if err != nil {
+			log.Debugf("HTTP probe - credential '%s' token error - %v", cred.Name, err)
+			return
+		}
+
+		req.Header.Set(headerAuthorization, "Bearer "+token)
+		return
+	}
+
+	username, err := resolveSecretRefs(cred.Username)
+	if err != nil {
+		log.Debugf("HTTP probe - credential '%s' username error - %v", cred.Name, err)
+		return
+	}
+
+	password, err := resolveSecretRefs(cred.Password)
+	if err != nil {
+		log.Debugf("HTTP probe - credential '%s' password error - %v", cred.Name, err)
+		return
+	}
+
+	req.SetBasicAuth(username, password)
+}
+
+// cmdCredential returns the index of the fallback credential set that worked for the command
+// (returns -1 if the command uses its own credentials)
+func (p *CustomProbe) cmdCredential(cmdIdx int) int {
+	p.credentialsMu.Lock()
+	defer p.credentialsMu.Unlock()
+
+	if idx, ok := p.cmdCredentials[cmdIdx]; ok {
+		return idx
+	}
+
+	return -1
+}
+
+func (p *CustomProbe) setCmdCredential(cmdIdx, credIdx int) {
+	p.credentialsMu.Lock()
+	defer p.credentialsMu.Unlock()
+
+	if p.cmdCredentials == nil {
+		p.cmdCredentials = map[int]int{}
+	}
+
+	p.cmdCredentials[cmdIdx] = credIdx
+}
+
+// authFallback retries the unauthorized call with the next fallback credential sets (in order)
+// until the target doesn't respond with 401. The unauthorized calls are recorded as failed calls.
+func (p *CustomProbe) authFallback(
+	client *http.Client,
+	req *http.Request,
+	rbSeeker io.Seeker,
+	cmdIdx int,
+	credIdx int,
+	call CallResult,
+	res *http.Response) authCall {
+	creds := p.opts.FallbackCredentials
+	last := authCall{res: res, start: call.Time, duration: call.Duration}
+	if credIdx >= 0 {
+		last.credential = creds[credIdx].Name
+	}
+
+	//the command credentials are used for the first call
+	credentialName := func(name string) string {
+		if name == "" {
+			return "command"
+		}
+
+		return name
+	}
+
+	for idx := credIdx + 1; idx < len(creds) && !p.stopped(); idx++ {
+		io.Copy(io.Discard, last.res.Body)
+		last.res.Body.Close()
+
+		call.Time = last.start
+		call.Duration = last.duration
+		call.StatusCode = last.res.StatusCode
+		call.Credential = last.credential
+		call.Error = fmt.Sprintf("unauthorized (credential=%s), trying the next credential set", credentialName(last.credential))
+		p.addCallResult(call)
+		atomic.AddUint64(&p.ErrCount, 1)
+		log.Debugf("HTTP probe - unauthorized call (%s %s) - trying the fallback credential set '%s'",
+			call.Method, call.Target, creds[idx].Name)
+
+		creq := req.Clone(p.ctx)
+		setCredential(creq, creds[idx])
+		p.waitCallRate()
+		start := time.Now()
+		lres, err := client.Do(creq)
+		last = authCall{
+			res:        lres,
+			err:        err,
+			credential: creds[idx].Name,
+			start:      start,
+			duration:   time.Since(start),
+		}
+		atomic.AddUint64(&p.CallCount, 1)
+		rewindBody(rbSeeker)
+
+		if err != nil || lres.StatusCode != http.StatusUnauthorized {
+			if err == nil {
+				p.setCmdCredential(cmdIdx, idx)
+			}
+			break
+		}
+	}
+
+	if last.err == nil && last.res.StatusCode == http.StatusUnauthorized {
+		last.err = ErrUnauthorized
+	}
+
+	return last
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,15 +11,19 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dockerapi "github.com/fsouza/go-dockerclient"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 
 	"github.com/slimtoolkit/slim/pkg/app"
 	"github.com/slimtoolkit/slim/pkg/app/master/config"
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
 	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/pod"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe/exec"
 )
 
 const (
@@ -37,22 +42,107 @@ type CustomProbe struct {
 
 	opts config.HTTPProbeOptions
 
-	ports      []string
-	targetHost string
+	ports []string
+	//the exposed non-tcp ports (with the protocol, e.g., '53/udp'), they are not probed
+	nonTCPPorts []string
+	targetHost  string
+	targetHosts []string
+	//the container ports for the probed host ports (the probed port -> the container port)
+	containerPorts map[string]string
+	//the container IP address probed when the published host ports fail (not probed if it's empty)
+	containerIP string
+
+	//the headers for all probe calls (the command headers override them)
+	defaultHeaders []string
+
+	//the number of the duplicate commands and ports collapsed before the probe calls
+	collapsedCmds  int
+	collapsedPorts int
 
 	APISpecProbes []apiSpecInfo
 
 	printState bool
+	startWait  time.Duration
+	//the target ports are not polled before the probe calls if it's zero
+	portReadyTimeout  time.Duration
+	portReadyInterval time.Duration
+
+	clientOpts *clientOptions
+
+	resultsMu sync.Mutex
+	results   []CallResult
+
+	skippedMu   sync.Mutex
+	skippedCmds []SkippedCmd
+
+	groupsMu     sync.Mutex
+	groupResults []GroupStageResult
+
+	localesMu     sync.Mutex
+	localeResults []LocaleResult
+
+	varsMu sync.Mutex
+	vars   map[int]map[string]string //variable scope -> captured variables
+
+	credentialsMu  sync.Mutex
+	cmdCredentials map[int]int
+
+	//the exec command runner for the target container (nil if there's no target container)
+	execRunner  exec.Runner
+	execMu      sync.Mutex
+	execResults map[int]bool
+
+	cpuThrottle *cpuThrottle
+	callLimiter *rate.Limiter
+	fakeData    *fakeDataGenerator
+	retryBudget *retryBudget
+	gauges      concurrencyGauges
+	keepAlive   keepAliveStats
+	cookies     cookieJars
+	schemes     portSchemes
+	events      eventLog
+
+	screenshotCount uint64
+
+	cmdCount    uint64
+	cmdErrCount uint64
+
+	cmdResultsMu  sync.Mutex
+	cmdOK         map[string]bool
+	requiredOK    map[string]bool
+	resultAsserts []ResultAssertionResult
+	customAsserts []namedResultAssertion
+
+	headerLimitCount uint64
 
 	CallCount uint64
 	ErrCount  uint64
 	OkCount   uint64
 
+	baseURLOkCount  uint64
+	targetProbed    uint32
+	canceled        uint32
+	preflightFailed uint32
+	maxTimeReached  uint32
+
+	//stops the probe when the probe max time is reached (started when the probe starts)
+	maxTimer *time.Timer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	doneChan           chan struct{}
 	workers            sync.WaitGroup
 	concurrentCrawlers chan struct{}
+	//the probe command worker pool (shared by all probe targets)
+	cmdWorkers chan struct{}
+
+	progressOnce sync.Once
+	progress     chan ProgressEvent
 }
 
+var _ probe.Probe = (*CustomProbe)(nil)
+
 // NewEndpointProbe creates a new custom HTTP probe for an endpoint
 func NewEndpointProbe(
 	xc *app.ExecutionContext,
@@ -61,7 +151,11 @@ func NewEndpointProbe(
 	opts config.HTTPProbeOptions,
 	printState bool,
 ) (*CustomProbe, error) {
-	probe := newCustomProbe(xc, targetEndpoint, opts, printState)
+	probe, err := newCustomProbe(xc, targetEndpoint, opts, printState)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(ports) == 0 {
 		ports = []uint{80}
 	}
@@ -84,7 +178,18 @@ func NewContainerProbe(
 	opts config.HTTPProbeOptions,
 	printState bool,
 ) (*CustomProbe, error) {
-	probe := newCustomProbe(xc, inspector.TargetHost, opts, printState)
+	probe, err := newCustomProbe(xc, inspector.TargetHost, opts, printState)
+	if err != nil {
+		return nil, err
+	}
+
+	if imageInfo := inspector.ImageInspector.ImageInfo; imageInfo != nil {
+		probe.filterPlatformCmds(imageInfo.OS, imageInfo.Architecture)
+	}
+
+	if probe.opts.UseHealthcheck {
+		probe.addHealthcheckCmd(inspector.ImageInspector.DockerfileInfo, inspector.ImageInspector.ImageInfo)
+	}
 
 	availableHostPorts := map[string]string{}
 	for nsPortKey, nsPortData := range inspector.AvailablePorts {
@@ -106,18 +211,7 @@ func NewContainerProbe(
 	log.Debugf("HTTP probe - available host ports => %+v", availableHostPorts)
 
 	if len(probe.opts.Ports) > 0 {
-		for _, pnum := range probe.opts.Ports {
-			pspec := dockerapi.Port(fmt.Sprintf("%v/tcp", pnum))
-			if _, ok := inspector.AvailablePorts[pspec]; ok {
-				if inspector.SensorIPCMode == container.SensorIPCModeDirect {
-					probe.ports = append(probe.ports, fmt.Sprintf("%d", pnum))
-				} else {
-					probe.ports = append(probe.ports, inspector.AvailablePorts[pspec].HostPort)
-				}
-			} else {
-				log.Debugf("HTTP probe - ignoring port => %v", pspec)
-			}
-		}
+		probe.ports = probe.selectedPorts(inspector.AvailablePorts, inspector.SensorIPCMode == container.SensorIPCModeDirect)
 		log.Debugf("HTTP probe - filtered ports => %+v", probe.ports)
 	} else {
 		//order the port list based on the order of the 'EXPOSE' instructions
@@ -129,6 +223,11 @@ func NewContainerProbe(
 				}
 
 				pspec := dockerapi.Port(portInfo)
+				if pspec.Proto() != "tcp" {
+					log.Debugf("HTTP probe - skipping non-tcp exposed port => %v", portInfo)
+					probe.nonTCPPorts = append(probe.nonTCPPorts, portInfo)
+					continue
+				}
 
 				if _, ok := inspector.AvailablePorts[pspec]; ok {
 					hostPort := inspector.AvailablePorts[pspec].HostPort
@@ -152,9 +251,15 @@ func NewContainerProbe(
 			}
 		}
 
-		for hostPort, containerPort := range availableHostPorts {
+		if len(inspector.ImageInspector.DockerfileInfo.ExposedPorts) == 0 && probe.opts.RequireExplicitPorts {
+			if err := checkFallbackPorts(availableHostPorts); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, hostPort := range orderFallbackPorts(availableHostPorts) {
 			if inspector.SensorIPCMode == container.SensorIPCModeDirect {
-				probe.ports = append(probe.ports, containerPort)
+				probe.ports = append(probe.ports, availableHostPorts[hostPort])
 			} else {
 				probe.ports = append(probe.ports, hostPort)
 			}
@@ -163,6 +268,39 @@ func NewContainerProbe(
 		log.Debugf("HTTP probe - probe.Ports => %+v", probe.ports)
 	}
 
+	if inspector.SensorIPCMode != container.SensorIPCModeDirect {
+		probe.setContainerPorts(inspector.AvailablePorts)
+	}
+
+	if probe.opts.ContainerIP {
+		if inspector.SensorIPCMode == container.SensorIPCModeDirect {
+			log.Debugf("HTTP probe - the container address is already probed directly (ipc.mode=%s)",
+				inspector.SensorIPCMode)
+		} else if probe.containerIP = containerIP(inspector); probe.containerIP == "" {
+			log.Debug("HTTP probe - no container IP address to probe")
+		} else {
+			log.Debugf("HTTP probe - container IP address => %s", probe.containerIP)
+		}
+	}
+
+	if probe.opts.AllAddresses {
+		if inspector.SensorIPCMode == container.SensorIPCModeDirect {
+			probe.targetHosts = containerAddresses(probe.targetHost, inspector)
+			log.Debugf("HTTP probe - target addresses => %+v", probe.targetHosts)
+		} else {
+			log.Debugf("HTTP probe - probing all container addresses requires the direct sensor IPC mode (ipc.mode=%s)",
+				inspector.SensorIPCMode)
+		}
+	}
+
+	if runner, err := exec.NewContainerRunner(inspector.APIClient, inspector.ContainerID); err == nil {
+		probe.execRunner = runner
+	}
+
+	if probe.opts.CPUThrottle && inspector.APIClient != nil && inspector.ContainerID != "" {
+		probe.cpuThrottle = newCPUThrottle(inspector.APIClient, inspector.ContainerID, probe.opts.CPUThrottleThreshold)
+	}
+
 	if len(probe.opts.APISpecFiles) > 0 {
 		probe.loadAPISpecFiles()
 	}
@@ -176,7 +314,10 @@ func NewPodProbe(
 	opts config.HTTPProbeOptions,
 	printState bool,
 ) (*CustomProbe, error) {
-	probe := newCustomProbe(xc, inspector.TargetHost(), opts, printState)
+	probe, err := newCustomProbe(xc, inspector.TargetHost(), opts, printState)
+	if err != nil {
+		return nil, err
+	}
 
 	availableHostPorts := map[string]string{}
 	for nsPortKey, nsPortData := range inspector.AvailablePorts() {
@@ -187,24 +328,20 @@ func NewPodProbe(
 	log.Debugf("HTTP probe - available host ports => %+v", availableHostPorts)
 
 	if len(probe.opts.Ports) > 0 {
-		for _, pnum := range probe.opts.Ports {
-			pspec := dockerapi.Port(fmt.Sprintf("%v/tcp", pnum))
-			if port, ok := inspector.AvailablePorts()[pspec]; ok {
-				probe.ports = append(probe.ports, port.HostPort)
-			} else {
-				log.Debugf("HTTP probe - ignoring port => %v", pspec)
-			}
-		}
-
+		probe.ports = probe.selectedPorts(inspector.AvailablePorts(), false)
 		log.Debugf("HTTP probe - filtered ports => %+v", probe.ports)
 	} else {
 		for hostPort := range availableHostPorts {
 			probe.ports = append(probe.ports, hostPort)
 		}
 
+		sortPorts(probe.ports)
+
 		log.Debugf("HTTP probe - probe.Ports => %+v", probe.ports)
 	}
 
+	probe.setContainerPorts(inspector.AvailablePorts())
+
 	if len(probe.opts.APISpecFiles) > 0 {
 		probe.loadAPISpecFiles()
 	}
@@ -217,7 +354,7 @@ func newCustomProbe(
 	targetHost string,
 	opts config.HTTPProbeOptions,
 	printState bool,
-) *CustomProbe {
+) (*CustomProbe, error) {
 	//note: the default probe should already be there if the user asked for it
 
 	//-1 means disabled
@@ -240,44 +377,128 @@ func newCustomProbe(
 		opts.CrawlConcurrencyMax = defaultMaxConcurrentCrawlers
 	}
 
+	if len(opts.RetryOn) == 0 {
+		opts.RetryOn = config.DefaultProbeRetryOn
+	}
+
+	for _, spec := range opts.APISpecCmds {
+		specCmds, err := LoadCmdsFromAPISpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("error loading the probe commands from API spec (%s): %w", spec, err)
+		}
+
+		log.Debugf("HTTP probe - API spec commands (%s) => %d", spec, len(specCmds))
+		opts.Cmds = append(opts.Cmds, specCmds...)
+	}
+
+	if err := checkDuplicateCmds(xc, opts, printState); err != nil {
+		return nil, err
+	}
+
+	//the duplicate commands are reported (above), but they are called only once
+	uniqueCmds, collapsedCmds := collapseDuplicateCmds(opts.Cmds)
+	opts.Cmds = uniqueCmds
+
+	cmds, err := orderProbeCmds(opts.Cmds)
+	if err != nil {
+		return nil, err
+	}
+	opts.Cmds = cmds
+
+	var startWait time.Duration
+	if opts.StartWait > 0 {
+		startWait = opts.StartWait
+	}
+
+	portReadyInterval := defaultPortReadyPollInterval
+	if opts.PortReadyInterval > 0 {
+		portReadyInterval = opts.PortReadyInterval
+	}
+
+	portReadyTimeout := defaultPortReadyTimeout
+	switch {
+	case opts.PortReadyTimeout > 0:
+		portReadyTimeout = opts.PortReadyTimeout
+	case opts.PortReadyTimeout < 0:
+		portReadyTimeout = 0
+	}
+
+	if opts.Host != "" {
+		//the target host override (e.g., for the remote Docker daemons)
+		log.Debugf("HTTP probe - target host override (%s) => %s", targetHost, opts.Host)
+		targetHost = opts.Host
+	}
+
 	probe := &CustomProbe{
 		xc:         xc,
 		opts:       opts,
 		printState: printState,
-		targetHost: targetHost,
+		startWait:  startWait,
+		//the IPv6 target addresses are bracketed when the call addresses are constructed
+		targetHost: strings.Trim(targetHost, "[]"),
 		doneChan:   make(chan struct{}),
+		clientOpts: newClientOptions(opts),
+		fakeData:   newFakeDataGenerator(opts.DataSeed),
+
+		portReadyTimeout:  portReadyTimeout,
+		portReadyInterval: portReadyInterval,
+		collapsedCmds:     collapsedCmds,
+		defaultHeaders:    defaultHeaders(opts),
 	}
 
+	probe.ctx, probe.cancel = newProbeContext(opts.Deadline)
+
+	if opts.DNSServer != "" {
+		log.Debugf("HTTP probe - using custom DNS server => %s", opts.DNSServer)
+	}
+
+	if err := probe.clientOpts.initCassette(opts); err != nil {
+		return nil, err
+	}
+
+	if err := probe.clientOpts.initTLS(opts); err != nil {
+		return nil, err
+	}
+
+	probe.initPcapOutput()
+
+	if opts.Concurrency > 1 {
+		probe.cmdWorkers = make(chan struct{}, opts.Concurrency)
+	}
+
+	probe.callLimiter = newCallLimiter(opts.RateLimit)
+
 	if opts.CrawlConcurrencyMax > 0 {
 		probe.concurrentCrawlers = make(chan struct{}, opts.CrawlConcurrencyMax)
 	}
 
-	return probe
+	return probe, nil
 }
 
 func (p *CustomProbe) Ports() []string {
 	return p.ports
 }
 
+// NonTCPPorts returns the exposed non-tcp ports with their protocols (e.g., '53/udp')
+// The HTTP probe doesn't probe them.
+func (p *CustomProbe) NonTCPPorts() []string {
+	return p.nonTCPPorts
+}
+
 // Start starts the HTTP probe instance execution
 func (p *CustomProbe) Start() {
-	if p.printState {
-		p.xc.Out.State("http.probe.starting",
-			ovars{
-				"message": "WAIT FOR HTTP PROBE TO FINISH",
-			})
-	}
+	p.startMaxTime()
+	p.publish(ProgressEvent{Type: ProgressEventStarted})
 
 	go func() {
-		//TODO: need to do a better job figuring out if the target app is ready to accept connections
-		time.Sleep(9 * time.Second) //base start wait time
-		if p.opts.StartWait > 0 {
+		//the target ports are polled until they accept connections before the probe calls,
+		//so the start wait is only needed for the apps that are not ready when their ports are open
+		if p.startWait > 0 && !p.opts.DryRun {
 			if p.printState {
-				p.xc.Out.State("http.probe.start.wait", ovars{"time": p.opts.StartWait})
+				p.xc.Out.State("http.probe.start.wait", ovars{"time": p.startWait.String()})
 			}
 
-			//additional wait time
-			time.Sleep(time.Duration(p.opts.StartWait) * time.Second)
+			p.sleep(p.startWait)
 
 			if p.printState {
 				p.xc.Out.State("http.probe.start.wait.done")
@@ -290,6 +511,8 @@ func (p *CustomProbe) Start() {
 
 		log.Info("HTTP probe started...")
 
+		p.collapseDuplicatePorts()
+
 		findIdx := func(ports []string, target string) int {
 			for idx, val := range ports {
 				if val == target {
@@ -316,10 +539,50 @@ func (p *CustomProbe) Start() {
 					"targets": strings.Join(p.ports, ","),
 				})
 
+			if len(p.targetHosts) > 1 {
+				p.xc.Out.Info("http.probe.addresses",
+					ovars{
+						"count":   len(p.targetHosts),
+						"targets": strings.Join(p.targetHosts, ","),
+					})
+			}
+
+			if sockets := socketTargets(p.opts.Cmds); len(sockets) > 0 {
+				var paths []string
+				for _, target := range sockets {
+					paths = append(paths, target.socket)
+				}
+
+				p.xc.Out.Info("http.probe.sockets",
+					ovars{
+						"count":   len(paths),
+						"targets": strings.Join(paths, ","),
+					})
+			}
+
+			if p.collapsedCmds > 0 || p.collapsedPorts > 0 {
+				p.xc.Out.Info("http.probe.duplicates.collapsed",
+					ovars{
+						"commands": p.collapsedCmds,
+						"ports":    p.collapsedPorts,
+					})
+			}
+
+			if p.opts.DNSServer != "" {
+				p.xc.Out.Info("http.probe.dns.server",
+					ovars{
+						"address": p.opts.DNSServer,
+					})
+			}
+
 			var cmdListPreview []string
 			var cmdListTail string
 			for idx, c := range p.opts.Cmds {
-				cmdListPreview = append(cmdListPreview, fmt.Sprintf("%s %s", c.Method, c.Resource))
+				if isExecCmd(c) {
+					cmdListPreview = append(cmdListPreview, fmt.Sprintf("%s %s", execCmdMethod, execCmdString(c)))
+				} else {
+					cmdListPreview = append(cmdListPreview, fmt.Sprintf("%s %s", c.Method, c.Resource))
+				}
 				if idx == 2 {
 					cmdListTail = ",..."
 					break
@@ -332,284 +595,712 @@ func (p *CustomProbe) Start() {
 					"count":    len(p.opts.Cmds),
 					"commands": cmdInfo,
 				})
+
+			if hasDataTemplates(p.opts.Cmds) {
+				p.xc.Out.Info("http.probe.data.seed",
+					ovars{
+						"seed": p.fakeData.seed,
+					})
+			}
+
+			for _, skipped := range p.skippedCmds {
+				p.xc.Out.Info("http.probe.command.skipped",
+					ovars{
+						"method":   skipped.Cmd.Method,
+						"resource": skipped.Cmd.Resource,
+						"reason":   skipped.Reason,
+					})
+			}
 		}
 
-		for _, port := range p.ports {
-			//If it's ok stop after the first successful probe pass
-			if p.OkCount > 0 && !p.opts.Full {
-				break
+		if p.opts.DryRun {
+			//the dry run probes only print the probe plan (no probe calls and no probe outputs)
+			if p.printState {
+				p.printPlan()
 			}
 
-			for _, cmd := range p.opts.Cmds {
-				var reqBody io.Reader
-				var rbSeeker io.Seeker
+			log.Info("HTTP probe dry run done.")
+			p.cpuThrottle.stop()
+			p.stopMaxTime()
+			p.cancel()
+			p.closeEvents()
+			close(p.doneChan)
+			return
+		}
+
+		//the app data is seeded before the probe commands, so they exercise the real code paths
+		p.seed()
+
+		//the preflight commands run before the retry budget is created (they don't use it)
+		p.preflight()
+
+		//the command list is final here (e.g., the platform specific commands are already filtered)
+		p.retryBudget = newRetryBudget(p.opts.TotalRetryBudget, p.opts.Cmds)
+
+		stopHeartbeat := p.startConcurrencyHeartbeat()
+		switch {
+		case atomic.LoadUint32(&p.preflightFailed) == 1:
+			log.Debug("HTTP probe - skipping the probe commands (failed preflight command)")
+		case p.opts.Concurrency > 1:
+			p.probeTargetsParallel()
+		default:
+			okHosts := map[string]bool{}
+			//the host ports with the successful probe passes (their container IP targets are skipped)
+			okPorts := map[string]bool{}
+			for targetIdx, target := range p.probeTargets() {
+				if p.stopped() {
+					break
+				}
 
-				if cmd.BodyFile != "" {
-					_, err := os.Stat(cmd.BodyFile)
-					if err != nil {
-						log.Errorf("http.probe - cmd.BodyFile (%s) check error: %v", cmd.BodyFile, err)
-					} else {
-						bodyFile, err := os.Open(cmd.BodyFile)
-						if err != nil {
-							log.Errorf("http.probe - cmd.BodyFile (%s) read error: %v", cmd.BodyFile, err)
-						} else {
-							reqBody = bodyFile
-							rbSeeker = bodyFile
-							//the file will be closed only when the function exits
-							defer bodyFile.Close()
-						}
+				if target.socket != "" {
+					//each Unix socket is a single probe target
+					if p.socketReady(target.socket) {
+						p.probeCmds(targetIdx, target)
 					}
-				} else {
-					strBody := strings.NewReader(cmd.Body)
-					reqBody = strBody
-					rbSeeker = strBody
+					continue
 				}
 
-				// TODO: need a smarter and more dynamic way to determine the actual protocol type
+				//If it's ok stop after the first successful probe pass (for each target address)
+				if okHosts[target.host] && !p.opts.Full {
+					continue
+				}
 
-				// Set up FastCGI defaults if the default CGI port is used without a FastCGI config.
-				if port == defaultFastCGIPortStr && cmd.FastCGI == nil {
-					log.Debugf("HTTP probe - FastCGI default port (%s) used, setting up HTTP probe FastCGI wrapper defaults", port)
+				//the container IP address is the fallback for the host port target
+				if target.hostPort != "" &&
+					(okPorts[target.hostPort] || (okHosts[p.targetHost] && !p.opts.Full)) {
+					continue
+				}
 
-					// Typicall the entrypoint into a PHP app.
-					if cmd.Resource == "/" {
-						cmd.Resource = "/index.php"
-					}
+				if !p.portReady(target.host, target.port) {
+					continue
+				}
 
-					// SplitPath is typically on the first .php path element.
-					var splitPath []string
-					if phpIdx := strings.Index(cmd.Resource, ".php"); phpIdx != -1 {
-						splitPath = []string{cmd.Resource[:phpIdx+4]}
-					}
+				okCount := p.targetOkCount()
 
-					cmd.FastCGI = &config.FastCGIProbeWrapperConfig{
-						// /var/www is a typical root for PHP indices.
-						Root:      "/var/www",
-						SplitPath: splitPath,
-					}
-				}
+				p.probeCmds(targetIdx, target)
 
-				var protocols []string
-				if cmd.Protocol == "" {
-					switch port {
-					case defaultHTTPPortStr:
-						protocols = []string{config.ProtoHTTP}
-					case defaultHTTPSPortStr:
-						protocols = []string{config.ProtoHTTPS}
-					default:
-						protocols = []string{config.ProtoHTTP, config.ProtoHTTPS}
+				if p.targetOkCount() > okCount {
+					okHosts[target.host] = true
+					if target.hostPort != "" {
+						//the fallback worked for the target host
+						okHosts[p.targetHost] = true
+					} else {
+						okPorts[target.port] = true
 					}
-				} else {
-					protocols = []string{cmd.Protocol}
+
+					p.printTargetAddress(target)
 				}
+			}
+		}
 
-				for _, proto := range protocols {
-					maxRetryCount := probeRetryCount
-					if p.opts.RetryCount > 0 {
-						maxRetryCount = p.opts.RetryCount
-					}
+		stopHeartbeat()
+		log.Info("HTTP probe done.")
+		p.printCanceled()
+		p.printDeadlineReached()
+		p.checkResultAssertions()
 
-					notReadyErrorWait := time.Duration(16)
-					webErrorWait := time.Duration(8)
-					otherErrorWait := time.Duration(4)
-					if p.opts.RetryWait > 0 {
-						webErrorWait = time.Duration(p.opts.RetryWait)
-						notReadyErrorWait = time.Duration(p.opts.RetryWait * 2)
-						otherErrorWait = time.Duration(p.opts.RetryWait / 2)
-					}
+		summary := p.progressSummary()
+		p.publish(ProgressEvent{Type: ProgressEventSummary, Summary: summary})
 
-					if IsValidWSProto(proto) {
-						wc, err := NewWebsocketClient(proto, p.targetHost, port)
-						if err != nil {
-							log.Debugf("HTTP probe - new websocket error - %v", err)
-							continue
-						}
+		if p.printState {
+			p.printAddressSummary()
+			p.printSocketSummary()
+			p.printTimings()
+			p.printNotFoundRoutes()
+			p.printGroupSummary()
+			p.printLocaleSummary()
+			p.printKeepAliveSummary()
+			p.printRetryBudget()
+			p.printConcurrencySummary()
+			p.printDeadline()
+			p.printSeverity()
+			p.printResultAssertions()
+		}
 
-						wc.ReadCh = make(chan WebsocketMessage, 10)
-						for i := 0; i < maxRetryCount; i++ {
-							err = wc.Connect()
-							if err != nil {
-								log.Debugf("HTTP probe - ws target not ready yet (retry again later) [err=%v]...", err)
-								time.Sleep(notReadyErrorWait * time.Second)
-								continue
-							}
+		p.publish(ProgressEvent{Type: ProgressEventDone, Summary: summary})
 
-							wc.CheckConnection()
-							//TODO: prep data to write from the HTTPProbeCmd fields
-							err = wc.WriteString("ws.data")
-							p.CallCount++
-
-							if p.printState {
-								statusCode := "error"
-								callErrorStr := "none"
-								if err == nil {
-									statusCode = "ok"
-								} else {
-									callErrorStr = err.Error()
-								}
-
-								p.xc.Out.Info("http.probe.call.ws",
-									ovars{
-										"status":    statusCode,
-										"stats.rc":  wc.ReadCount,
-										"stats.pic": wc.PingCount,
-										"stats.poc": wc.PongCount,
-										"target":    wc.Addr,
-										"attempt":   i + 1,
-										"error":     callErrorStr,
-										"time":      time.Now().UTC().Format(time.RFC3339),
-									})
-							}
+		p.workers.Wait()
+		p.cpuThrottle.stop()
+		p.saveCSVOutput()
+		p.saveReport()
+		p.saveEventLog()
+		p.saveCassette()
+		p.savePcapOutput()
+		p.stopMaxTime()
+		p.cancel()
+		p.closeEvents()
+		close(p.doneChan)
+	}()
+}
+
+// probeCmd runs the probe command for the target address and port
+// (returns true if the command was successful)
+func (p *CustomProbe) probeCmd(cmdIdx int, cmd config.HTTPProbeCmd, targetIdx int, targetHost, port string) bool {
+	if isExecCmd(cmd) {
+		return p.probeExecCmd(cmdIdx, cmd, cmdVarScope(cmdIdx, targetIdx))
+	}
+
+	if len(cmd.AcceptLanguages) > 0 {
+		return p.probeCmdLocales(cmdIdx, cmd, targetIdx, targetHost, port)
+	}
+
+	if cmd.Protocol == config.ProtoGraphQL && cmd.GraphQLQuery == "" {
+		return p.probeGraphQLQueries(cmdIdx, cmd, targetIdx, targetHost, port)
+	}
+
+	if cmd.Protocol == config.ProtoGRPC && isGRPCReflectionResource(cmd.Resource) {
+		return p.probeGRPCMethods(cmdIdx, cmd, targetIdx, targetHost, port)
+	}
+
+	var cmdOK bool
+	var screenshotAddr string
+	varScope := cmdVarScope(cmdIdx, targetIdx)
+	cmd = p.expandVars(varScope, cmd)
+	cmd = p.expandDataTemplates(cmd)
+	if cmd.BaseURL != "" {
+		//the port and address discovery is bypassed for the commands with a base URL
+		var err error
+		if cmd, targetHost, port, err = withBaseURL(cmd); err != nil {
+			log.Errorf("http.probe - cmd.BaseURL (%s) error: %v", cmd.BaseURL, err)
+			return false
+		}
+	}
+
+	socket := cmdUnixSocket(cmd)
+	if socket != "" {
+		//the command calls are sent over the Unix socket (the target address and port are not used)
+		targetHost, port = unixSocketHost, ""
+		if cmd.Protocol == "" {
+			cmd.Protocol = config.ProtoHTTP
+		}
+	}
+
+	cmdTLSInfo, err := loadCmdTLS(cmd)
+	if err != nil {
+		log.Errorf("http.probe - cmd TLS error: %v", err)
+		return false
+	}
+
+	switch cmd.Mode {
+	case config.ProbeModeWebDAV:
+		cmd = withWebDAVDefaults(cmd)
+	case config.ProbeModeRange:
+		cmd = withRangeDefaults(cmd)
+	}
+
+	if cmd.Protocol == config.ProtoGraphQL {
+		var err error
+		if cmd, err = withGraphQLRequest(cmd); err != nil {
+			log.Errorf("http.probe - GraphQL request (%s) error: %v", cmd.Resource, err)
+			return false
+		}
+	}
+
+	if cmd.Protocol == config.ProtoGRPC {
+		cmd = withGRPCRequest(cmd)
+	}
+
+	cmd = withBodyContentType(cmd)
+	cmd = withGzipHeaders(cmd)
+
+	var reqBody io.Reader
+	var rbSeeker io.Seeker
+	var uploadSize int64
+
+	if cmd.Mode == config.ProbeModeUpload {
+		uploadBody := newUploadBodyReader(cmd.UploadSize, cmd.UploadChunkSize)
+		reqBody = uploadBody
+		rbSeeker = uploadBody
+		uploadSize = uploadBody.size
+	} else if cmd.BodyFile != "" {
+		//the command fails without the calls if its body file can't be used
+		//(the calls without the expected body are not useful).
+		//The file is read once, so the requests can get the body again for the retries and the follow up calls
+		//(the transport closes the request body after each call).
+		data, err := os.ReadFile(cmd.BodyFile)
+		if err != nil {
+			log.Errorf("http.probe - cmd.BodyFile (%s) read error: %v", cmd.BodyFile, err)
+			return false
+		}
+
+		fileBody := bytes.NewReader(data)
+		reqBody = fileBody
+		rbSeeker = fileBody
+	} else {
+		strBody := strings.NewReader(cmd.Body)
+		reqBody = strBody
+		rbSeeker = strBody
+	}
+
+	if gzipRequestBody(cmd) && reqBody != nil {
+		gzBody, err := newGzipBody(reqBody)
+		if err != nil {
+			log.Errorf("http.probe - gzip request body error: %v", err)
+			return false
+		}
 
+		reqBody = gzBody
+		rbSeeker = gzBody
+	}
+
+	// TODO: need a smarter and more dynamic way to determine the actual protocol type
+
+	// Set up FastCGI defaults if the default CGI port is used without a FastCGI config.
+	if port == defaultFastCGIPortStr && cmd.FastCGI == nil {
+		log.Debugf("HTTP probe - FastCGI default port (%s) used, setting up HTTP probe FastCGI wrapper defaults", port)
+
+		// Typicall the entrypoint into a PHP app.
+		if cmd.Resource == "/" {
+			cmd.Resource = "/index.php"
+		}
+
+		// SplitPath is typically on the first .php path element.
+		var splitPath []string
+		if phpIdx := strings.Index(cmd.Resource, ".php"); phpIdx != -1 {
+			splitPath = []string{cmd.Resource[:phpIdx+4]}
+		}
+
+		cmd.FastCGI = &config.FastCGIProbeWrapperConfig{
+			// /var/www is a typical root for PHP indices.
+			Root:      "/var/www",
+			SplitPath: splitPath,
+		}
+	}
+
+	protocols := p.cmdProtocols(cmd, targetHost, port, true)
+
+	var chainProto string
+
+	for _, proto := range protocols {
+		if p.stopped() {
+			break
+		}
+
+		//the protocol chain stops at the first protocol with a successful call
+		if len(cmd.ProtocolChain) > 0 && cmdOK {
+			break
+		}
+
+		maxRetryCount := probeRetryCount
+		if p.opts.RetryCount > 0 {
+			maxRetryCount = p.opts.RetryCount
+		}
+
+		notReadyErrorWait := time.Duration(16)
+		webErrorWait := time.Duration(8)
+		otherErrorWait := time.Duration(4)
+		if p.opts.RetryWait > 0 {
+			webErrorWait = time.Duration(p.opts.RetryWait)
+			notReadyErrorWait = time.Duration(p.opts.RetryWait * 2)
+			otherErrorWait = time.Duration(p.opts.RetryWait / 2)
+		}
+
+		if IsValidWSProto(proto) {
+			wc, err := NewWebsocketClient(proto, targetHost, port)
+			if err != nil {
+				log.Debugf("HTTP probe - new websocket error - %v", err)
+				continue
+			}
+
+			wc.Addr += wsResource(cmd.Resource)
+			wc.Dialer = p.wsDialer(tlsServerName(cmd, targetHost, p.opts.TLSServerName))
+			wc.Header = wsRequestHeader(cmd, p.defaultHeaders)
+			wc.ReadCh = make(chan WebsocketMessage, 10)
+			wc.OnRead = func(mtype int, mdata []byte) {
+				p.addMessageEvent(eventProtoWS, wc.Addr, eventActionReceive, wsMessageType(mtype), mdata, nil)
+			}
+
+			backoff := newRetryBackoff(p.opts.RetryBackoffReset, p.opts.RetryMaxWait)
+			for i := 0; i < maxRetryCount && !p.stopped(); i++ {
+				if i > 0 && !p.takeRetry(cmdIdx, cmd) {
+					break
+				}
+
+				p.waitCallRate()
+				err = wc.Connect()
+				p.addMessageEvent(eventProtoWS, wc.Addr, eventActionConnect, "", nil, err)
+				if err != nil {
+					log.Debugf("HTTP probe - ws target not ready yet (retry again later) [err=%v]...", err)
+					if !p.sleep(backoff.next(notReadyErrorWait * time.Second)) {
+						break
+					}
+					continue
+				}
+
+				wc.CheckConnection()
+				//the command body is the message to send
+				//(the completed handshake is enough if there's no message)
+				var bodyCheck string
+				if cmd.Body != "" {
+					err = wc.WriteString(cmd.Body)
+					p.addMessageEvent(eventProtoWS, wc.Addr, eventActionSend, "text", []byte(cmd.Body), err)
+
+					//wait for the reply to the message
+					if err == nil {
+						err = p.wsReply(wc, cmd)
+						if hasExpectedBody(cmd) {
+							bodyCheck = bodyCheckPassed
 							if err != nil {
-								p.ErrCount++
-								log.Debugf("HTTP probe - websocket write error - %v", err)
-								time.Sleep(notReadyErrorWait * time.Second)
-							} else {
-								p.OkCount++
-
-								//try to read something from the socket
-								select {
-								case wsMsg := <-wc.ReadCh:
-									log.Debugf("HTTP probe - websocket read - [type=%v data=%s]", wsMsg.Type, string(wsMsg.Data))
-								case <-time.After(time.Second * 5):
-									log.Debugf("HTTP probe - websocket read time out")
-								}
-
-								break
+								bodyCheck = bodyCheckFailed
 							}
 						}
+					}
+				}
+				atomic.AddUint64(&p.CallCount, 1)
 
-						wc.Disconnect()
-						continue
+				if p.printState {
+					statusCode := "error"
+					callErrorStr := "none"
+					if err == nil {
+						statusCode = "ok"
+					} else {
+						callErrorStr = err.Error()
 					}
 
-					var client *http.Client
-					switch {
-					case cmd.FastCGI != nil:
-						log.Debug("HTTP probe - FastCGI embedded proxy configured")
-						client = getFastCGIClient(cmd.FastCGI)
-					default:
-						var err error
-						if client, err = getHTTPClient(proto); err != nil {
-							p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
-							continue
-						}
+					info := ovars{
+						"status":    statusCode,
+						"stats.rc":  wc.ReadCount,
+						"stats.pic": wc.PingCount,
+						"stats.poc": wc.PongCount,
+						"target":    wc.Addr,
+						"attempt":   i + 1,
+						"error":     callErrorStr,
+						"time":      time.Now().UTC().Format(time.RFC3339),
+					}
+
+					if bodyCheck != "" {
+						info["body.check"] = bodyCheck
 					}
 
-					baseAddr := getHTTPAddr(proto, p.targetHost, port)
-					// TODO: cmd.Resource may need to be a part of cmd.FastCGI instead.
-					addr := fmt.Sprintf("%s%s", baseAddr, cmd.Resource)
+					p.xc.Out.Info("http.probe.call.ws", info)
+				}
 
-					req, err := newHTTPRequestFromCmd(cmd, addr, reqBody)
-					if err != nil {
-						p.xc.Out.Error("HTTP probe - construct request error - %v", err.Error())
-						continue
+				if err != nil {
+					atomic.AddUint64(&p.ErrCount, 1)
+					if bodyCheck == bodyCheckFailed {
+						//the unexpected replies are not retried (like the unexpected response bodies)
+						log.Debugf("HTTP probe - websocket reply error - %v", err)
+						break
 					}
 
-					for i := 0; i < maxRetryCount; i++ {
-						res, err := client.Do(req.Clone(context.Background()))
-						p.CallCount++
-						rbSeeker.Seek(0, 0)
+					log.Debugf("HTTP probe - websocket write error - %v", err)
+					if !p.sleep(backoff.next(notReadyErrorWait * time.Second)) {
+						break
+					}
+				} else {
+					atomic.AddUint64(&p.OkCount, 1)
+					if cmd.BaseURL != "" {
+						atomic.AddUint64(&p.baseURLOkCount, 1)
+					}
+					cmdOK = true
+					break
+				}
+			}
 
-						if res != nil {
-							if res.Body != nil {
-								io.Copy(io.Discard, res.Body)
-							}
+			if wc.Conn != nil {
+				p.addMessageEvent(eventProtoWS, wc.Addr, eventActionClose, "", nil, wc.Disconnect())
+			}
+			continue
+		}
 
-							res.Body.Close()
-						}
+		if cmd.Mode == config.ProbeModeConnect {
+			if p.connectProbe(cmdIdx, cmd, proto, targetHost, port, maxRetryCount, webErrorWait) {
+				cmdOK = true
+			}
+			continue
+		}
 
-						statusCode := "error"
-						callErrorStr := "none"
-						if err == nil {
-							statusCode = fmt.Sprintf("%v", res.StatusCode)
-						} else {
-							callErrorStr = err.Error()
-						}
+		var client *http.Client
+		switch {
+		case cmd.FastCGI != nil:
+			log.Debug("HTTP probe - FastCGI embedded proxy configured")
+			client = getFastCGIClient(cmd.FastCGI)
+		default:
+			var err error
+			if client, err = getHTTPClient(proto, p.clientOpts); err != nil {
+				p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
+				continue
+			}
 
-						if p.printState {
-							p.xc.Out.Info("http.probe.call",
-								ovars{
-									"status":  statusCode,
-									"method":  cmd.Method,
-									"target":  addr,
-									"attempt": i + 1,
-									"error":   callErrorStr,
-									"time":    time.Now().UTC().Format(time.RFC3339),
-								})
-						}
+			if serverName := tlsServerName(cmd, targetHost, p.opts.TLSServerName); serverName != p.clientOpts.tlsServerName {
+				log.Debugf("HTTP probe - TLS server name => '%s' (target=%s)", serverName, targetHost)
+				setTLSServerName(client, serverName)
+			}
 
-						if err == nil {
-							p.OkCount++
+			setCmdTLS(client, cmdTLSInfo)
+			if socket != "" {
+				setUnixSocket(client, p.clientOpts, socket)
+			}
+		}
 
-							if p.OkCount == 1 {
-								if len(p.opts.APISpecs) != 0 && len(p.opts.APISpecFiles) != 0 && cmd.FastCGI != nil {
-									p.xc.Out.Info("HTTP probe - API spec probing not implemented for fastcgi")
-								} else {
-									p.probeAPISpecs(proto, p.targetHost, port)
-								}
-							}
+		client.CheckRedirect = cmdRedirectPolicy(cmd, p.opts.MaxRedirects)
+		if jar := p.cookieJar(targetHost, port, socket); jar != nil {
+			client.Jar = jar
+		}
 
-							if cmd.Crawl {
-								if cmd.FastCGI != nil {
-									p.xc.Out.Info("HTTP probe - crawling not implemented for fastcgi")
-								} else {
-									p.crawl(proto, p.targetHost, addr)
-								}
-							}
-							break
-						} else {
-							p.ErrCount++
-
-							urlErr := &url.Error{}
-							if errors.As(err, &urlErr) {
-								if errors.Is(urlErr.Err, io.EOF) {
-									log.Debugf("HTTP probe - target not ready yet (retry again later)...")
-									time.Sleep(notReadyErrorWait * time.Second)
-								} else {
-									log.Debugf("HTTP probe - web error... retry again later...")
-									time.Sleep(webErrorWait * time.Second)
-								}
-
-							} else {
-								log.Debugf("HTTP probe - other error... retry again later...")
-								time.Sleep(otherErrorWait * time.Second)
-							}
-						}
+		baseAddr := getHTTPAddr(proto, targetHost, port)
+		// TODO: cmd.Resource may need to be a part of cmd.FastCGI instead.
+		addr := fmt.Sprintf("%s%s", baseAddr, cmd.Resource)
 
-					}
-				}
+		req, err := newHTTPRequestFromCmd(p.ctx, cmd, addr, reqBody, p.defaultHeaders)
+		if err != nil {
+			p.xc.Out.Error("HTTP probe - construct request error - %v", err.Error())
+			continue
+		}
+
+		if cmd.Mode == config.ProbeModeWebDAV {
+			if err := p.prepareWebDAVRequest(client, req); err != nil {
+				p.xc.Out.Error("HTTP probe - WebDAV request error - %v", err.Error())
+				continue
 			}
 		}
 
-		log.Info("HTTP probe done.")
+		//the failed call attempts are printed only if they are the last attempts (or in the verbose mode)
+		var lastFailure *ProgressEvent
+		backoff := newRetryBackoff(p.opts.RetryBackoffReset, p.opts.RetryMaxWait)
+		for i := 0; i < maxRetryCount && !p.stopped(); i++ {
+			if i > 0 && !p.takeRetry(cmdIdx, cmd) {
+				break
+			}
 
-		if p.printState {
-			p.xc.Out.Info("http.probe.summary",
-				ovars{
-					"total":      p.CallCount,
-					"failures":   p.ErrCount,
-					"successful": p.OkCount,
-				})
+			creq := req.Clone(p.ctx)
+			requestID := p.setRequestID(creq, cmd, targetIdx, cmdIdx, i+1)
+
+			var credential string
+			credIdx := p.cmdCredential(cmdIdx)
+			if credIdx >= 0 {
+				credential = p.opts.FallbackCredentials[credIdx].Name
+				setCredential(creq, p.opts.FallbackCredentials[credIdx])
+			}
 
-			outVars := ovars{}
-			//warning := ""
-			switch {
-			case p.CallCount == 0:
-				outVars["warning"] = "no.calls"
-				//warning = "warning=no.calls"
-			case p.OkCount == 0:
-				//warning = "warning=no.successful.calls"
-				outVars["warning"] = "no.successful.calls"
+			call := CallResult{
+				Port:      port,
+				CmdIndex:  cmdIdx,
+				Method:    cmd.Method,
+				Path:      cmd.Resource,
+				Target:    addr,
+				Attempt:   i + 1,
+				RequestID: requestID,
+				Socket:    socket,
+			}
+
+			p.throttleCPU()
+			p.waitCallRate()
+
+			callStart := time.Now()
+			res, err := client.Do(creq)
+			callDuration := time.Since(callStart)
+			atomic.AddUint64(&p.CallCount, 1)
+			rewindBody(rbSeeker)
+
+			if err == nil && res.StatusCode == http.StatusUnauthorized && len(p.opts.FallbackCredentials) > 0 {
+				call.Time = callStart
+				call.Duration = callDuration
+				acall := p.authFallback(client, creq, rbSeeker, cmdIdx, credIdx, call, res)
+				res, err, credential = acall.res, acall.err, acall.credential
+				callStart, callDuration = acall.start, acall.duration
+
+				//the follow up calls use the fallback credential that worked
+				if fidx := p.cmdCredential(cmdIdx); fidx != credIdx && fidx >= 0 {
+					setCredential(creq, p.opts.FallbackCredentials[fidx])
+				}
+			}
+
+			if isHeaderLimitError(err) {
+				p.headerLimitHit(cmd.Method, addr)
+			}
+
+			var etag string
+			var statusNum int
+			var resProto string
+			var resBody responseBody
+			if res != nil {
+				statusNum = res.StatusCode
+				resProto = res.Proto
+				etag = res.Header.Get(headerETag)
+
+				if err == nil && cmd.AcceptGzip {
+					err = decodeGzipResponse(res)
+				}
+
+				var readLimit int64
+				switch {
+				case cmd.Mode == config.ProbeModeUpload:
+					readLimit = maxUploadResponseSize
+				case cmd.Mode == config.ProbeModeRange:
+					readLimit = maxRangeResponseSize
+				case needsResponseBody(cmd) || hasExpectedBody(cmd):
+					readLimit = maxCaptureBodySize
+				case cmd.Protocol == config.ProtoGraphQL:
+					readLimit = maxGraphQLResponseSize
+				}
+
+				//the body size is measured by reading the body
+				//(the chunked responses don't have Content-Length)
+				var readErr error
+				resBody, readErr = readResponseBody(res, readLimit)
+				if err == nil && errors.Is(readErr, ErrBadGzipBody) {
+					err = readErr
+				}
+				if res.Body != nil {
+					io.Copy(io.Discard, res.Body)
+					res.Body.Close()
+				}
+			}
+
+			if err == nil {
+				if expected := expectedStatus(cmd, p.opts.ExpectStatus); len(expected) > 0 && !isExpectedStatus(statusNum, expected) {
+					err = &StatusError{StatusCode: statusNum, Expected: expected}
+				}
+			}
+
+			var bodyCheck string
+			if err == nil && hasExpectedBody(cmd) {
+				bodyCheck = bodyCheckPassed
+				if err = checkExpectedBody(cmd, resBody); err != nil {
+					bodyCheck = bodyCheckFailed
+				}
+			}
+
+			if err == nil && cmd.Mode == config.ProbeModeUpload {
+				err = checkUploadResponse(res, resBody.data, uploadSize, cmd.UploadVerify)
+			}
+
+			if err == nil && cmd.Mode == config.ProbeModeRange {
+				err = checkRangeResponse(cmd, res, resBody.data, resBody.truncated)
+			}
+
+			if err == nil && cmd.Mode == config.ProbeModeSecurityHeaders {
+				err = p.securityHeadersCheck(cmd, addr, res.Header)
+			}
+
+			if err == nil && cmd.Mode == config.ProbeModeCacheHeaders {
+				err = p.cacheHeadersCheck(cmd, addr, res.Header)
+			}
+
+			if err == nil && cmd.ExpectHTTPS {
+				err = checkHTTPSRedirect(res)
+			}
+
+			if err == nil && cmd.Protocol == config.ProtoGraphQL {
+				err = checkGraphQLResponse(resBody)
+			}
+
+			if err == nil && cmd.Protocol == config.ProtoGRPC {
+				err = checkGRPCResponse(res)
+			}
+
+			if err == nil && needsResponseBody(cmd) {
+				err = p.captureAndAssert(varScope, cmd, addr, res, resBody)
+			}
+
+			call.Time = callStart
+			call.StatusCode = statusNum
+			call.Proto = resProto
+			call.Duration = callDuration
+			call.Error = errorString(err)
+			call.Credential = credential
+			call.BodyCheck = bodyCheck
+			lastFailure = p.addCommandAttemptResult(call, err)
+
+			if socket == "" && needsScreenshot(cmd, res, err) {
+				screenshotAddr = addr
+			} else if err == nil {
+				screenshotAddr = ""
+			}
+
+			if err == nil {
+				atomic.AddUint64(&p.OkCount, 1)
+				if cmd.BaseURL != "" {
+					atomic.AddUint64(&p.baseURLOkCount, 1)
+				}
+				cmdOK = true
+				if chainProto == "" {
+					chainProto = proto
+				}
+
+				if cmd.Mode == config.ProbeModeETag {
+					p.etagRoundTrip(client, creq, cmdIdx, port, res.StatusCode, etag)
+				}
+
+				if cmd.Mode == config.ProbeModeCompression {
+					p.compressionRoundTrip(client, req, cmdIdx, port, cmd.MinCompressionRatio)
+				}
+
+				if cmd.Mode == config.ProbeModeKeepAlive {
+					p.keepAliveRoundTrip(client, req, cmdIdx, port, cmd)
+				}
+
+				if count := cmdRepeatCount(cmd, p.opts.RepeatCount); count > 0 {
+					p.repeatRoundTrip(client, req, cmdIdx, port, cmd, count)
+				}
+
+				//the API specs and routes are probed after the first successful call to the target
+				//(the base URL commands don't make calls to the target, the API specs and routes
+				//are not probed over the Unix sockets and the preflight commands don't trigger them)
+				if cmd.BaseURL == "" && socket == "" && cmdIdx != preflightCmdIdx &&
+					atomic.CompareAndSwapUint32(&p.targetProbed, 0, 1) {
+					if len(p.opts.APISpecs) != 0 && len(p.opts.APISpecFiles) != 0 && cmd.FastCGI != nil {
+						p.xc.Out.Info("HTTP probe - API spec probing not implemented for fastcgi")
+					} else {
+						p.probeAPISpecs(proto, targetHost, port)
+						p.probeRoutes(proto, targetHost, port)
+					}
+				}
+
+				if cmd.Crawl {
+					if cmd.FastCGI != nil {
+						p.xc.Out.Info("HTTP probe - crawling not implemented for fastcgi")
+					} else if !crawlable(cmd.Method, res) {
+						log.Debugf("HTTP probe - not crawling (not an HTML response to a GET call) => %s", addr)
+					} else {
+						p.crawl(proto, targetHost, addr)
+					}
+				}
+				break
+			} else {
+				atomic.AddUint64(&p.ErrCount, 1)
+
+				if res != nil {
+					//got a response, so the target is (at least intermittently) up
+					backoff.success()
+				}
+
+				if !isRetryableError(err, p.opts.RetryOn) && !isServerStatusError(err) {
+					log.Debugf("HTTP probe - not retrying the call (error.category=%s)", errorCategory(err))
+					break
+				}
+
+				urlErr := &url.Error{}
+				if errors.As(err, &urlErr) {
+					if isNotReadyError(urlErr.Err) {
+						log.Debugf("HTTP probe - target not ready yet (retry again later)...")
+						p.retrySleep(call, maxRetryCount, backoff.next(notReadyErrorWait*time.Second))
+					} else {
+						log.Debugf("HTTP probe - web error... retry again later...")
+						p.retrySleep(call, maxRetryCount, backoff.next(webErrorWait*time.Second))
+					}
+
+				} else {
+					log.Debugf("HTTP probe - other error... retry again later...")
+					p.retrySleep(call, maxRetryCount, backoff.next(otherErrorWait*time.Second))
+				}
 			}
 
-			p.xc.Out.State("http.probe.done", outVars)
 		}
 
-		p.workers.Wait()
-		close(p.doneChan)
-	}()
+		p.printAttemptResult(lastFailure)
+	}
+
+	if len(cmd.ProtocolChain) > 0 {
+		p.printProtocolChain(cmd, port, chainProto)
+	}
+
+	if screenshotAddr != "" && p.opts.ScreenshotOnFailure != "" {
+		p.captureScreenshot(screenshotAddr)
+	}
+
+	return cmdOK
 }
 
 func (p *CustomProbe) probeAPISpecs(proto, targetHost, port string) {
@@ -641,13 +1332,60 @@ func (p *CustomProbe) DoneChan() <-chan struct{} {
 	return p.doneChan
 }
 
-func newHTTPRequestFromCmd(cmd config.HTTPProbeCmd, addr string, reqBody io.Reader) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(context.Background(), cmd.Method, addr, reqBody)
+// cmdProtocols returns the protocols for the command calls on the target port
+// (the unknown port scheme is detected only if 'detect' is true, both schemes are used otherwise)
+func (p *CustomProbe) cmdProtocols(cmd config.HTTPProbeCmd, targetHost, port string, detect bool) []string {
+	if len(cmd.ProtocolChain) > 0 {
+		return cmd.ProtocolChain
+	}
+
+	//the gRPC calls use the HTTP/2 cleartext transport
+	if cmd.Protocol == config.ProtoGRPC {
+		return []string{config.ProtoHTTP2C}
+	}
+
+	//the GraphQL requests use the HTTP transport for the target port
+	if cmd.Protocol != "" && cmd.Protocol != config.ProtoGraphQL {
+		return []string{cmd.Protocol}
+	}
+
+	switch port {
+	case defaultHTTPPortStr:
+		return []string{config.ProtoHTTP}
+	case defaultHTTPSPortStr:
+		return []string{config.ProtoHTTPS}
+	}
+
+	//both schemes are probed if the port scheme is unknown
+	if detect && cmd.FastCGI == nil {
+		if scheme := p.portScheme(targetHost, port); scheme != "" {
+			return []string{scheme}
+		}
+	}
+
+	return []string{config.ProtoHTTP, config.ProtoHTTPS}
+}
+
+// rewindBody rewinds the request body for the next call attempt (the calls without a body have no seeker)
+func rewindBody(rbSeeker io.Seeker) {
+	if rbSeeker == nil {
+		return
+	}
+
+	if _, err := rbSeeker.Seek(0, io.SeekStart); err != nil {
+		log.Errorf("http.probe - request body rewind error: %v", err)
+	}
+}
+
+// newHTTPRequestFromCmd creates the probe command request
+// (the default headers are added before the command headers, so the command can override them)
+func newHTTPRequestFromCmd(ctx context.Context, cmd config.HTTPProbeCmd, addr string, reqBody io.Reader, defaults []string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, cmd.Method, addr, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, hline := range cmd.Headers {
+	for _, hline := range withDefaultHeaders(defaults, cmd.Headers) {
 		hparts := strings.SplitN(hline, ":", 2)
 		if len(hparts) != 2 {
 			log.Debugf("ignoring malformed header (%v)", hline)
@@ -655,12 +1393,27 @@ func newHTTPRequestFromCmd(cmd config.HTTPProbeCmd, addr string, reqBody io.Read
 		}
 
 		hname := strings.TrimSpace(hparts[0])
-		hvalue := strings.TrimSpace(hparts[1])
+		hvalue, err := resolveSecretRefs(strings.TrimSpace(hparts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", hname, err)
+		}
+
+		if strings.EqualFold(hname, headerHost) {
+			//the client ignores the Host value in the request header map
+			req.Host = hvalue
+			continue
+		}
+
 		req.Header.Add(hname, hvalue)
 	}
 
-	if (cmd.Username != "") || (cmd.Password != "") {
-		req.SetBasicAuth(cmd.Username, cmd.Password)
+	if (cmd.Username != "") || (cmd.Password != "") || (cmd.PasswordFile != "") {
+		username, password, err := cmdBasicAuth(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("basic auth: %w", err)
+		}
+
+		req.SetBasicAuth(username, password)
 	}
 
 	return req, nil
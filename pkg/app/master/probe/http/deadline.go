@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// newProbeContext creates the probe context
+// (canceled at the probe deadline if it's set or when the probe is done)
+func newProbeContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// stopped checks if the probe context is done (e.g., the probe deadline is reached)
+func (p *CustomProbe) stopped() bool {
+	return p.ctx.Err() != nil
+}
+
+// sleep waits for the given time or until the probe context is done
+// (returns false if the probe context is done)
+func (p *CustomProbe) sleep(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// startMaxTime starts the probe max time timer (the probe stops when the max time is reached)
+func (p *CustomProbe) startMaxTime() {
+	if p.opts.MaxTime <= 0 {
+		return
+	}
+
+	p.maxTimer = time.AfterFunc(p.opts.MaxTime, func() {
+		log.Debugf("HTTP probe - max time reached (%s)", p.opts.MaxTime)
+		atomic.StoreUint32(&p.maxTimeReached, 1)
+		p.cancel()
+	})
+}
+
+func (p *CustomProbe) stopMaxTime() {
+	if p.maxTimer != nil {
+		p.maxTimer.Stop()
+	}
+}
+
+// DeadlineReached returns true if the probe was stopped at the probe deadline
+// or when the probe max time was reached
+func (p *CustomProbe) DeadlineReached() bool {
+	return p.ctx.Err() == context.DeadlineExceeded || atomic.LoadUint32(&p.maxTimeReached) == 1
+}
+
+// printDeadlineReached prints the probe state if the probe was stopped at the deadline (or the max time)
+func (p *CustomProbe) printDeadlineReached() {
+	if !p.DeadlineReached() || !p.printState {
+		return
+	}
+
+	info := ovars{
+		"total": atomic.LoadUint64(&p.CallCount),
+	}
+
+	if atomic.LoadUint32(&p.maxTimeReached) == 1 {
+		info["max.time"] = p.opts.MaxTime.String()
+	} else {
+		info["deadline"] = p.opts.Deadline.Format(time.RFC3339)
+	}
+
+	p.xc.Out.State("http.probe.deadline.reached", info)
+}
+
+func (p *CustomProbe) printDeadline() {
+	if p.opts.Deadline.IsZero() {
+		return
+	}
+
+	reached := p.DeadlineReached()
+	if reached {
+		log.Debugf("HTTP probe - stopped at the probe deadline (%s)", p.opts.Deadline.Format(time.RFC3339))
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.deadline",
+			ovars{
+				"deadline": p.opts.Deadline.Format(time.RFC3339),
+				"reached":  reached,
+			})
+	}
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+var ErrDuplicateCmds = errors.New("duplicate probe commands")
+
+// cmdRequestKey identifies the probe command target (method, protocol, port, target ports and resource)
+// or the exec command line for the exec commands
+func cmdRequestKey(cmd config.HTTPProbeCmd) string {
+	if isExecCmd(cmd) {
+		return fmt.Sprintf("%s %s", execCmdMethod, execCmdString(cmd))
+	}
+
+	key := fmt.Sprintf("%s %s:%d %s", strings.ToUpper(cmd.Method), cmd.Protocol, cmd.Port, cmd.Resource)
+	if len(cmd.Ports) > 0 {
+		key = fmt.Sprintf("%s ports=%v", key, cmd.Ports)
+	}
+
+	return key
+}
+
+// cmdFullKey identifies the probe command by all of its fields except its name
+// (the commands with the same full key make the same calls)
+func cmdFullKey(cmd config.HTTPProbeCmd) string {
+	cmd.Name = ""
+	cmd.Method = strings.ToUpper(cmd.Method)
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return cmdRequestKey(cmd)
+	}
+
+	return string(data)
+}
+
+// findDuplicateCmds returns the duplicate commands (same calls)
+// and the overlapping commands (same method and resource, but different parameters).
+// Each entry is a list of the command indexes (the first command and its duplicates).
+func findDuplicateCmds(cmds []config.HTTPProbeCmd) (duplicates [][]int, overlaps [][]int) {
+	collect := func(key func(config.HTTPProbeCmd) string) [][]int {
+		var keys []string
+		indexes := map[string][]int{}
+		for idx, cmd := range cmds {
+			k := key(cmd)
+			if _, ok := indexes[k]; !ok {
+				keys = append(keys, k)
+			}
+
+			indexes[k] = append(indexes[k], idx)
+		}
+
+		var found [][]int
+		for _, k := range keys {
+			if len(indexes[k]) > 1 {
+				found = append(found, indexes[k])
+			}
+		}
+
+		return found
+	}
+
+	duplicates = collect(cmdFullKey)
+	//the overlapping command sets that are not just duplicates
+	for _, set := range collect(cmdRequestKey) {
+		full := map[string]bool{}
+		for _, idx := range set {
+			full[cmdFullKey(cmds[idx])] = true
+		}
+
+		if len(full) > 1 {
+			overlaps = append(overlaps, set)
+		}
+	}
+
+	return duplicates, overlaps
+}
+
+// collapseDuplicateCmds removes the duplicate commands (same calls) keeping the first command,
+// so the same calls are not repeated (the commands with different headers or bodies are not duplicates).
+// The named duplicates are kept because they can be the 'depends_on' references
+// and the exec commands are kept because they can be repeated on purpose (e.g., to run a job twice).
+func collapseDuplicateCmds(cmds []config.HTTPProbeCmd) ([]config.HTTPProbeCmd, int) {
+	var unique []config.HTTPProbeCmd
+	known := map[string]bool{}
+	for _, cmd := range cmds {
+		key := cmdFullKey(cmd)
+		if known[key] && cmd.Name == "" && !isExecCmd(cmd) {
+			log.Debugf("HTTP probe - collapsing duplicate command => %s %s", cmd.Method, cmd.Resource)
+			continue
+		}
+
+		known[key] = true
+		unique = append(unique, cmd)
+	}
+
+	return unique, len(cmds) - len(unique)
+}
+
+// collapseDuplicatePorts removes the duplicate probe ports (e.g., the ports
+// from the repeated 'EXPOSE' instructions or the repeated port flags) keeping the port order
+func (p *CustomProbe) collapseDuplicatePorts() {
+	var unique []string
+	known := map[string]bool{}
+	for _, port := range p.ports {
+		if known[port] {
+			continue
+		}
+
+		known[port] = true
+		unique = append(unique, port)
+	}
+
+	if collapsed := len(p.ports) - len(unique); collapsed > 0 {
+		log.Debugf("HTTP probe - collapsed duplicate ports (%d) => %+v", collapsed, unique)
+		p.collapsedPorts += collapsed
+		p.ports = unique
+	}
+}
+
+func cmdPositions(indexes []int) string {
+	var positions []string
+	for _, idx := range indexes {
+		positions = append(positions, fmt.Sprintf("%d", idx+1))
+	}
+
+	return strings.Join(positions, ",")
+}
+
+// checkDuplicateCmds reports the duplicate and overlapping probe commands
+// (returns an error for the duplicate commands if the probe is configured to fail on them)
+func checkDuplicateCmds(xc *app.ExecutionContext, opts config.HTTPProbeOptions, printState bool) error {
+	duplicates, overlaps := findDuplicateCmds(opts.Cmds)
+	for _, set := range duplicates {
+		cmd := opts.Cmds[set[0]]
+		log.Debugf("HTTP probe - duplicate commands (%s) => %s %s", cmdPositions(set), cmd.Method, cmd.Resource)
+		if printState {
+			xc.Out.Info("http.probe.command.duplicate",
+				ovars{
+					"method":   cmd.Method,
+					"resource": cmd.Resource,
+					"count":    len(set),
+					"commands": cmdPositions(set),
+				})
+		}
+	}
+
+	for _, set := range overlaps {
+		cmd := opts.Cmds[set[0]]
+		log.Debugf("HTTP probe - overlapping commands (%s) => %s %s", cmdPositions(set), cmd.Method, cmd.Resource)
+		if printState {
+			xc.Out.Info("http.probe.command.overlap",
+				ovars{
+					"method":   cmd.Method,
+					"resource": cmd.Resource,
+					"count":    len(set),
+					"commands": cmdPositions(set),
+				})
+		}
+	}
+
+	if opts.FailOnDuplicateCmds && len(duplicates) > 0 {
+		var entries []string
+		for _, set := range duplicates {
+			cmd := opts.Cmds[set[0]]
+			entries = append(entries, fmt.Sprintf("%s %s (commands: %s)", cmd.Method, cmd.Resource, cmdPositions(set)))
+		}
+
+		return fmt.Errorf("%w - %s", ErrDuplicateCmds, strings.Join(entries, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// errorCategory maps the probe call errors to the error categories
+func errorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var dnsErr *net.DNSError
+	var statusErr *StatusError
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var netErr net.Error
+
+	switch {
+	case isHeaderLimitError(err):
+		return config.ProbeErrorHeaderLimit
+	case errors.As(err, &dnsErr):
+		return config.ProbeErrorDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return config.ProbeErrorRefused
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE):
+		return config.ProbeErrorReset
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return config.ProbeErrorTimeout
+	case errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return config.ProbeErrorEOF
+	case errors.As(err, &recordErr),
+		errors.As(err, &certErr),
+		errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr),
+		strings.Contains(err.Error(), "tls: "):
+		return config.ProbeErrorTLS
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		return config.ProbeErrorNotFound
+	case errors.As(err, &statusErr),
+		errors.Is(err, ErrUploadNotVerified),
+		errors.Is(err, ErrBadContentRange),
+		errors.Is(err, ErrMissingSecurityHeaders),
+		errors.Is(err, ErrCacheHeaders),
+		errors.Is(err, ErrAssertFailed),
+		errors.Is(err, ErrUnexpectedBody),
+		errors.Is(err, ErrBadGzipBody),
+		errors.Is(err, ErrNoWSReply),
+		errors.Is(err, ErrNotGRPCResponse),
+		errors.Is(err, ErrGRPCStatus),
+		errors.Is(err, ErrRedirectNotHTTPS),
+		errors.Is(err, ErrTooManyRedirects),
+		errors.Is(err, ErrGraphQLErrors),
+		errors.Is(err, ErrGraphQLNoData),
+		errors.Is(err, ErrUnauthorized),
+		errors.Is(err, ErrCaptureNotFound),
+		errors.Is(err, ErrCassetteNoMatch):
+		return config.ProbeErrorResponse
+	default:
+		return config.ProbeErrorOther
+	}
+}
+
+// isNotReadyError returns true if the call error means the target app is not ready yet
+// (the connection is refused before the app listener is bound or it's closed without a response)
+func isNotReadyError(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isRetryableError checks if the error category is in the list of the retried categories
+func isRetryableError(err error, retryOn []string) bool {
+	category := errorCategory(err)
+	for _, val := range retryOn {
+		if val == config.ProbeErrorAll || val == category {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// etagRoundTrip makes the conditional follow up call for the 'etag' probe mode
+// using the ETag value captured from the first (regular) probe call.
+// The target is expected to respond with '304 Not Modified'.
+// The follow up call clones the request of the successful attempt,
+// so it keeps the attempt request ID and credential headers.
+func (p *CustomProbe) etagRoundTrip(
+	client *http.Client,
+	req *http.Request,
+	cmdIdx int,
+	port string,
+	firstStatus int,
+	etag string) {
+	if etag == "" {
+		atomic.AddUint64(&p.ErrCount, 1)
+		p.addCallResult(CallResult{
+			Time:     time.Now(),
+			Port:     port,
+			CmdIndex: cmdIdx,
+			Method:   req.Method,
+			Path:     req.URL.RequestURI(),
+			Target:   req.URL.String(),
+			Attempt:  1,
+			Error:    "no.etag.in.response",
+		})
+
+		if p.printState {
+			p.xc.Out.Info("http.probe.call.etag",
+				ovars{
+					"status": "failed",
+					"method": req.Method,
+					"target": req.URL.String(),
+					"first":  firstStatus,
+					"etag":   "none",
+					"error":  "no.etag.in.response",
+					"time":   time.Now().UTC().Format(time.RFC3339),
+				})
+		}
+
+		return
+	}
+
+	creq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		creq.Body, _ = req.GetBody()
+	}
+	creq.Header.Set(headerIfNoneMatch, etag)
+
+	p.waitCallRate()
+	callStart := time.Now()
+	res, err := client.Do(creq)
+	callDuration := time.Since(callStart)
+	atomic.AddUint64(&p.CallCount, 1)
+
+	var conditionalStatus string
+	var statusCode int
+	if res != nil {
+		statusCode = res.StatusCode
+		if res.Body != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+	}
+
+	callErrorStr := "none"
+	resultStatus := "ok"
+	switch {
+	case err != nil:
+		conditionalStatus = "error"
+		callErrorStr = err.Error()
+		resultStatus = "failed"
+	case statusCode != http.StatusNotModified:
+		conditionalStatus = fmt.Sprintf("%v", statusCode)
+		callErrorStr = "unexpected.status"
+		resultStatus = "failed"
+	default:
+		conditionalStatus = fmt.Sprintf("%v", statusCode)
+	}
+
+	result := CallResult{
+		Time:       callStart,
+		Port:       port,
+		CmdIndex:   cmdIdx,
+		Method:     creq.Method,
+		Path:       creq.URL.RequestURI(),
+		Target:     creq.URL.String(),
+		StatusCode: statusCode,
+		Attempt:    1,
+		Duration:   callDuration,
+	}
+
+	if callErrorStr != "none" {
+		result.Error = callErrorStr
+	}
+
+	p.addCallResult(result)
+
+	if resultStatus == "ok" {
+		atomic.AddUint64(&p.OkCount, 1)
+	} else {
+		atomic.AddUint64(&p.ErrCount, 1)
+		log.Debugf("HTTP probe - etag round trip failed (status=%s error=%v)", conditionalStatus, err)
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.call.etag",
+			ovars{
+				"status":      resultStatus,
+				"method":      creq.Method,
+				"target":      creq.URL.String(),
+				"first":       firstStatus,
+				"etag":        etag,
+				"conditional": conditionalStatus,
+				"error":       callErrorStr,
+				"time":        time.Now().UTC().Format(time.RFC3339),
+			})
+	}
+}
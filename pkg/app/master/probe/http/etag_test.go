@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the conditional follow up call keeps the request ID and the fallback credential of the successful attempt
+func TestProbeCmdETagFollowUpHeaders(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		conditional []*http.Request
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerAuthorization) != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get(headerIfNoneMatch) != "" {
+			mu.Lock()
+			conditional = append(conditional, r)
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pnum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := config.HTTPProbeOptions{
+		RequestIDMode: config.RequestIDModeDeterministic,
+		FallbackCredentials: []config.HTTPProbeCredential{
+			{Name: "bad", Token: "bad"},
+			{Name: "good", Token: "good"},
+		},
+		Cmds: []config.HTTPProbeCmd{
+			{Method: "GET", Resource: "/items", Mode: config.ProbeModeETag},
+		},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	p, err := NewEndpointProbe(xc, "127.0.0.1", []uint{uint(pnum)}, opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Start()
+	<-p.DoneChan()
+
+	if len(conditional) != 1 {
+		t.Fatalf("got %d conditional calls expected 1", len(conditional))
+	}
+
+	if id := conditional[0].Header.Get(headerRequestID); id == "" {
+		t.Errorf("conditional call has no request ID header")
+	}
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultEventLogMaxMessages = 1000
+	maxEventLogDataSize        = 1024
+)
+
+// event log actions (the AsyncAPI operation actions from the probe point of view)
+const (
+	eventActionSend    = "send"
+	eventActionReceive = "receive"
+	eventActionConnect = "connect"
+	eventActionClose   = "close"
+)
+
+// event log protocols
+const (
+	eventProtoHTTP = "http"
+	eventProtoWS   = "ws"
+)
+
+// ProbeEvent is an event log record for an HTTP call or a streaming message
+type ProbeEvent struct {
+	Time     time.Time `json:"time"`
+	Protocol string    `json:"protocol"`
+	//the channel address (the target URL)
+	Channel string `json:"channel"`
+	Action  string `json:"action"`
+	Method  string `json:"method,omitempty"`
+	Status  int    `json:"status,omitempty"`
+	//streaming message type (e.g., text or binary)
+	MessageType string `json:"message_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	Data        string `json:"data,omitempty"`
+	Truncated   bool   `json:"truncated,omitempty"`
+	DurationMS  int64  `json:"duration_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// eventLog collects the probe events (up to the max message count)
+type eventLog struct {
+	mu      sync.Mutex
+	events  []ProbeEvent
+	dropped int
+}
+
+func (p *CustomProbe) eventLogEnabled() bool {
+	return p.opts.EventLogOutput != ""
+}
+
+func (p *CustomProbe) addEvent(event ProbeEvent) {
+	if !p.eventLogEnabled() {
+		return
+	}
+
+	maxMessages := p.opts.EventLogMaxMessages
+	if maxMessages <= 0 {
+		maxMessages = defaultEventLogMaxMessages
+	}
+
+	p.events.mu.Lock()
+	defer p.events.mu.Unlock()
+
+	if len(p.events.events) >= maxMessages {
+		p.events.dropped++
+		return
+	}
+
+	p.events.events = append(p.events.events, event)
+}
+
+// addMessageEvent records a streaming message (the message data is truncated)
+func (p *CustomProbe) addMessageEvent(proto, channel, action, mtype string, data []byte, err error) {
+	if !p.eventLogEnabled() {
+		return
+	}
+
+	event := ProbeEvent{
+		Time:        time.Now(),
+		Protocol:    proto,
+		Channel:     channel,
+		Action:      action,
+		MessageType: mtype,
+		Size:        len(data),
+		Error:       errorString(err),
+	}
+
+	if len(data) > maxEventLogDataSize {
+		data = data[:maxEventLogDataSize]
+		event.Truncated = true
+	}
+
+	if utf8.Valid(data) {
+		event.Data = string(data)
+	}
+
+	p.addEvent(event)
+}
+
+// addCallEvent records an HTTP call
+func (p *CustomProbe) addCallEvent(result CallResult) {
+	if !p.eventLogEnabled() {
+		return
+	}
+
+	p.addEvent(ProbeEvent{
+		Time:       result.Time,
+		Protocol:   eventProtoHTTP,
+		Channel:    result.Target,
+		Action:     eventActionSend,
+		Method:     result.Method,
+		Status:     result.StatusCode,
+		DurationMS: result.Duration.Milliseconds(),
+		Error:      result.Error,
+	})
+}
+
+// EventLog returns the collected probe events
+func (p *CustomProbe) EventLog() []ProbeEvent {
+	p.events.mu.Lock()
+	defer p.events.mu.Unlock()
+
+	events := make([]ProbeEvent, len(p.events.events))
+	copy(events, p.events.events)
+	return events
+}
+
+func (p *CustomProbe) saveEventLog() {
+	if !p.eventLogEnabled() {
+		return
+	}
+
+	events := p.EventLog()
+	if err := writeEventLog(p.opts.EventLogOutput, events); err != nil {
+		log.Warnf("HTTP probe - error saving event log (%s) - %v", p.opts.EventLogOutput, err)
+		return
+	}
+
+	if p.printState {
+		p.events.mu.Lock()
+		dropped := p.events.dropped
+		p.events.mu.Unlock()
+
+		p.xc.Out.Info("http.probe.event.log",
+			ovars{
+				"file":    p.opts.EventLogOutput,
+				"events":  len(events),
+				"dropped": dropped,
+			})
+	}
+}
+
+// writeEventLog saves the events as JSON lines
+func writeEventLog(name string, events []ProbeEvent) error {
+	if dir := filepath.Dir(name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe/exec"
+)
+
+// the exec command 'method' and 'protocol' (for the probe plan)
+const (
+	execCmdMethod   = "EXEC"
+	execCmdProtocol = "exec"
+)
+
+var ErrNoExecRunner = errors.New("no target container for the exec command")
+
+// isExecCmd returns true if the probe command runs a command in the target container (instead of an HTTP call)
+func isExecCmd(cmd config.HTTPProbeCmd) bool {
+	return len(cmd.Exec) > 0
+}
+
+// execCmdString returns the exec command line (for the probe outputs)
+func execCmdString(cmd config.HTTPProbeCmd) string {
+	return strings.Join(cmd.Exec, " ")
+}
+
+// probeExecCmd runs the exec command in the target container.
+// The exec commands run once for each probe run (they don't depend on the probe targets,
+// so the other targets get the result of the first run and the first target variables are used) and one at a time.
+// The command is successful if its exit code is zero and its output passes the expected body checks.
+func (p *CustomProbe) probeExecCmd(cmdIdx int, cmd config.HTTPProbeCmd, varScope int) bool {
+	p.execMu.Lock()
+	defer p.execMu.Unlock()
+
+	//the preflight commands share the command index (and they run only once)
+	if cmdIdx != preflightCmdIdx {
+		if ok, found := p.execResults[cmdIdx]; found {
+			return ok
+		}
+	}
+
+	ok := p.runExecCmd(p.expandVars(varScope, cmd))
+	if cmdIdx != preflightCmdIdx {
+		if p.execResults == nil {
+			p.execResults = map[int]bool{}
+		}
+
+		p.execResults[cmdIdx] = ok
+	}
+
+	return ok
+}
+
+func (p *CustomProbe) runExecCmd(cmd config.HTTPProbeCmd) bool {
+	if p.stopped() {
+		return false
+	}
+
+	var result *exec.Result
+	err := ErrNoExecRunner
+	if p.execRunner != nil {
+		result, err = exec.Call(p.ctx, p.execRunner, cmd.Exec)
+	}
+
+	var bodyCheck string
+	if err == nil && hasExpectedBody(cmd) {
+		bodyCheck = bodyCheckPassed
+		output := responseBody{
+			data:      result.Output,
+			size:      int64(len(result.Output)),
+			truncated: result.Truncated,
+		}
+
+		if err = checkExpectedBody(cmd, output); err != nil {
+			bodyCheck = bodyCheckFailed
+		}
+	}
+
+	//the exec commands are counted the same way as the HTTP calls
+	atomic.AddUint64(&p.CallCount, 1)
+	if err == nil {
+		atomic.AddUint64(&p.OkCount, 1)
+	} else {
+		atomic.AddUint64(&p.ErrCount, 1)
+		log.Debugf("HTTP probe - exec command error (%s) - %v", execCmdString(cmd), err)
+	}
+
+	if p.printState {
+		info := ovars{
+			"command": execCmdString(cmd),
+			"error":   "none",
+		}
+
+		if result != nil {
+			info["exit.code"] = result.ExitCode
+			info["duration"] = result.Duration.Round(time.Millisecond).String()
+		}
+
+		if bodyCheck != "" {
+			info["body.check"] = bodyCheck
+		}
+
+		if err != nil {
+			info["error"] = err.Error()
+		}
+
+		p.xc.Out.Info("http.probe.exec", info)
+	}
+
+	return err == nil
+}
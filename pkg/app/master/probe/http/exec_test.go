@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe/exec"
+)
+
+type testExecRunner struct {
+	results map[string]*exec.Result
+	calls   int
+}
+
+func (r *testExecRunner) Run(ctx context.Context, cmd []string) (*exec.Result, error) {
+	r.calls++
+	return r.results[cmd[0]], nil
+}
+
+func newTestExecProbe(runner exec.Runner) *CustomProbe {
+	return &CustomProbe{
+		ctx:        context.Background(),
+		execRunner: runner,
+	}
+}
+
+func TestProbeExecCmd(t *testing.T) {
+	runner := &testExecRunner{
+		results: map[string]*exec.Result{
+			"ok":        {ExitCode: 0, Output: []byte("status: ready")},
+			"fail":      {ExitCode: 1, Output: []byte("status: ready")},
+			"truncated": {ExitCode: 0, Output: []byte("status: sta"), Truncated: true},
+		},
+	}
+
+	tt := []struct {
+		cmd config.HTTPProbeCmd
+		ok  bool
+	}{
+		{cmd: config.HTTPProbeCmd{Exec: []string{"ok"}}, ok: true},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"fail"}}, ok: false},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"ok"}, ExpectBodyContains: "ready"}, ok: true},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"ok"}, ExpectBodyContains: "stopped"}, ok: false},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"ok"}, ExpectBodyMatch: `^status: \w+$`}, ok: true},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"ok"}, ExpectBodyMatch: `^ready`}, ok: false},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"fail"}, ExpectBodyContains: "ready"}, ok: false},
+		{cmd: config.HTTPProbeCmd{Exec: []string{"truncated"}, ExpectBodyContains: "started"}, ok: false},
+	}
+
+	for idx, test := range tt {
+		p := newTestExecProbe(runner)
+		if ok := p.probeExecCmd(idx, test.cmd, 0); ok != test.ok {
+			t.Errorf("probeExecCmd(%+v): got %v expected %v", test.cmd, ok, test.ok)
+		}
+
+		okCount := uint64(0)
+		if test.ok {
+			okCount = 1
+		}
+
+		if p.CallCount != 1 || p.OkCount != okCount || p.ErrCount != 1-okCount {
+			t.Errorf("probeExecCmd(%+v): got calls/ok/errors %d/%d/%d expected 1/%d/%d",
+				test.cmd, p.CallCount, p.OkCount, p.ErrCount, okCount, 1-okCount)
+		}
+	}
+}
+
+func TestProbeExecCmdResultCache(t *testing.T) {
+	runner := &testExecRunner{
+		results: map[string]*exec.Result{
+			"ok":   {ExitCode: 0},
+			"fail": {ExitCode: 1},
+		},
+	}
+
+	p := newTestExecProbe(runner)
+	okCmd := config.HTTPProbeCmd{Exec: []string{"ok"}}
+	failCmd := config.HTTPProbeCmd{Exec: []string{"fail"}}
+
+	//the other probe targets get the cached results
+	for i := 0; i < 3; i++ {
+		if !p.probeExecCmd(0, okCmd, 0) {
+			t.Errorf("probeExecCmd(0): got failure expected success (run %d)", i)
+		}
+
+		if p.probeExecCmd(1, failCmd, 0) {
+			t.Errorf("probeExecCmd(1): got success expected failure (run %d)", i)
+		}
+	}
+
+	if runner.calls != 2 {
+		t.Errorf("got %d exec calls expected 2 (one for each command index)", runner.calls)
+	}
+
+	//the preflight commands are not cached
+	p.probeExecCmd(preflightCmdIdx, okCmd, 0)
+	p.probeExecCmd(preflightCmdIdx, okCmd, 0)
+	if runner.calls != 4 {
+		t.Errorf("got %d exec calls expected 4 (the preflight commands run each time)", runner.calls)
+	}
+
+	if p.CallCount != 4 || p.OkCount != 3 || p.ErrCount != 1 {
+		t.Errorf("got calls/ok/errors %d/%d/%d expected 4/3/1", p.CallCount, p.OkCount, p.ErrCount)
+	}
+}
+
+func TestProbeExecCmdNoRunner(t *testing.T) {
+	p := newTestExecProbe(nil)
+	if p.probeExecCmd(0, config.HTTPProbeCmd{Exec: []string{"ok"}}, 0) {
+		t.Errorf("probeExecCmd: got success expected failure (no exec runner)")
+	}
+
+	if p.CallCount != 1 || p.ErrCount != 1 {
+		t.Errorf("got calls/errors %d/%d expected 1/1", p.CallCount, p.ErrCount)
+	}
+}
@@ -0,0 +1,441 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A small expression evaluator for the probe command assertions.
+//
+// Supported syntax:
+//   - number (10, 1.5) and string ('text' or "text") literals, true and false
+//   - variables (captured values and the built-in 'status' variable)
+//   - arithmetic operators: + - * / % (with the usual precedence) and parentheses
+//   - comparison operators: == != < <= > >=
+//   - logical operators: && || !
+//
+// The values are compared as numbers when both values are numeric.
+
+var (
+	ErrExprSyntax     = errors.New("expression syntax error")
+	ErrExprUnknownVar = errors.New("unknown variable")
+	ErrExprType       = errors.New("expression type error")
+)
+
+type exprKind int
+
+const (
+	exprNumber exprKind = iota
+	exprString
+	exprBool
+)
+
+type exprValue struct {
+	kind exprKind
+	num  float64
+	str  string
+	b    bool
+}
+
+func (v exprValue) String() string {
+	switch v.kind {
+	case exprNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case exprBool:
+		return strconv.FormatBool(v.b)
+	default:
+		return v.str
+	}
+}
+
+// asNumber returns the numeric value (numeric strings are ok too)
+func (v exprValue) asNumber() (float64, bool) {
+	switch v.kind {
+	case exprNumber:
+		return v.num, true
+	case exprString:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+		return num, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func (v exprValue) asBool() (bool, error) {
+	switch v.kind {
+	case exprBool:
+		return v.b, nil
+	case exprNumber:
+		return v.num != 0, nil
+	default:
+		return false, fmt.Errorf("%w (not a boolean: '%s')", ErrExprType, v.str)
+	}
+}
+
+type exprToken struct {
+	kind string //num, str, ident, op, end
+	text string
+}
+
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "num", text: string(runes[start:i])})
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w (unterminated string)", ErrExprSyntax)
+			}
+			tokens = append(tokens, exprToken{kind: "str", text: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "ident", text: string(runes[start:i])})
+		default:
+			if i+1 < len(runes) {
+				switch op := string(runes[i : i+2]); op {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					tokens = append(tokens, exprToken{kind: "op", text: op})
+					i += 2
+					continue
+				}
+			}
+
+			switch r {
+			case '+', '-', '*', '/', '%', '<', '>', '!', '(', ')':
+				tokens = append(tokens, exprToken{kind: "op", text: string(r)})
+				i++
+			default:
+				return nil, fmt.Errorf("%w (unexpected character '%c')", ErrExprSyntax, r)
+			}
+		}
+	}
+
+	return append(tokens, exprToken{kind: "end"}), nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	vars   func(name string) (string, bool)
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) isOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != "op" {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+// evalExpr evaluates the expression using the variable lookup function
+func evalExpr(expr string, vars func(name string) (string, bool)) (exprValue, error) {
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	p := &exprParser{tokens: tokens, vars: vars}
+	val, err := p.parseOr()
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	if tok := p.peek(); tok.kind != "end" {
+		return exprValue{}, fmt.Errorf("%w (unexpected '%s')", ErrExprSyntax, tok.text)
+	}
+
+	return val, nil
+}
+
+func (p *exprParser) parseOr() (exprValue, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return left, err
+	}
+
+	for {
+		if _, ok := p.isOp("||"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return right, err
+		}
+
+		lb, err := left.asBool()
+		if err != nil {
+			return left, err
+		}
+
+		rb, err := right.asBool()
+		if err != nil {
+			return right, err
+		}
+
+		left = exprValue{kind: exprBool, b: lb || rb}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprValue, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return left, err
+	}
+
+	for {
+		if _, ok := p.isOp("&&"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseCompare()
+		if err != nil {
+			return right, err
+		}
+
+		lb, err := left.asBool()
+		if err != nil {
+			return left, err
+		}
+
+		rb, err := right.asBool()
+		if err != nil {
+			return right, err
+		}
+
+		left = exprValue{kind: exprBool, b: lb && rb}
+	}
+}
+
+func (p *exprParser) parseCompare() (exprValue, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return left, err
+	}
+
+	op, ok := p.isOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.parseSum()
+	if err != nil {
+		return right, err
+	}
+
+	var result bool
+	ln, lok := left.asNumber()
+	rn, rok := right.asNumber()
+	switch {
+	case lok && rok:
+		result = compareValues(op, ln < rn, ln == rn)
+	case left.kind == exprBool || right.kind == exprBool:
+		if op != "==" && op != "!=" {
+			return left, fmt.Errorf("%w (can't compare booleans with '%s')", ErrExprType, op)
+		}
+
+		result = compareValues(op, false, left.String() == right.String())
+	default:
+		ls, rs := left.String(), right.String()
+		result = compareValues(op, ls < rs, ls == rs)
+	}
+
+	return exprValue{kind: exprBool, b: result}, nil
+}
+
+func compareValues(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	default: //">="
+		return !less
+	}
+}
+
+func (p *exprParser) parseSum() (exprValue, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return left, err
+	}
+
+	for {
+		op, ok := p.isOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return right, err
+		}
+
+		ln, lok := left.asNumber()
+		rn, rok := right.asNumber()
+		switch {
+		case lok && rok && op == "+":
+			left = exprValue{kind: exprNumber, num: ln + rn}
+		case lok && rok:
+			left = exprValue{kind: exprNumber, num: ln - rn}
+		case op == "+" && left.kind == exprString && right.kind == exprString:
+			left = exprValue{kind: exprString, str: left.str + right.str}
+		default:
+			return left, fmt.Errorf("%w ('%s' %s '%s')", ErrExprType, left, op, right)
+		}
+	}
+}
+
+func (p *exprParser) parseTerm() (exprValue, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+
+	for {
+		op, ok := p.isOp("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return right, err
+		}
+
+		ln, lok := left.asNumber()
+		rn, rok := right.asNumber()
+		if !lok || !rok {
+			return left, fmt.Errorf("%w ('%s' %s '%s')", ErrExprType, left, op, right)
+		}
+
+		switch op {
+		case "*":
+			left = exprValue{kind: exprNumber, num: ln * rn}
+		default:
+			if rn == 0 {
+				return left, fmt.Errorf("%w (division by zero)", ErrExprType)
+			}
+
+			if op == "/" {
+				left = exprValue{kind: exprNumber, num: ln / rn}
+			} else {
+				left = exprValue{kind: exprNumber, num: math.Mod(ln, rn)}
+			}
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprValue, error) {
+	if op, ok := p.isOp("-", "!"); ok {
+		val, err := p.parseUnary()
+		if err != nil {
+			return val, err
+		}
+
+		if op == "!" {
+			b, err := val.asBool()
+			if err != nil {
+				return val, err
+			}
+
+			return exprValue{kind: exprBool, b: !b}, nil
+		}
+
+		num, ok := val.asNumber()
+		if !ok {
+			return val, fmt.Errorf("%w (not a number: '%s')", ErrExprType, val)
+		}
+
+		return exprValue{kind: exprNumber, num: -num}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprValue, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case "num":
+		p.pos++
+		num, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return exprValue{}, fmt.Errorf("%w (bad number '%s')", ErrExprSyntax, tok.text)
+		}
+
+		return exprValue{kind: exprNumber, num: num}, nil
+	case "str":
+		p.pos++
+		return exprValue{kind: exprString, str: tok.text}, nil
+	case "ident":
+		p.pos++
+		switch tok.text {
+		case "true":
+			return exprValue{kind: exprBool, b: true}, nil
+		case "false":
+			return exprValue{kind: exprBool, b: false}, nil
+		}
+
+		val, ok := p.vars(tok.text)
+		if !ok {
+			return exprValue{}, fmt.Errorf("%w '%s'", ErrExprUnknownVar, tok.text)
+		}
+
+		return exprValue{kind: exprString, str: val}, nil
+	case "op":
+		if tok.text == "(" {
+			p.pos++
+			val, err := p.parseOr()
+			if err != nil {
+				return val, err
+			}
+
+			if _, ok := p.isOp(")"); !ok {
+				return val, fmt.Errorf("%w (missing ')')", ErrExprSyntax)
+			}
+
+			return val, nil
+		}
+	}
+
+	return exprValue{}, fmt.Errorf("%w (unexpected '%s')", ErrExprSyntax, tok.text)
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvalExpr(t *testing.T) {
+	vars := map[string]string{
+		"balance":      "90",
+		"prev_balance": "100",
+		"name":         "slim",
+		"item.count":   "3",
+	}
+
+	lookup := func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	}
+
+	tt := []struct {
+		expr     string
+		expected string
+		err      error
+	}{
+		{expr: "balance == prev_balance - 10", expected: "true"},
+		{expr: "balance < prev_balance && balance > 0", expected: "true"},
+		{expr: "(prev_balance - balance) * 2 == 20", expected: "true"},
+		{expr: "prev_balance / 4 + 1", expected: "26"},
+		{expr: "item.count % 2 != 0", expected: "true"},
+		{expr: "5 % 0.5", expected: "0"},
+		{expr: "7.5 % 2", expected: "1.5"},
+		{expr: "-7 % 3", expected: "-1"},
+		{expr: "name == 'slim'", expected: "true"},
+		{expr: "name != \"slim\" || !(balance >= 100)", expected: "true"},
+		{expr: "-balance < -prev_balance", expected: "false"},
+		{expr: "balance == 100", expected: "false"},
+		{expr: "unknown == 1", err: ErrExprUnknownVar},
+		{expr: "balance == (1", err: ErrExprSyntax},
+		{expr: "name * 2", err: ErrExprType},
+		{expr: "balance / 0", err: ErrExprType},
+		{expr: "balance % 0", err: ErrExprType},
+	}
+
+	for _, test := range tt {
+		val, err := evalExpr(test.expr, lookup)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("'%s': got error '%v' expected '%v'", test.expr, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("'%s': unexpected error - %v", test.expr, err)
+			continue
+		}
+
+		if val.String() != test.expected {
+			t.Errorf("'%s': got '%s' expected '%s'", test.expr, val, test.expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the probe failure error messages are the 'probe.error' output values
+var (
+	ErrNoSuccessfulCalls = errors.New("no.successful.calls")
+	ErrRequiredCmdFailed = errors.New("required.command.failed")
+	ErrPreflightFailed   = errors.New("preflight.failed")
+)
+
+// Error returns the probe failure if the probe exits on failures (the ExitOnFailure option):
+// no successful probe calls (including the probe runs without calls)
+// or a required probe command without successful calls for all probed targets.
+// A failed preflight command is always a probe failure if it aborts the probe (the PreflightAbort option).
+// The result is available after the probe is done.
+func (p *CustomProbe) Error() error {
+	if atomic.LoadUint32(&p.preflightFailed) == 1 {
+		return ErrPreflightFailed
+	}
+
+	if !p.opts.ExitOnFailure {
+		return nil
+	}
+
+	if atomic.LoadUint64(&p.OkCount) == 0 {
+		return ErrNoSuccessfulCalls
+	}
+
+	if failed := p.failedRequiredCmds(); len(failed) > 0 {
+		return fmt.Errorf("%w (%s)", ErrRequiredCmdFailed, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// countRequiredCmd saves the required command result
+// (the command is successful if it's successful for one of the probed targets)
+func (p *CustomProbe) countRequiredCmd(cmd config.HTTPProbeCmd, ok bool) {
+	if !cmd.Required {
+		return
+	}
+
+	name := cmdDepName(cmd)
+
+	p.cmdResultsMu.Lock()
+	if p.requiredOK == nil {
+		p.requiredOK = map[string]bool{}
+	}
+
+	p.requiredOK[name] = p.requiredOK[name] || ok
+	p.cmdResultsMu.Unlock()
+}
+
+// failedRequiredCmds returns the required commands without successful calls
+// (including the commands that didn't run, e.g., because of a failed dependency)
+func (p *CustomProbe) failedRequiredCmds() []string {
+	p.cmdResultsMu.Lock()
+	defer p.cmdResultsMu.Unlock()
+
+	var failed []string
+	for _, cmd := range p.opts.Cmds {
+		if cmd.Required && !p.requiredOK[cmdDepName(cmd)] {
+			failed = append(failed, cmdDepName(cmd))
+		}
+	}
+
+	return failed
+}
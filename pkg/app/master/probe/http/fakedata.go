@@ -0,0 +1,199 @@
+package http
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// data generator template references (e.g., '{{email}}' or '{{int 1 100}}')
+var dataTemplateRE = regexp.MustCompile(`\{\{\s*([a-z_]+)((?:\s+-?[0-9]+)*)\s*\}\}`)
+
+var (
+	fakeFirstNames = []string{"james", "mary", "john", "patricia", "robert", "jennifer", "michael", "linda", "david", "elizabeth", "maria", "wei", "yuki", "ahmed", "olga", "lucas"}
+	fakeLastNames  = []string{"smith", "johnson", "williams", "brown", "jones", "garcia", "miller", "davis", "martinez", "lopez", "wilson", "anderson", "taylor", "thomas", "moore", "lee"}
+	fakeWords      = []string{"alpha", "bravo", "delta", "echo", "gamma", "kilo", "lima", "nova", "omega", "orbit", "pixel", "quartz", "sigma", "tango", "vector", "zulu"}
+	fakeDomains    = []string{"example.com", "example.org", "example.net", "test.com"}
+)
+
+const fakeAlphanumeric = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+const (
+	defaultFakeStringLen = 8
+	maxFakeStringLen     = 4096
+
+	fakeDateDays = 3650
+)
+
+var fakeDateBase = time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeDataGenerator generates the fake (but valid) data for the probe command templates
+// (the generated values are reproducible for the same seed and the same call order)
+type fakeDataGenerator struct {
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	seed int64
+}
+
+func newFakeDataGenerator(seed int64) *fakeDataGenerator {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &fakeDataGenerator{
+		rnd:  rand.New(rand.NewSource(seed)),
+		seed: seed,
+	}
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+func (g *fakeDataGenerator) pick(values []string) string {
+	return values[g.rnd.Intn(len(values))]
+}
+
+func (g *fakeDataGenerator) intn(min, max int) int {
+	if max < min {
+		min, max = max, min
+	}
+
+	return min + g.rnd.Intn(max-min+1)
+}
+
+func (g *fakeDataGenerator) alphanumeric(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte(fakeAlphanumeric[g.rnd.Intn(len(fakeAlphanumeric))])
+	}
+
+	return sb.String()
+}
+
+func (g *fakeDataGenerator) uuid() string {
+	var b [16]byte
+	g.rnd.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 //version 4
+	b[8] = (b[8] & 0x3f) | 0x80 //variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// generate returns the generated value for the template function
+// (returns false if the function or its arguments are unknown)
+func (g *fakeDataGenerator) generate(name string, args []int) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case name == "uuid" && len(args) == 0:
+		return g.uuid(), true
+	case name == "email" && len(args) == 0:
+		return fmt.Sprintf("%s.%s%d@%s", g.pick(fakeFirstNames), g.pick(fakeLastNames), g.rnd.Intn(1000), g.pick(fakeDomains)), true
+	case name == "first_name" && len(args) == 0:
+		return capitalize(g.pick(fakeFirstNames)), true
+	case name == "last_name" && len(args) == 0:
+		return capitalize(g.pick(fakeLastNames)), true
+	case name == "name" && len(args) == 0:
+		return capitalize(g.pick(fakeFirstNames)) + " " + capitalize(g.pick(fakeLastNames)), true
+	case name == "username" && len(args) == 0:
+		return fmt.Sprintf("%s_%s%d", g.pick(fakeFirstNames), g.pick(fakeWords), g.rnd.Intn(100)), true
+	case name == "word" && len(args) == 0:
+		return g.pick(fakeWords), true
+	case name == "bool" && len(args) == 0:
+		return strconv.FormatBool(g.rnd.Intn(2) == 1), true
+	case name == "date" && len(args) == 0:
+		//the dates don't depend on the current time (so they are reproducible)
+		return fakeDateBase.AddDate(0, 0, g.rnd.Intn(fakeDateDays)).Format("2006-01-02"), true
+	case name == "ip" && len(args) == 0:
+		return fmt.Sprintf("10.%d.%d.%d", g.rnd.Intn(256), g.rnd.Intn(256), 1+g.rnd.Intn(254)), true
+	case name == "int" && len(args) == 0:
+		return strconv.Itoa(g.rnd.Intn(1000)), true
+	case name == "int" && len(args) == 2:
+		return strconv.Itoa(g.intn(args[0], args[1])), true
+	case name == "string" && len(args) <= 1:
+		n := defaultFakeStringLen
+		if len(args) == 1 {
+			n = args[0]
+		}
+
+		if n < 1 || n > maxFakeStringLen {
+			return "", false
+		}
+
+		return g.alphanumeric(n), true
+	default:
+		return "", false
+	}
+}
+
+// expand replaces the data generator template references with the generated values
+// (the values are escaped if they are used in the URL path)
+func (g *fakeDataGenerator) expand(value string, escape bool) string {
+	if !strings.Contains(value, "{{") {
+		return value
+	}
+
+	return dataTemplateRE.ReplaceAllStringFunc(value, func(ref string) string {
+		match := dataTemplateRE.FindStringSubmatch(ref)
+		var args []int
+		for _, field := range strings.Fields(match[2]) {
+			//the regexp only matches the integer arguments
+			arg, _ := strconv.Atoi(field)
+			args = append(args, arg)
+		}
+
+		val, ok := g.generate(match[1], args)
+		if !ok {
+			log.Debugf("HTTP probe - unknown data template => %s", ref)
+			return ref
+		}
+
+		if escape {
+			return url.PathEscape(val)
+		}
+
+		return val
+	})
+}
+
+// hasDataTemplates returns true if the probe commands use the data generator templates
+func hasDataTemplates(cmds []config.HTTPProbeCmd) bool {
+	for _, cmd := range cmds {
+		values := append([]string{cmd.Resource, cmd.Body}, cmd.Headers...)
+		for _, value := range values {
+			if dataTemplateRE.MatchString(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expandDataTemplates generates the data for the templates in the command resource, headers and body
+func (p *CustomProbe) expandDataTemplates(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	cmd.Resource = p.fakeData.expand(cmd.Resource, true)
+	cmd.Body = p.fakeData.expand(cmd.Body, false)
+
+	var headers []string
+	for _, header := range cmd.Headers {
+		headers = append(headers, p.fakeData.expand(header, false))
+	}
+	cmd.Headers = headers
+
+	return cmd
+}
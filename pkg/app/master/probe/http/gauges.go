@@ -0,0 +1,139 @@
+package http
+
+import (
+	"sync"
+	"time"
+)
+
+// HostConcurrencyStats provides the probe command scheduler gauges for a target host
+type HostConcurrencyStats struct {
+	Host      string
+	Active    int
+	Queued    int
+	Completed int
+	MaxActive int
+	MaxQueued int
+}
+
+// concurrencyGauges tracks the probe commands waiting for the scheduler semaphore (queued),
+// the commands holding it (active) and the finished commands (completed) for each host
+type concurrencyGauges struct {
+	mu    sync.Mutex
+	hosts map[string]*HostConcurrencyStats
+	order []string
+}
+
+func (g *concurrencyGauges) host(name string) *HostConcurrencyStats {
+	if g.hosts == nil {
+		g.hosts = map[string]*HostConcurrencyStats{}
+	}
+
+	stats, ok := g.hosts[name]
+	if !ok {
+		stats = &HostConcurrencyStats{Host: name}
+		g.hosts[name] = stats
+		g.order = append(g.order, name)
+	}
+
+	return stats
+}
+
+func (g *concurrencyGauges) queue(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := g.host(host)
+	stats.Queued++
+	if stats.Queued > stats.MaxQueued {
+		stats.MaxQueued = stats.Queued
+	}
+}
+
+func (g *concurrencyGauges) start(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := g.host(host)
+	stats.Queued--
+	stats.Active++
+	if stats.Active > stats.MaxActive {
+		stats.MaxActive = stats.Active
+	}
+}
+
+func (g *concurrencyGauges) done(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := g.host(host)
+	stats.Active--
+	stats.Completed++
+}
+
+// ConcurrencyStats returns the current scheduler gauges for each target host
+func (p *CustomProbe) ConcurrencyStats() []HostConcurrencyStats {
+	p.gauges.mu.Lock()
+	defer p.gauges.mu.Unlock()
+
+	var stats []HostConcurrencyStats
+	for _, host := range p.gauges.order {
+		stats = append(stats, *p.gauges.hosts[host])
+	}
+
+	return stats
+}
+
+// startConcurrencyHeartbeat periodically prints the scheduler gauges
+// (returns the function to stop the heartbeat)
+func (p *CustomProbe) startConcurrencyHeartbeat() func() {
+	if p.opts.MetricsInterval <= 0 || !p.printState {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(p.opts.MetricsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				for _, stats := range p.ConcurrencyStats() {
+					p.xc.Out.Info("http.probe.concurrency",
+						ovars{
+							"host":      stats.Host,
+							"active":    stats.Active,
+							"queued":    stats.Queued,
+							"completed": stats.Completed,
+						})
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func (p *CustomProbe) printConcurrencySummary() {
+	if p.opts.Concurrency <= 1 && p.opts.MetricsInterval <= 0 {
+		return
+	}
+
+	for _, stats := range p.ConcurrencyStats() {
+		p.xc.Out.Info("http.probe.concurrency.summary",
+			ovars{
+				"host":       stats.Host,
+				"completed":  stats.Completed,
+				"max.active": stats.MaxActive,
+				"max.queued": stats.MaxQueued,
+			})
+	}
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	graphQLContentType = "application/json"
+
+	//the query used when the schema can't be introspected
+	//(every GraphQL server supports it)
+	graphQLTypenameQuery = "{ __typename }"
+
+	graphQLIntrospectionQuery = `query { __schema { queryType { fields { name args { type { kind } } type { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } }`
+
+	maxGraphQLGeneratedQueries = 10
+	maxGraphQLResponseSize     = 1024 * 1024
+)
+
+var (
+	ErrGraphQLErrors = errors.New("GraphQL response has errors")
+	ErrGraphQLNoData = errors.New("GraphQL response has no data")
+)
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// withGraphQLRequest sets the GraphQL request for the probe command
+// (the JSON request body for POST or the URL query parameters for GET)
+func withGraphQLRequest(cmd config.HTTPProbeCmd) (config.HTTPProbeCmd, error) {
+	greq := graphQLRequest{
+		Query:         cmd.GraphQLQuery,
+		Variables:     cmd.GraphQLVariables,
+		OperationName: cmd.GraphQLOperation,
+	}
+
+	if cmd.Method == "" {
+		cmd.Method = http.MethodPost
+	}
+
+	if cmd.Method == http.MethodGet {
+		params := url.Values{}
+		params.Set("query", greq.Query)
+		if len(greq.Variables) > 0 {
+			variables, err := json.Marshal(greq.Variables)
+			if err != nil {
+				return cmd, err
+			}
+
+			params.Set("variables", string(variables))
+		}
+
+		if greq.OperationName != "" {
+			params.Set("operationName", greq.OperationName)
+		}
+
+		sep := "?"
+		if strings.Contains(cmd.Resource, "?") {
+			sep = "&"
+		}
+
+		cmd.Resource = cmd.Resource + sep + params.Encode()
+		return cmd, nil
+	}
+
+	data, err := json.Marshal(greq)
+	if err != nil {
+		return cmd, err
+	}
+
+	cmd.Body = string(data)
+	cmd.BodyFile = ""
+	for _, hline := range cmd.Headers {
+		if hname := strings.SplitN(hline, ":", 2)[0]; strings.EqualFold(strings.TrimSpace(hname), headerContentType) {
+			return cmd, nil
+		}
+	}
+
+	cmd.Headers = append(cmd.Headers, headerContentType+": "+graphQLContentType)
+	return cmd, nil
+}
+
+// checkGraphQLResponse checks that the GraphQL response has data and no errors
+func checkGraphQLResponse(body responseBody) error {
+	if body.truncated {
+		return fmt.Errorf("%w (response is too large)", ErrGraphQLNoData)
+	}
+
+	var gres graphQLResponse
+	if err := json.Unmarshal(body.data, &gres); err != nil {
+		return fmt.Errorf("%w (not a GraphQL response: %v)", ErrGraphQLNoData, err)
+	}
+
+	if len(gres.Errors) > 0 {
+		return fmt.Errorf("%w (count=%d): %s", ErrGraphQLErrors, len(gres.Errors), gres.Errors[0].Message)
+	}
+
+	if len(gres.Data) == 0 || string(gres.Data) == "null" {
+		return ErrGraphQLNoData
+	}
+
+	return nil
+}
+
+type graphQLTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	OfType *graphQLTypeRef `json:"ofType"`
+}
+
+// namedType returns the type without the NON_NULL and LIST wrappers
+func (t *graphQLTypeRef) namedType() *graphQLTypeRef {
+	for t != nil && t.OfType != nil && (t.Kind == "NON_NULL" || t.Kind == "LIST") {
+		t = t.OfType
+	}
+
+	return t
+}
+
+type graphQLField struct {
+	Name string `json:"name"`
+	Args []struct {
+		Type graphQLTypeRef `json:"type"`
+	} `json:"args"`
+	Type graphQLTypeRef `json:"type"`
+}
+
+type graphQLSchemaResponse struct {
+	Data struct {
+		Schema struct {
+			QueryType struct {
+				Fields []graphQLField `json:"fields"`
+			} `json:"queryType"`
+		} `json:"__schema"`
+	} `json:"data"`
+}
+
+// graphQLQueriesFromSchema generates the basic queries for the query type fields
+// that don't have required arguments
+func graphQLQueriesFromSchema(data []byte) ([]string, error) {
+	var schema graphQLSchemaResponse
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+
+	var queries []string
+	for _, field := range schema.Data.Schema.QueryType.Fields {
+		if len(queries) == maxGraphQLGeneratedQueries {
+			break
+		}
+
+		required := false
+		for _, arg := range field.Args {
+			if arg.Type.Kind == "NON_NULL" {
+				required = true
+				break
+			}
+		}
+
+		if required || strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+
+		switch field.Type.namedType().Kind {
+		case "SCALAR", "ENUM":
+			queries = append(queries, fmt.Sprintf("{ %s }", field.Name))
+		default:
+			queries = append(queries, fmt.Sprintf("{ %s { __typename } }", field.Name))
+		}
+	}
+
+	return queries, nil
+}
+
+// introspectGraphQL gets the GraphQL schema from the target and generates the basic queries
+func (p *CustomProbe) introspectGraphQL(cmd config.HTTPProbeCmd, targetHost, port string) ([]string, error) {
+	proto := config.ProtoHTTP
+	if port == defaultHTTPSPortStr {
+		proto = config.ProtoHTTPS
+	}
+
+	client, err := getHTTPClient(proto, p.clientOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(graphQLRequest{Query: graphQLIntrospectionQuery})
+	if err != nil {
+		return nil, err
+	}
+
+	icmd := cmd
+	icmd.Method = http.MethodPost
+	icmd.Headers = withHeader(cmd.Headers, headerContentType, graphQLContentType)
+	addr := fmt.Sprintf("%s%s", getHTTPAddr(proto, targetHost, port), cmd.Resource)
+	req, err := newHTTPRequestFromCmd(p.ctx, icmd, addr, bytes.NewReader(data), p.defaultHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	p.waitCallRate()
+	atomic.AddUint64(&p.CallCount, 1)
+	queries, err := introspectionQueries(client, req)
+	if err != nil {
+		atomic.AddUint64(&p.ErrCount, 1)
+		return nil, err
+	}
+
+	atomic.AddUint64(&p.OkCount, 1)
+	return queries, nil
+}
+
+// introspectionQueries sends the introspection query and generates the basic queries from the schema response
+func introspectionQueries(client *http.Client, req *http.Request) ([]string, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readResponseBody(res, maxGraphQLResponseSize)
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w (status=%d)", ErrGraphQLNoData, res.StatusCode)
+	}
+
+	if err := checkGraphQLResponse(body); err != nil {
+		return nil, err
+	}
+
+	return graphQLQueriesFromSchema(body.data)
+}
+
+// probeGraphQLQueries runs the GraphQL command once for each query generated from the target schema
+// (the command is successful if all generated queries are successful)
+func (p *CustomProbe) probeGraphQLQueries(cmdIdx int, cmd config.HTTPProbeCmd, targetIdx int, targetHost, port string) bool {
+	queries, err := p.introspectGraphQL(cmd, targetHost, port)
+	if err != nil {
+		log.Debugf("HTTP probe - GraphQL introspection error (%s) - %v", cmd.Resource, err)
+	}
+
+	if len(queries) == 0 {
+		queries = []string{graphQLTypenameQuery}
+	}
+
+	allOK := true
+	for _, query := range queries {
+		if p.stopped() {
+			return false
+		}
+
+		log.Debugf("HTTP probe - GraphQL query (%s) => %s", cmd.Resource, query)
+		qcmd := cmd
+		qcmd.GraphQLQuery = query
+		qcmd.GraphQLVariables = nil
+		qcmd.GraphQLOperation = ""
+		if !p.probeCmd(cmdIdx, qcmd, targetIdx, targetHost, port) {
+			allOK = false
+		}
+	}
+
+	return allOK
+}
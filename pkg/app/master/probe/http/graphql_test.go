@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const testGraphQLSchema = `{"data":{"__schema":{"queryType":{"fields":[
+{"name":"version","args":[],"type":{"kind":"SCALAR","name":"String"}},
+{"name":"users","args":[{"type":{"kind":"SCALAR"}}],"type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"kind":"OBJECT","name":"User"}}}},
+{"name":"user","args":[{"type":{"kind":"NON_NULL"}}],"type":{"kind":"OBJECT","name":"User"}},
+{"name":"__internal","args":[],"type":{"kind":"SCALAR","name":"String"}}
+]}}}}`
+
+func TestGraphQLQueriesFromSchema(t *testing.T) {
+	queries, err := graphQLQueriesFromSchema([]byte(testGraphQLSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"{ version }", "{ users { __typename } }"}
+	if strings.Join(queries, ",") != strings.Join(expected, ",") {
+		t.Errorf("got queries %q expected %q", queries, expected)
+	}
+}
+
+func TestProbeGraphQLQueries(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var greq graphQLRequest
+		if r.Method != http.MethodPost ||
+			r.URL.Path != "/graphql" ||
+			r.Header.Get(headerContentType) != graphQLContentType ||
+			json.NewDecoder(r.Body).Decode(&greq) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		queries = append(queries, greq.Query)
+		mu.Unlock()
+
+		w.Header().Set(headerContentType, graphQLContentType)
+		switch greq.Query {
+		case graphQLIntrospectionQuery:
+			w.Write([]byte(testGraphQLSchema))
+		case "{ version }":
+			w.Write([]byte(`{"data":{"version":"1.0"}}`))
+		case "{ users { __typename } }":
+			w.Write([]byte(`{"data":{"users":[{"__typename":"User"}]}}`))
+		default:
+			w.Write([]byte(`{"errors":[{"message":"unknown query"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	p, err := NewEndpointProbe(xc, host, nil, config.HTTPProbeOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := config.HTTPProbeCmd{
+		Protocol: config.ProtoGraphQL,
+		Resource: "/graphql",
+	}
+
+	if !p.probeGraphQLQueries(0, cmd, 0, host, port) {
+		t.Errorf("probeGraphQLQueries: got failure expected success")
+	}
+
+	//the introspection query first and then a query for each field without the required arguments
+	expected := []string{graphQLIntrospectionQuery, "{ version }", "{ users { __typename } }"}
+	if strings.Join(queries, ",") != strings.Join(expected, ",") {
+		t.Errorf("got queries %q expected %q", queries, expected)
+	}
+
+	if p.CallCount != 3 || p.OkCount != 3 || p.ErrCount != 0 {
+		t.Errorf("got calls/ok/errors %d/%d/%d expected 3/3/0", p.CallCount, p.OkCount, p.ErrCount)
+	}
+}
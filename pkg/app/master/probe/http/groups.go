@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net"
+	"sort"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	groupStageOK      = "ok"
+	groupStageFailed  = "failed"
+	groupStageSkipped = "skipped"
+)
+
+// GroupStageResult is the result of a command group setup or teardown command
+type GroupStageResult struct {
+	Group  string
+	Stage  string
+	Target string
+	Status string
+}
+
+func groupStageStatus(ok bool) string {
+	if ok {
+		return groupStageOK
+	}
+
+	return groupStageFailed
+}
+
+// groupStageDone saves and prints the group setup and teardown command results
+// (the regular commands are ignored)
+func (p *CustomProbe) groupStageDone(cmd config.HTTPProbeCmd, targetHost, port, status string) {
+	if cmd.Group == "" || cmd.GroupStage == "" {
+		return
+	}
+
+	target := net.JoinHostPort(targetHost, port)
+	p.groupsMu.Lock()
+	p.groupResults = append(p.groupResults, GroupStageResult{
+		Group:  cmd.Group,
+		Stage:  cmd.GroupStage,
+		Target: target,
+		Status: status,
+	})
+	p.groupsMu.Unlock()
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.group."+cmd.GroupStage,
+			ovars{
+				"group":    cmd.Group,
+				"status":   status,
+				"method":   cmd.Method,
+				"resource": cmd.Resource,
+				"target":   target,
+			})
+	}
+}
+
+// GroupResults returns the command group setup and teardown results
+func (p *CustomProbe) GroupResults() []GroupStageResult {
+	p.groupsMu.Lock()
+	defer p.groupsMu.Unlock()
+
+	return append([]GroupStageResult{}, p.groupResults...)
+}
+
+func (p *CustomProbe) printGroupSummary() {
+	type stageCounts map[string]int
+	groups := map[string]map[string]stageCounts{}
+	for _, result := range p.GroupResults() {
+		stages, ok := groups[result.Group]
+		if !ok {
+			stages = map[string]stageCounts{}
+			groups[result.Group] = stages
+		}
+
+		if stages[result.Stage] == nil {
+			stages[result.Stage] = stageCounts{}
+		}
+
+		stages[result.Stage][result.Status]++
+	}
+
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		summary := ovars{"group": name}
+		for _, stage := range []string{config.CmdGroupStageSetup, config.CmdGroupStageTeardown} {
+			for status, count := range groups[name][stage] {
+				summary[stage+"."+status] = count
+			}
+		}
+
+		p.xc.Out.Info("http.probe.group.summary", summary)
+	}
+}
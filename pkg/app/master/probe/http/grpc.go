@@ -0,0 +1,370 @@
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	grpcContentType = "application/grpc"
+
+	headerGRPCStatus  = "Grpc-Status"
+	headerGRPCMessage = "Grpc-Message"
+	headerTE          = "TE"
+
+	grpcStatusOK            = "0"
+	grpcStatusUnimplemented = "12"
+
+	//the method called when the target services can't be listed with the reflection API
+	//(the standard health check service)
+	grpcHealthCheckMethod = "/grpc.health.v1.Health/Check"
+
+	grpcReflectionService = "grpc.reflection."
+
+	maxGRPCGeneratedCalls = 10
+	maxGRPCResponseSize   = 4 * 1024 * 1024
+)
+
+// the reflection API versions (the older 'v1alpha' version is used if the 'v1' version is not implemented)
+var grpcReflectionMethods = []string{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+// the reflection API message fields
+const (
+	grpcReflectFileContainingSymbol protowire.Number = 4
+	grpcReflectListServices         protowire.Number = 7
+
+	grpcReflectFileDescriptorResponse protowire.Number = 4
+	grpcReflectListServicesResponse   protowire.Number = 6
+	grpcReflectErrorResponse          protowire.Number = 7
+)
+
+var (
+	ErrNotGRPCResponse = errors.New("not a gRPC response")
+	ErrGRPCStatus      = errors.New("gRPC error status")
+)
+
+// isGRPCReflectionResource returns true if the gRPC command methods are discovered with the reflection API
+// (the command resource is not a method path like '/package.Service/Method')
+func isGRPCReflectionResource(resource string) bool {
+	return resource == "" || resource == "/"
+}
+
+// withGRPCRequest sets the gRPC request for the probe command
+// (the unary call with an empty message, i.e., the message with the default field values)
+func withGRPCRequest(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	cmd.Method = http.MethodPost
+	cmd.Body = string(grpcFrame(nil))
+	cmd.BodyFile = ""
+	cmd.ContentType = ""
+	cmd.Headers = withHeader(cmd.Headers, headerContentType, grpcContentType)
+	cmd.Headers = withHeader(cmd.Headers, headerTE, "trailers")
+	return cmd
+}
+
+// grpcFrame returns the length-prefixed (uncompressed) gRPC message
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// grpcMessages returns the messages in the gRPC response body
+func grpcMessages(data []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, fmt.Errorf("%w (truncated message)", ErrNotGRPCResponse)
+		}
+
+		if data[0] != 0 {
+			return nil, fmt.Errorf("%w (compressed message)", ErrNotGRPCResponse)
+		}
+
+		size := binary.BigEndian.Uint32(data[1:5])
+		if uint64(len(data)-5) < uint64(size) {
+			return nil, fmt.Errorf("%w (truncated message)", ErrNotGRPCResponse)
+		}
+
+		msgs = append(msgs, data[5:5+size])
+		data = data[5+size:]
+	}
+
+	return msgs, nil
+}
+
+// grpcStatus returns the gRPC status of the response
+// (the status is in the trailers or in the headers for the trailers-only responses)
+func grpcStatus(res *http.Response) (string, string) {
+	if status := res.Trailer.Get(headerGRPCStatus); status != "" {
+		return status, res.Trailer.Get(headerGRPCMessage)
+	}
+
+	return res.Header.Get(headerGRPCStatus), res.Header.Get(headerGRPCMessage)
+}
+
+// checkGRPCResponse checks that the call response is a gRPC response for an implemented method
+// (the other error statuses are the method responses to the empty message, so they are successful calls).
+// The response body needs to be read before the check (the status is in the trailers).
+func checkGRPCResponse(res *http.Response) error {
+	status, message := grpcStatus(res)
+	switch status {
+	case "":
+		return ErrNotGRPCResponse
+	case grpcStatusUnimplemented:
+		return fmt.Errorf("%w (status=%s message=%q)", ErrGRPCStatus, status, message)
+	case grpcStatusOK:
+	default:
+		log.Debugf("HTTP probe - gRPC call status => %s (%s)", status, message)
+	}
+
+	return nil
+}
+
+// protoBytesFields returns the length-delimited field values in the protobuf message
+// (the other field types are skipped)
+func protoBytesFields(msg []byte) (map[protowire.Number][][]byte, error) {
+	fields := map[protowire.Number][][]byte{}
+	for len(msg) > 0 {
+		num, wtype, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if wtype == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(msg)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+
+			fields[num] = append(fields[num], value)
+			msg = msg[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, wtype, msg)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		msg = msg[n:]
+	}
+
+	return fields, nil
+}
+
+// grpcReflectionResponse returns the reflection response submessage fields (for the oneof response field)
+func grpcReflectionResponse(msg []byte, field protowire.Number) (map[protowire.Number][][]byte, error) {
+	fields, err := protoBytesFields(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := fields[grpcReflectErrorResponse]; len(errs) > 0 {
+		efields, _ := protoBytesFields(errs[0])
+		var message string
+		if values := efields[2]; len(values) > 0 {
+			message = string(values[0])
+		}
+
+		return nil, fmt.Errorf("reflection error: %s", message)
+	}
+
+	values := fields[field]
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no reflection response (field=%d)", field)
+	}
+
+	return protoBytesFields(values[0])
+}
+
+// grpcReflectionCall makes a reflection API call with one reflection request
+// and returns the response submessage fields (for the expected response field)
+func (p *CustomProbe) grpcReflectionCall(client *http.Client, cmd config.HTTPProbeCmd, addr string,
+	reqField protowire.Number, reqValue string, resField protowire.Number) (map[protowire.Number][][]byte, error) {
+	var msg []byte
+	msg = protowire.AppendTag(msg, reqField, protowire.BytesType)
+	msg = protowire.AppendString(msg, reqValue)
+
+	var lastErr error
+	for _, method := range grpcReflectionMethods {
+		rcmd := withGRPCRequest(cmd)
+		req, err := newHTTPRequestFromCmd(p.ctx, rcmd, addr+method, bytes.NewReader(grpcFrame(msg)), p.defaultHeaders)
+		if err != nil {
+			return nil, err
+		}
+
+		p.waitCallRate()
+		res, err := client.Do(req)
+		atomic.AddUint64(&p.CallCount, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := readResponseBody(res, maxGRPCResponseSize)
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		status, message := grpcStatus(res)
+		if status == "" {
+			return nil, ErrNotGRPCResponse
+		}
+
+		if status != grpcStatusOK {
+			lastErr = fmt.Errorf("%w (status=%s message=%q)", ErrGRPCStatus, status, message)
+			if status == grpcStatusUnimplemented {
+				continue
+			}
+
+			return nil, lastErr
+		}
+
+		msgs, err := grpcMessages(body.data)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(msgs) == 0 {
+			return nil, fmt.Errorf("%w (no reflection response)", ErrNotGRPCResponse)
+		}
+
+		return grpcReflectionResponse(msgs[0], resField)
+	}
+
+	return nil, lastErr
+}
+
+// grpcReflectMethods returns the unary method paths for the target services (using the reflection API)
+func (p *CustomProbe) grpcReflectMethods(cmd config.HTTPProbeCmd, targetHost, port string) ([]string, error) {
+	client, err := getHTTPClient(config.ProtoHTTP2C, p.clientOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := getHTTPAddr(config.ProtoHTTP2C, targetHost, port)
+	fields, err := p.grpcReflectionCall(client, cmd, addr, grpcReflectListServices, "*", grpcReflectListServicesResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, svalue := range fields[1] {
+		sfields, err := protoBytesFields(svalue)
+		if err != nil {
+			return nil, err
+		}
+
+		if names := sfields[1]; len(names) > 0 && !strings.HasPrefix(string(names[0]), grpcReflectionService) {
+			services = append(services, string(names[0]))
+		}
+	}
+	//predictable method order
+	sort.Strings(services)
+
+	var methods []string
+	known := map[string]bool{}
+	for _, service := range services {
+		fields, err := p.grpcReflectionCall(client, cmd, addr, grpcReflectFileContainingSymbol, service, grpcReflectFileDescriptorResponse)
+		if err != nil {
+			log.Debugf("HTTP probe - gRPC service (%s) reflection error - %v", service, err)
+			continue
+		}
+
+		for _, fdata := range fields[1] {
+			var fd descriptorpb.FileDescriptorProto
+			if err := proto.Unmarshal(fdata, &fd); err != nil {
+				log.Debugf("HTTP probe - gRPC service (%s) file descriptor error - %v", service, err)
+				continue
+			}
+
+			for _, sd := range fd.GetService() {
+				fullName := sd.GetName()
+				if fd.GetPackage() != "" {
+					fullName = fd.GetPackage() + "." + fullName
+				}
+
+				if fullName != service {
+					continue
+				}
+
+				for _, md := range sd.GetMethod() {
+					if md.GetClientStreaming() || md.GetServerStreaming() {
+						log.Debugf("HTTP probe - skipping gRPC streaming method => %s/%s", service, md.GetName())
+						continue
+					}
+
+					method := fmt.Sprintf("/%s/%s", service, md.GetName())
+					if !known[method] {
+						known[method] = true
+						methods = append(methods, method)
+					}
+				}
+			}
+		}
+	}
+
+	return methods, nil
+}
+
+// probeGRPCMethods runs the gRPC command once for each unary method discovered with the reflection API
+// (the command is successful if all method calls are successful)
+func (p *CustomProbe) probeGRPCMethods(cmdIdx int, cmd config.HTTPProbeCmd, targetIdx int, targetHost, port string) bool {
+	methods, err := p.grpcReflectMethods(cmd, targetHost, port)
+	if err != nil {
+		log.Debugf("HTTP probe - gRPC reflection error (%s:%s) - %v", targetHost, port, err)
+	}
+
+	if len(methods) > maxGRPCGeneratedCalls {
+		log.Warnf("HTTP probe - gRPC methods limited to %d (found %d)", maxGRPCGeneratedCalls, len(methods))
+		if p.printState {
+			p.xc.Out.Info("http.probe.grpc.methods.limited",
+				ovars{
+					"target":  fmt.Sprintf("%s:%s", targetHost, port),
+					"found":   len(methods),
+					"limit":   maxGRPCGeneratedCalls,
+					"skipped": strings.Join(methods[maxGRPCGeneratedCalls:], ","),
+				})
+		}
+
+		methods = methods[:maxGRPCGeneratedCalls]
+	}
+
+	if len(methods) == 0 {
+		methods = []string{grpcHealthCheckMethod}
+	}
+
+	allOK := true
+	for _, method := range methods {
+		if p.stopped() {
+			return false
+		}
+
+		log.Debugf("HTTP probe - gRPC method => %s", method)
+		mcmd := cmd
+		mcmd.Resource = method
+		if !p.probeCmd(cmdIdx, mcmd, targetIdx, targetHost, port) {
+			allOK = false
+		}
+	}
+
+	return allOK
+}
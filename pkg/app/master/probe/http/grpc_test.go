@@ -0,0 +1,191 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestGRPCMessages(t *testing.T) {
+	frames := func(msgs ...string) []byte {
+		var data []byte
+		for _, msg := range msgs {
+			data = append(data, grpcFrame([]byte(msg))...)
+		}
+
+		return data
+	}
+
+	tt := []struct {
+		name     string
+		data     []byte
+		expected []string
+		err      error
+	}{
+		{name: "no messages", data: nil},
+		{name: "empty message", data: frames(""), expected: []string{""}},
+		{name: "one message", data: frames("hello"), expected: []string{"hello"}},
+		{name: "many messages", data: frames("a", "", "bcd"), expected: []string{"a", "", "bcd"}},
+		{name: "truncated prefix", data: []byte{0, 0, 0}, err: ErrNotGRPCResponse},
+		{name: "truncated message", data: frames("hello")[:7], err: ErrNotGRPCResponse},
+		{name: "compressed message", data: append([]byte{1}, frames("hello")[1:]...), err: ErrNotGRPCResponse},
+	}
+
+	for _, test := range tt {
+		msgs, err := grpcMessages(test.data)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v expected %v", test.name, err, test.err)
+			continue
+		}
+
+		if len(msgs) != len(test.expected) {
+			t.Errorf("%s: got %d messages expected %d", test.name, len(msgs), len(test.expected))
+			continue
+		}
+
+		for i := range msgs {
+			if string(msgs[i]) != test.expected[i] {
+				t.Errorf("%s: got message %q expected %q", test.name, msgs[i], test.expected[i])
+			}
+		}
+	}
+
+	if frame := grpcFrame([]byte("abc")); !bytes.Equal(frame, []byte{0, 0, 0, 0, 3, 'a', 'b', 'c'}) {
+		t.Errorf("got frame %v", frame)
+	}
+}
+
+func TestProtoBytesFields(t *testing.T) {
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	msg = protowire.AppendString(msg, "first")
+	msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, 150)
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	msg = protowire.AppendString(msg, "second")
+	msg = protowire.AppendTag(msg, 3, protowire.Fixed32Type)
+	msg = protowire.AppendFixed32(msg, 7)
+	msg = protowire.AppendTag(msg, 4, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, nil)
+
+	fields, err := protoBytesFields(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		num      protowire.Number
+		expected []string
+	}{
+		{num: 1, expected: []string{"first", "second"}},
+		{num: 2},
+		{num: 3},
+		{num: 4, expected: []string{""}},
+	}
+
+	for _, test := range tt {
+		var values []string
+		for _, value := range fields[test.num] {
+			values = append(values, string(value))
+		}
+
+		if strings.Join(values, ",") != strings.Join(test.expected, ",") || len(values) != len(test.expected) {
+			t.Errorf("field %d: got %q expected %q", test.num, values, test.expected)
+		}
+	}
+
+	//the varint field value with the continuation bit and no next byte
+	if _, err := protoBytesFields([]byte{0x10, 0x96}); err == nil {
+		t.Errorf("truncated varint field: got no error")
+	}
+
+	if _, err := protoBytesFields([]byte{0x0a, 10, 'a'}); err == nil {
+		t.Errorf("truncated bytes field: got no error")
+	}
+}
+
+func TestGRPCReflectionResponse(t *testing.T) {
+	submsg := func(num protowire.Number, values ...string) []byte {
+		var msg []byte
+		for _, value := range values {
+			msg = protowire.AppendTag(msg, num, protowire.BytesType)
+			msg = protowire.AppendString(msg, value)
+		}
+
+		return msg
+	}
+
+	message := func(num protowire.Number, value []byte) []byte {
+		msg := submsg(1, "host")
+		msg = protowire.AppendTag(msg, num, protowire.BytesType)
+		return protowire.AppendBytes(msg, value)
+	}
+
+	//the ListServiceResponse with the ServiceResponse messages (the service name is field 1)
+	services := submsg(1, string(submsg(1, "app.Users")), string(submsg(1, "grpc.reflection.v1.ServerReflection")))
+	var errResponse []byte
+	errResponse = protowire.AppendTag(errResponse, 1, protowire.VarintType)
+	errResponse = protowire.AppendVarint(errResponse, 5)
+	errResponse = append(errResponse, submsg(2, "symbol not found")...)
+
+	tt := []struct {
+		name     string
+		msg      []byte
+		field    protowire.Number
+		expected []string
+		err      string
+	}{
+		{
+			name:     "list services",
+			msg:      message(grpcReflectListServicesResponse, services),
+			field:    grpcReflectListServicesResponse,
+			expected: []string{"app.Users", "grpc.reflection.v1.ServerReflection"},
+		},
+		{
+			name:  "error response",
+			msg:   message(grpcReflectErrorResponse, errResponse),
+			field: grpcReflectListServicesResponse,
+			err:   "reflection error: symbol not found",
+		},
+		{
+			name:  "other response",
+			msg:   message(grpcReflectFileDescriptorResponse, nil),
+			field: grpcReflectListServicesResponse,
+			err:   "no reflection response",
+		},
+	}
+
+	for _, test := range tt {
+		fields, err := grpcReflectionResponse(test.msg, test.field)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v expected '%s'", test.name, err, test.err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error - %v", test.name, err)
+			continue
+		}
+
+		var names []string
+		for _, service := range fields[1] {
+			sfields, err := protoBytesFields(service)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range sfields[1] {
+				names = append(names, string(name))
+			}
+		}
+
+		if strings.Join(names, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: got services %q expected %q", test.name, names, test.expected)
+		}
+	}
+}
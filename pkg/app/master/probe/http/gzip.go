@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const gzipEncoding = "gzip"
+
+var ErrBadGzipBody = errors.New("bad gzip response body")
+
+// withGzipHeaders adds the gzip headers for the commands with the gzip options
+// (the Accept-Encoding header provided by the user is preserved)
+func withGzipHeaders(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	if cmd.AcceptGzip && !hasHeader(cmd.Headers, headerAcceptEncoding) {
+		cmd.Headers = append(append([]string{}, cmd.Headers...),
+			headerAcceptEncoding+": "+gzipEncoding)
+	}
+
+	if gzipRequestBody(cmd) {
+		cmd.Headers = withHeader(cmd.Headers, headerContentEncoding, gzipEncoding)
+	}
+
+	return cmd
+}
+
+// gzipRequestBody returns true if the command request body is sent compressed
+// (the generated upload mode bodies are not compressed)
+func gzipRequestBody(cmd config.HTTPProbeCmd) bool {
+	return cmd.GzipBody &&
+		cmd.Mode != config.ProbeModeUpload &&
+		(cmd.Body != "" || cmd.BodyFile != "")
+}
+
+// newGzipBody compresses the request body
+// (the compressed body is kept in memory, so it can be sent again in the call retries)
+func newGzipBody(body io.Reader) (*bytes.Reader, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, body); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// decodeGzipResponse replaces the gzip encoded response body with the decoded body
+// (like the transparent decoding in the transport, which is disabled when Accept-Encoding is set explicitly)
+func decodeGzipResponse(res *http.Response) error {
+	if res.Body == nil || res.Uncompressed ||
+		res.Header.Get(headerContentEncoding) != gzipEncoding {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		return fmt.Errorf("%w (%v)", ErrBadGzipBody, err)
+	}
+
+	res.Body = &gzipBody{zr: zr, body: res.Body}
+	res.Header.Del(headerContentEncoding)
+	res.Header.Del(headerContentLength)
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return nil
+}
+
+type gzipBody struct {
+	zr   *gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	n, err := b.zr.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w (%v)", ErrBadGzipBody, err)
+	}
+
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	b.zr.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// the transport errors for the responses with too large headers (http1 and http2)
+var headerLimitErrors = []string{
+	"server response headers exceeded",
+	"response header list larger than advertised limit",
+}
+
+// isHeaderLimitError returns true if the response headers exceeded the header size limit
+func isHeaderLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	for _, text := range headerLimitErrors {
+		if strings.Contains(msg, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// headerLimitHit reports the calls with the response headers exceeding the header size limit
+func (p *CustomProbe) headerLimitHit(method, addr string) {
+	atomic.AddUint64(&p.headerLimitCount, 1)
+	log.Debugf("HTTP probe - response header limit hit (%s %s)", method, addr)
+
+	if p.printState {
+		limit := "default"
+		if p.opts.MaxResponseHeaderBytes > 0 {
+			limit = strconv.FormatInt(p.opts.MaxResponseHeaderBytes, 10)
+		}
+
+		p.xc.Out.Info("http.probe.call.header.limit",
+			ovars{
+				"method": method,
+				"target": addr,
+				"limit":  limit,
+			})
+	}
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const headerUserAgent = "User-Agent"
+
+// defaultHeaders returns the headers for all probe calls
+// (the user agent option overrides the User-Agent header in the default headers)
+func defaultHeaders(opts config.HTTPProbeOptions) []string {
+	headers := append([]string{}, opts.Headers...)
+	if opts.UserAgent != "" {
+		headers = withHeader(headers, headerUserAgent, opts.UserAgent)
+	}
+
+	return headers
+}
+
+// withDefaultHeaders returns the command headers with the default headers
+// the command doesn't set (the command headers override the default headers)
+func withDefaultHeaders(defaults, headers []string) []string {
+	if len(defaults) == 0 {
+		return headers
+	}
+
+	var merged []string
+	for _, hline := range defaults {
+		hparts := strings.SplitN(hline, ":", 2)
+		if !hasHeader(headers, strings.TrimSpace(hparts[0])) {
+			merged = append(merged, hline)
+		}
+	}
+
+	return append(merged, headers...)
+}
+
+// setDefaultHeaders sets the default headers for the probe calls that are not probe command calls
+// (e.g., the API spec and the crawler calls). The Host header is used only for the probe command calls.
+func setDefaultHeaders(header http.Header, defaults []string) {
+	for _, hline := range defaults {
+		hparts := strings.SplitN(hline, ":", 2)
+		if len(hparts) != 2 {
+			continue
+		}
+
+		hname := strings.TrimSpace(hparts[0])
+		if strings.EqualFold(hname, headerHost) {
+			continue
+		}
+
+		hvalue, err := resolveSecretRefs(strings.TrimSpace(hparts[1]))
+		if err != nil {
+			log.Debugf("HTTP probe - default header %s error - %v", hname, err)
+			continue
+		}
+
+		header.Set(hname, hvalue)
+	}
+}
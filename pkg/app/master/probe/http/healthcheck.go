@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/docker/dockerfile/reverse"
+)
+
+const healthcheckInstType = "HEALTHCHECK"
+
+// healthcheckURLRE matches the URLs in the healthcheck commands
+// (e.g., 'curl -f http://localhost:8080/health || exit 1')
+var healthcheckURLRE = regexp.MustCompile(`https?://[^\s'"|;&<>()\[\]\\]+`)
+
+// healthcheckCommand returns the image healthcheck command
+// (the last HEALTHCHECK instruction or the image config healthcheck)
+func healthcheckCommand(dockerfileInfo *reverse.Dockerfile, imageInfo *dockerapi.Image) string {
+	if dockerfileInfo != nil {
+		for i := len(dockerfileInfo.AllInstructions) - 1; i >= 0; i-- {
+			inst := dockerfileInfo.AllInstructions[i]
+			if inst != nil && inst.Type == healthcheckInstType {
+				return inst.CommandAll
+			}
+		}
+	}
+
+	if imageInfo != nil && imageInfo.Config != nil && imageInfo.Config.Healthcheck != nil {
+		return strings.Join(imageInfo.Config.Healthcheck.Test, " ")
+	}
+
+	return ""
+}
+
+// healthcheckCmd creates a GET probe command for the URL in the healthcheck command.
+// The URL host and port are ignored (the healthcheck calls are made from inside the container),
+// so the command is probed on the target ports like the other commands.
+func healthcheckCmd(healthcheck string) (config.HTTPProbeCmd, bool) {
+	for _, raw := range healthcheckURLRE.FindAllString(healthcheck, -1) {
+		hcURL, err := url.Parse(raw)
+		if err != nil || hcURL.Host == "" {
+			log.Debugf("HTTP probe - ignoring healthcheck URL (%s) - %v", raw, err)
+			continue
+		}
+
+		resource := hcURL.EscapedPath()
+		if resource == "" {
+			resource = "/"
+		}
+
+		if hcURL.RawQuery != "" {
+			resource += "?" + hcURL.RawQuery
+		}
+
+		return config.HTTPProbeCmd{
+			Protocol: hcURL.Scheme,
+			Method:   "GET",
+			Resource: resource,
+		}, true
+	}
+
+	return config.HTTPProbeCmd{}, false
+}
+
+// addHealthcheckCmd adds the probe command for the image healthcheck URL
+// (unless one of the configured commands already calls the same resource)
+func (p *CustomProbe) addHealthcheckCmd(dockerfileInfo *reverse.Dockerfile, imageInfo *dockerapi.Image) {
+	healthcheck := healthcheckCommand(dockerfileInfo, imageInfo)
+	if healthcheck == "" {
+		log.Debug("HTTP probe - no image healthcheck")
+		return
+	}
+
+	cmd, ok := healthcheckCmd(healthcheck)
+	if !ok {
+		log.Debugf("HTTP probe - no URL in the image healthcheck => %s", healthcheck)
+		return
+	}
+
+	for _, existing := range p.opts.Cmds {
+		if strings.EqualFold(existing.Method, cmd.Method) && existing.Resource == cmd.Resource {
+			log.Debugf("HTTP probe - healthcheck command is already configured => %s %s", cmd.Method, cmd.Resource)
+			return
+		}
+	}
+
+	log.Debugf("HTTP probe - adding healthcheck command => %s %s", cmd.Method, cmd.Resource)
+	p.opts.Cmds = append(p.opts.Cmds, cmd)
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.healthcheck",
+			ovars{
+				"method":   cmd.Method,
+				"resource": cmd.Resource,
+			})
+	}
+}
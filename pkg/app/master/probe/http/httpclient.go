@@ -1,71 +1,281 @@
 package http
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
+	log "github.com/sirupsen/logrus"
+
 	"golang.org/x/net/http2"
 
 	"github.com/slimtoolkit/slim/pkg/app/master/config"
 	"github.com/slimtoolkit/slim/pkg/app/master/probe/http/internal"
 )
 
-func getHTTP1Client() *http.Client {
+const (
+	defaultDNSDialTimeout = 5 * time.Second
+	defaultCallTimeout    = 30 * time.Second
+	defaultConnectTimeout = 5 * time.Second
+)
+
+// clientOptions holds the probe level settings
+// used to construct the probe HTTP clients
+type clientOptions struct {
+	dialer       *net.Dialer
+	cassetteMode string
+	cassette     *Cassette
+	pcap         *pcapWriter
+	//TLS server name (SNI) for the targets probed by IP
+	tlsServerName string
+	//response header size limit (the transport default if it's zero)
+	maxResponseHeaderBytes int64
+	//redirect limit (the default limit if it's zero)
+	maxRedirects int
+	//client timeout for each call (including the response body)
+	callTimeout time.Duration
+	//proxy for the HTTP/1.x calls (the explicit proxy or the proxy environment variables)
+	proxy func(*http.Request) (*url.URL, error)
+	//the base TLS config for the probe clients (each client gets its own copy)
+	tlsConfig *tls.Config
+}
+
+func newClientOptions(opts config.HTTPProbeOptions) *clientOptions {
+	//the connect timeout is shorter than the call timeout, so the unreachable targets fail fast
+	connectTimeout := defaultConnectTimeout
+	if opts.ConnectTimeout > 0 {
+		connectTimeout = opts.ConnectTimeout
+	}
+
+	callTimeout := defaultCallTimeout
+	if opts.CallTimeout > 0 {
+		callTimeout = opts.CallTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if opts.DNSServer != "" {
+		dialer.Resolver = newDNSResolver(opts.DNSServer)
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		if proxyURL, err := config.ParseProbeProxy(opts.Proxy); err == nil {
+			proxy = http.ProxyURL(proxyURL)
+		} else {
+			log.Debugf("HTTP probe - ignoring the proxy (%s) - %v", opts.Proxy, err)
+		}
+	}
+
+	return &clientOptions{
+		dialer:                 dialer,
+		tlsServerName:          opts.TLSServerName,
+		maxResponseHeaderBytes: opts.MaxResponseHeaderBytes,
+		maxRedirects:           opts.MaxRedirects,
+		callTimeout:            callTimeout,
+		proxy:                  proxy,
+		tlsConfig: &tls.Config{
+			InsecureSkipVerify: !opts.VerifyTLS,
+			ServerName:         opts.TLSServerName,
+		},
+	}
+}
+
+// initTLS loads the CA certificate bundle for the server certificate verification
+func (copts *clientOptions) initTLS(opts config.HTTPProbeOptions) error {
+	if opts.CACert == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(opts.CACert)
+	if err != nil {
+		return fmt.Errorf("CA certificate (%s): %w", opts.CACert, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("CA certificate (%s): no PEM certificates", opts.CACert)
+	}
+
+	copts.tlsConfig.RootCAs = pool
+	copts.tlsConfig.InsecureSkipVerify = false
+	return nil
+}
+
+// clientTLSConfig returns a copy of the base TLS config with the server name
+// (the client TLS configs are updated for the command calls, e.g., with the client certificates)
+func (copts *clientOptions) clientTLSConfig(serverName string) *tls.Config {
+	cfg := copts.tlsConfig.Clone()
+	cfg.ServerName = serverName
+	return cfg
+}
+
+// proxied returns true if the calls to the target address and port go through a proxy
+// (the target is not connected directly then, e.g., to wait for the target port)
+func (copts *clientOptions) proxied(targetHost, port string) bool {
+	if copts == nil || copts.proxy == nil {
+		return false
+	}
+
+	req, err := http.NewRequest(http.MethodGet, getHTTPAddr(config.ProtoHTTP, targetHost, port), nil)
+	if err != nil {
+		return false
+	}
+
+	proxyURL, err := copts.proxy(req)
+	return err == nil && proxyURL != nil
+}
+
+func (copts *clientOptions) initCassette(opts config.HTTPProbeOptions) error {
+	switch opts.CassetteMode {
+	case config.CassetteModeRecord:
+		copts.cassette = NewCassette()
+	case config.CassetteModePlayback:
+		cassette, err := LoadCassette(opts.CassetteFile)
+		if err != nil {
+			return err
+		}
+
+		copts.cassette = cassette
+	case "":
+		return nil
+	default:
+		return fmt.Errorf("unknown cassette mode - %s", opts.CassetteMode)
+	}
+
+	copts.cassetteMode = opts.CassetteMode
+	return nil
+}
+
+func (copts *clientOptions) wrapClient(client *http.Client) *http.Client {
+	if copts.cassette != nil {
+		client.Transport = &CassetteTransport{
+			Mode:      copts.cassetteMode,
+			Cassette:  copts.cassette,
+			Transport: client.Transport,
+		}
+	}
+
+	return client
+}
+
+// newDNSResolver creates a resolver that sends all DNS queries to the selected server
+func newDNSResolver(server string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: defaultDNSDialTimeout}
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func getHTTP1Client(copts *clientOptions) *http.Client {
 	client := &http.Client{
-		Timeout: time.Second * 30,
+		Timeout: copts.callTimeout,
 		Transport: &http.Transport{
-			MaxIdleConns:    10,
-			IdleConnTimeout: 30 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+			Proxy:                  copts.proxy,
+			DialContext:            copts.dialContext,
+			MaxIdleConns:           10,
+			IdleConnTimeout:        30 * time.Second,
+			MaxResponseHeaderBytes: copts.maxResponseHeaderBytes,
+			TLSClientConfig:        copts.clientTLSConfig(copts.tlsServerName),
 		},
 	}
 
 	return client
 }
 
-func getHTTP2Client(h2c bool) *http.Client {
+func getHTTP2Client(copts *clientOptions, h2c bool) *http.Client {
 	transport := &http2.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
+		TLSClientConfig: copts.clientTLSConfig(copts.tlsServerName),
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			if copts.pcap == nil {
+				return tls.DialWithDialer(copts.dialer, network, addr, cfg)
+			}
+
+			//the TLS connection is created over the recorded connection
+			conn, err := copts.dialContext(context.Background(), network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			tconn := tls.Client(conn, cfg)
+			if err := tconn.Handshake(); err != nil {
+				conn.Close()
+				return nil, err
+			}
+
+			return tconn, nil
 		},
 	}
 
+	if copts.maxResponseHeaderBytes > 0 {
+		//the http2 header list limit is advertised to the server (and enforced by the transport)
+		transport.MaxHeaderListSize = uint32(copts.maxResponseHeaderBytes)
+		if copts.maxResponseHeaderBytes > math.MaxUint32 {
+			transport.MaxHeaderListSize = math.MaxUint32
+		}
+	}
+
 	client := &http.Client{
-		Timeout:   time.Second * 30,
+		Timeout:   copts.callTimeout,
 		Transport: transport,
 	}
 
 	if h2c {
 		transport.AllowHTTP = true
 		transport.DialTLS = func(network, addr string, cfg *tls.Config) (net.Conn, error) {
-			return net.Dial(network, addr)
+			return copts.dialContext(context.Background(), network, addr)
 		}
 	}
 
 	return client
 }
 
-func getHTTPClient(proto string) (*http.Client, error) {
+func getHTTPClient(proto string, copts *clientOptions) (*http.Client, error) {
+	if copts == nil {
+		copts = newClientOptions(config.HTTPProbeOptions{})
+	}
+
+	var client *http.Client
 	switch proto {
 	case config.ProtoHTTP2:
-		return getHTTP2Client(false), nil
+		client = getHTTP2Client(copts, false)
 	case config.ProtoHTTP2C:
-		return getHTTP2Client(true), nil
+		client = getHTTP2Client(copts, true)
 	default:
-		return getHTTP1Client(), nil
+		client = getHTTP1Client(copts)
 	}
 
-	return nil, fmt.Errorf("unsupported HTTP-family protocol %s", proto)
+	client.CheckRedirect = limitRedirects(copts.maxRedirects)
+	return copts.wrapClient(client), nil
 }
 
 func getHTTPAddr(proto, targetHost, port string) string {
 	scheme := getHTTPScheme(proto)
-	return fmt.Sprintf("%s://%s:%s", scheme, targetHost, port)
+	if port == "" {
+		//the Unix socket calls don't have a target port
+		if strings.Contains(targetHost, ":") {
+			//IPv6 address
+			return fmt.Sprintf("%s://[%s]", scheme, targetHost)
+		}
+
+		return fmt.Sprintf("%s://%s", scheme, targetHost)
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(targetHost, port))
 }
 
 func getHTTPScheme(proto string) string {
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+func TestGetHTTPAddr(t *testing.T) {
+	tt := []struct {
+		proto    string
+		host     string
+		port     string
+		expected string
+	}{
+		{proto: config.ProtoHTTP, host: "127.0.0.1", port: "8080", expected: "http://127.0.0.1:8080"},
+		{proto: config.ProtoHTTPS, host: "localhost", port: "443", expected: "https://localhost:443"},
+		{proto: config.ProtoHTTP, host: "::1", port: "8080", expected: "http://[::1]:8080"},
+		{proto: config.ProtoHTTP2, host: "fd00::2", port: "8443", expected: "https://[fd00::2]:8443"},
+		{proto: config.ProtoHTTP2C, host: "fe80::1%eth0", port: "80", expected: "http://[fe80::1%eth0]:80"},
+		{proto: config.ProtoHTTP, host: "localhost", expected: "http://localhost"},
+		{proto: config.ProtoHTTP, host: "::1", expected: "http://[::1]"},
+	}
+
+	for _, test := range tt {
+		addr := getHTTPAddr(test.proto, test.host, test.port)
+		if addr != test.expected {
+			t.Errorf("'%s' '%s' '%s': got '%s' expected '%s'", test.proto, test.host, test.port, addr, test.expected)
+		}
+	}
+
+	//the call URL is the address with the command resource
+	if url := getHTTPAddr(config.ProtoHTTP, "::1", "8080") + "/"; url != "http://[::1]:8080/" {
+		t.Errorf("got '%s' expected 'http://[::1]:8080/'", url)
+	}
+}
+
+func TestNewEndpointProbeIPv6(t *testing.T) {
+	xc := app.NewExecutionContext("probe", true, "text")
+	for _, endpoint := range []string{"::1", "[::1]"} {
+		probe, err := NewEndpointProbe(xc, endpoint, []uint{8080}, config.HTTPProbeOptions{}, false)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error - %v", endpoint, err)
+		}
+
+		if addr := getHTTPAddr(config.ProtoHTTP, probe.targetHost, probe.Ports()[0]); addr != "http://[::1]:8080" {
+			t.Errorf("'%s': got '%s' expected 'http://[::1]:8080'", endpoint, addr)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const defaultKeepAliveRequests = 5
+
+// KeepAliveStats provides the connection reuse counts for a target host
+type KeepAliveStats struct {
+	Host   string
+	New    int
+	Reused int
+}
+
+type keepAliveStats struct {
+	mu    sync.Mutex
+	hosts map[string]*KeepAliveStats
+	order []string
+}
+
+func (s *keepAliveStats) add(host string, reused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hosts == nil {
+		s.hosts = map[string]*KeepAliveStats{}
+	}
+
+	stats, ok := s.hosts[host]
+	if !ok {
+		stats = &KeepAliveStats{Host: host}
+		s.hosts[host] = stats
+		s.order = append(s.order, host)
+	}
+
+	if reused {
+		stats.Reused++
+	} else {
+		stats.New++
+	}
+}
+
+// KeepAliveStats returns the connection reuse counts for the 'keepalive' mode calls (for each target host)
+func (p *CustomProbe) KeepAliveStats() []KeepAliveStats {
+	p.keepAlive.mu.Lock()
+	defer p.keepAlive.mu.Unlock()
+
+	var stats []KeepAliveStats
+	for _, host := range p.keepAlive.order {
+		stats = append(stats, *p.keepAlive.hosts[host])
+	}
+
+	return stats
+}
+
+// keepAliveRoundTrip makes the sequential follow up calls for the 'keepalive' probe mode
+// and uses httptrace to check if the calls reuse the connections (instead of dialing new ones).
+// The mode fails if none of the follow up calls reuses a connection.
+func (p *CustomProbe) keepAliveRoundTrip(
+	client *http.Client,
+	req *http.Request,
+	cmdIdx int,
+	port string,
+	cmd config.HTTPProbeCmd) {
+	count := cmd.KeepAliveRequests
+	if count <= 0 {
+		count = defaultKeepAliveRequests
+	}
+
+	var newConns, reusedConns int
+	for i := 0; i < count && !p.stopped(); i++ {
+		var reused bool
+		trace := &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				reused = info.Reused
+			},
+		}
+
+		creq := req.Clone(httptrace.WithClientTrace(req.Context(), trace))
+		if req.GetBody != nil {
+			creq.Body, _ = req.GetBody()
+		}
+
+		p.waitCallRate()
+		callStart := time.Now()
+		res, err := client.Do(creq)
+		callDuration := time.Since(callStart)
+		atomic.AddUint64(&p.CallCount, 1)
+
+		var statusCode int
+		if res != nil {
+			statusCode = res.StatusCode
+			//the connection is reused only if the response body is read and closed
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		p.addCallResult(CallResult{
+			Time:       callStart,
+			Port:       port,
+			CmdIndex:   cmdIdx,
+			Method:     creq.Method,
+			Path:       creq.URL.RequestURI(),
+			Target:     creq.URL.String(),
+			StatusCode: statusCode,
+			Attempt:    1,
+			Duration:   callDuration,
+			Error:      errorString(err),
+		})
+
+		if err != nil {
+			atomic.AddUint64(&p.ErrCount, 1)
+			log.Debugf("HTTP probe - keepalive call error - %v", err)
+			continue
+		}
+
+		atomic.AddUint64(&p.OkCount, 1)
+		p.keepAlive.add(creq.URL.Host, reused)
+		if reused {
+			reusedConns++
+		} else {
+			newConns++
+		}
+	}
+
+	status := "ok"
+	if reusedConns == 0 {
+		status = "failed"
+		log.Debugf("HTTP probe - keepalive connections not reused (%s %s)", req.Method, req.URL.String())
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.call.keepalive",
+			ovars{
+				"status": status,
+				"method": req.Method,
+				"target": req.URL.String(),
+				"calls":  count,
+				"new":    newConns,
+				"reused": reusedConns,
+				"time":   time.Now().UTC().Format(time.RFC3339),
+			})
+	}
+}
+
+func (p *CustomProbe) printKeepAliveSummary() {
+	for _, stats := range p.KeepAliveStats() {
+		p.xc.Out.Info("http.probe.keepalive.summary",
+			ovars{
+				"host":   stats.Host,
+				"new":    stats.New,
+				"reused": stats.Reused,
+			})
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const headerAcceptLanguage = "Accept-Language"
+
+// LocaleResult is the result of a probe command executed for one of its locales
+type LocaleResult struct {
+	Locale   string
+	Method   string
+	Resource string
+	Target   string
+	OK       bool
+}
+
+// withHeader returns the headers with the header value replaced (or added)
+func withHeader(headers []string, name, value string) []string {
+	var updated []string
+	for _, hline := range headers {
+		hparts := strings.SplitN(hline, ":", 2)
+		if strings.EqualFold(strings.TrimSpace(hparts[0]), name) {
+			continue
+		}
+
+		updated = append(updated, hline)
+	}
+
+	return append(updated, name+": "+value)
+}
+
+// probeCmdLocales runs the probe command once for each of its Accept-Language values
+// (the command is successful if it's successful for all locales)
+func (p *CustomProbe) probeCmdLocales(cmdIdx int, cmd config.HTTPProbeCmd, targetIdx int, targetHost, port string) bool {
+	allOK := true
+	for _, locale := range cmd.AcceptLanguages {
+		if p.stopped() {
+			return false
+		}
+
+		lcmd := cmd
+		lcmd.AcceptLanguages = nil
+		lcmd.Headers = withHeader(cmd.Headers, headerAcceptLanguage, locale)
+
+		ok := p.probeCmd(cmdIdx, lcmd, targetIdx, targetHost, port)
+		if !ok {
+			allOK = false
+		}
+
+		result := LocaleResult{
+			Locale:   locale,
+			Method:   cmd.Method,
+			Resource: cmd.Resource,
+			Target:   net.JoinHostPort(targetHost, port),
+			OK:       ok,
+		}
+
+		p.localesMu.Lock()
+		p.localeResults = append(p.localeResults, result)
+		p.localesMu.Unlock()
+
+		if p.printState {
+			status := "ok"
+			if !ok {
+				status = "failed"
+			}
+
+			p.xc.Out.Info("http.probe.call.locale",
+				ovars{
+					"locale":   locale,
+					"status":   status,
+					"method":   result.Method,
+					"resource": result.Resource,
+					"target":   result.Target,
+				})
+		}
+	}
+
+	return allOK
+}
+
+// LocaleResults returns the per-locale probe command results
+func (p *CustomProbe) LocaleResults() []LocaleResult {
+	p.localesMu.Lock()
+	defer p.localesMu.Unlock()
+
+	return append([]LocaleResult{}, p.localeResults...)
+}
+
+func (p *CustomProbe) printLocaleSummary() {
+	okCounts := map[string]int{}
+	failedCounts := map[string]int{}
+	var locales []string
+	for _, result := range p.LocaleResults() {
+		if okCounts[result.Locale] == 0 && failedCounts[result.Locale] == 0 {
+			locales = append(locales, result.Locale)
+		}
+
+		if result.OK {
+			okCounts[result.Locale]++
+		} else {
+			failedCounts[result.Locale]++
+		}
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		p.xc.Out.Info("http.probe.locale.summary",
+			ovars{
+				"locale":     locale,
+				"successful": okCounts[locale],
+				"failures":   failedCounts[locale],
+			})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/net/http2"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// cmdTLS is the command client certificate and CA pool (mutual TLS)
+type cmdTLS struct {
+	certs []tls.Certificate
+	//the server certificate is verified only if the command has a CA certificate
+	rootCAs *x509.CertPool
+}
+
+// loadCmdTLS loads the command client certificate and CA certificate
+// (returns nil if the command doesn't have them)
+func loadCmdTLS(cmd config.HTTPProbeCmd) (*cmdTLS, error) {
+	if cmd.ClientCert == "" && cmd.CACert == "" {
+		return nil, nil
+	}
+
+	info := &cmdTLS{}
+	if cmd.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cmd.ClientCert, cmd.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate (%s): %w", cmd.ClientCert, err)
+		}
+
+		info.certs = []tls.Certificate{cert}
+	}
+
+	if cmd.CACert != "" {
+		data, err := os.ReadFile(cmd.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("CA certificate (%s): %w", cmd.CACert, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("CA certificate (%s): no PEM certificates", cmd.CACert)
+		}
+
+		info.rootCAs = pool
+	}
+
+	return info, nil
+}
+
+// setCmdTLS sets the command client certificate for the client transport
+// and enables the server certificate verification if the command has a CA certificate
+func setCmdTLS(client *http.Client, info *cmdTLS) {
+	if info == nil {
+		return
+	}
+
+	transport := client.Transport
+	if ct, ok := transport.(*CassetteTransport); ok {
+		transport = ct.Transport
+	}
+
+	var cfg *tls.Config
+	switch t := transport.(type) {
+	case *http.Transport:
+		cfg = t.TLSClientConfig
+	case *http2.Transport:
+		cfg = t.TLSClientConfig
+	}
+
+	if cfg == nil {
+		return
+	}
+
+	cfg.Certificates = info.certs
+	if info.rootCAs != nil {
+		cfg.RootCAs = info.rootCAs
+		cfg.InsecureSkipVerify = false
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+)
+
+// NotFoundRoute is a probe command route that doesn't exist in the target app
+// (all calls for the route got a 404 response)
+type NotFoundRoute struct {
+	Method   string
+	Resource string
+	Calls    int
+}
+
+// NotFoundRoutes returns the probe command routes with only 404 responses
+// (the calls without a response are ignored, so the real failures don't hide the route status)
+func (p *CustomProbe) NotFoundRoutes() []NotFoundRoute {
+	type routeKey struct {
+		cmdIdx int
+		method string
+		path   string
+	}
+
+	var keys []routeKey
+	notFound := map[routeKey]int{}
+	found := map[routeKey]bool{}
+	for _, result := range p.sortedCallResults() {
+		if result.CmdIndex < 0 || result.StatusCode == 0 {
+			continue
+		}
+
+		key := routeKey{cmdIdx: result.CmdIndex, method: result.Method, path: result.Path}
+		if _, ok := notFound[key]; !ok && !found[key] {
+			keys = append(keys, key)
+		}
+
+		if result.StatusCode == http.StatusNotFound && !p.notFoundExpected(result) {
+			notFound[key]++
+		} else {
+			found[key] = true
+		}
+	}
+
+	var routes []NotFoundRoute
+	for _, key := range keys {
+		if found[key] {
+			continue
+		}
+
+		routes = append(routes, NotFoundRoute{
+			Method:   key.method,
+			Resource: key.path,
+			Calls:    notFound[key],
+		})
+	}
+
+	return routes
+}
+
+// notFoundExpected returns true if the probe command of the call expects the 404 responses
+func (p *CustomProbe) notFoundExpected(result CallResult) bool {
+	return result.CmdIndex >= 0 && result.CmdIndex < len(p.opts.Cmds) &&
+		isExpectedStatus(http.StatusNotFound, p.opts.Cmds[result.CmdIndex].ExpectStatus)
+}
+
+// notFoundCallCount returns the number of the probe command calls with a 404 response
+func (p *CustomProbe) notFoundCallCount() int {
+	var count int
+	for _, result := range p.CallResults() {
+		if result.CmdIndex >= 0 && result.StatusCode == http.StatusNotFound && !p.notFoundExpected(result) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (p *CustomProbe) printNotFoundRoutes() {
+	routes := p.NotFoundRoutes()
+	for _, route := range routes {
+		p.xc.Out.Info("http.probe.route.not.found",
+			ovars{
+				"method":   route.Method,
+				"resource": route.Resource,
+				"calls":    route.Calls,
+			})
+	}
+
+	if len(routes) > 0 {
+		p.xc.Out.Info("http.probe.routes.not.found",
+			ovars{
+				"count":   len(routes),
+				"message": "the probe commands for these routes got only 404 responses (update the probe commands or the app routes)",
+			})
+	}
+}
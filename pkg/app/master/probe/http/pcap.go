@@ -0,0 +1,351 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Synthetic pcap output for the probe traffic.
+// The packets are reconstructed from the data sent and received on the probe connections
+// (a TCP handshake, the data segments and a FIN when the connection is closed),
+// so no packet capture privileges (CAP_NET_RAW) are needed.
+// The packet timing, the segment sizes and the TCP level retransmissions
+// are not the real ones and the HTTPS traffic is captured encrypted.
+
+const (
+	pcapMagic        = 0xa1b2c3d4
+	pcapVersionMajor = 2
+	pcapVersionMinor = 4
+	pcapSnapLen      = 65535
+	pcapLinkTypeRaw  = 101 //raw IPv4/IPv6 packets (no link layer header)
+
+	pcapMaxSegmentSize = 16 * 1024
+	pcapTCPWindow      = 65535
+
+	tcpFlagFIN = 0x01
+	tcpFlagSYN = 0x02
+	tcpFlagPSH = 0x08
+	tcpFlagACK = 0x10
+
+	ipProtoTCP = 6
+)
+
+// pcapWriter writes the synthetic probe connection packets to a pcap file
+type pcapWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	w       *bufio.Writer
+	packets int
+	closed  bool
+	err     error
+}
+
+func newPcapWriter(name string) (*pcapWriter, error) {
+	if dir := filepath.Dir(name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	pw := &pcapWriter{
+		file: f,
+		w:    bufio.NewWriter(f),
+	}
+
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(hdr[6:], pcapVersionMinor)
+	binary.LittleEndian.PutUint32(hdr[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:], pcapLinkTypeRaw)
+	if _, err := pw.w.Write(hdr); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return pw, nil
+}
+
+// writePacket writes a packet record (the caller holds the lock)
+func (pw *pcapWriter) writePacket(ts time.Time, packet []byte) {
+	//the idle connections can be closed after the output is saved
+	if pw.closed || pw.err != nil {
+		return
+	}
+
+	hdr := make([]byte, 16)
+	binary.LittleEndian.PutUint32(hdr[0:], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(packet)))
+
+	if _, pw.err = pw.w.Write(hdr); pw.err != nil {
+		return
+	}
+
+	if _, pw.err = pw.w.Write(packet); pw.err != nil {
+		return
+	}
+
+	pw.packets++
+}
+
+func (pw *pcapWriter) Close() (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	pw.closed = true
+	if err := pw.w.Flush(); err != nil && pw.err == nil {
+		pw.err = err
+	}
+
+	if err := pw.file.Close(); err != nil && pw.err == nil {
+		pw.err = err
+	}
+
+	return pw.packets, pw.err
+}
+
+// wrapConn records the connection traffic
+// (the connections with non-TCP addresses are not recorded)
+func (pw *pcapWriter) wrapConn(conn net.Conn) net.Conn {
+	local, lok := conn.LocalAddr().(*net.TCPAddr)
+	remote, rok := conn.RemoteAddr().(*net.TCPAddr)
+	if !lok || !rok {
+		return conn
+	}
+
+	pc := &pcapConn{
+		Conn:   conn,
+		pw:     pw,
+		local:  local,
+		remote: remote,
+		//the real initial sequence numbers are not visible (any values work here)
+		localSeq:  uint32(time.Now().UnixNano()),
+		remoteSeq: uint32(time.Now().UnixNano() >> 16),
+	}
+
+	pw.mu.Lock()
+	now := time.Now()
+	pc.segment(now, true, tcpFlagSYN, nil)
+	pc.localSeq++
+	pc.segment(now, false, tcpFlagSYN|tcpFlagACK, nil)
+	pc.remoteSeq++
+	pc.segment(now, true, tcpFlagACK, nil)
+	pw.mu.Unlock()
+
+	return pc
+}
+
+type pcapConn struct {
+	net.Conn
+	pw        *pcapWriter
+	local     *net.TCPAddr
+	remote    *net.TCPAddr
+	localSeq  uint32
+	remoteSeq uint32
+	closed    bool
+}
+
+func (c *pcapConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.record(false, b[:n])
+	}
+
+	return n, err
+}
+
+func (c *pcapConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.record(true, b[:n])
+	}
+
+	return n, err
+}
+
+func (c *pcapConn) Close() error {
+	c.pw.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		c.segment(time.Now(), true, tcpFlagFIN|tcpFlagACK, nil)
+		c.localSeq++
+	}
+	c.pw.mu.Unlock()
+
+	return c.Conn.Close()
+}
+
+func (c *pcapConn) record(outbound bool, data []byte) {
+	c.pw.mu.Lock()
+	defer c.pw.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	now := time.Now()
+	for len(data) > 0 {
+		size := len(data)
+		if size > pcapMaxSegmentSize {
+			size = pcapMaxSegmentSize
+		}
+
+		c.segment(now, outbound, tcpFlagPSH|tcpFlagACK, data[:size])
+		if outbound {
+			c.localSeq += uint32(size)
+		} else {
+			c.remoteSeq += uint32(size)
+		}
+
+		data = data[size:]
+	}
+}
+
+// segment writes a TCP segment (the caller holds the writer lock)
+func (c *pcapConn) segment(ts time.Time, outbound bool, flags byte, payload []byte) {
+	src, dst := c.local, c.remote
+	seq, ack := c.localSeq, c.remoteSeq
+	if !outbound {
+		src, dst = c.remote, c.local
+		seq, ack = c.remoteSeq, c.localSeq
+	}
+
+	if flags&tcpFlagACK == 0 {
+		ack = 0
+	}
+
+	tcp := make([]byte, 20+len(payload))
+	binary.BigEndian.PutUint16(tcp[0:], uint16(src.Port))
+	binary.BigEndian.PutUint16(tcp[2:], uint16(dst.Port))
+	binary.BigEndian.PutUint32(tcp[4:], seq)
+	binary.BigEndian.PutUint32(tcp[8:], ack)
+	tcp[12] = 5 << 4 //header size (in 32-bit words)
+	tcp[13] = flags
+	binary.BigEndian.PutUint16(tcp[14:], pcapTCPWindow)
+	copy(tcp[20:], payload)
+
+	c.pw.writePacket(ts, ipPacket(src.IP, dst.IP, tcp))
+}
+
+// ipPacket builds the IPv4 or IPv6 packet with the TCP segment
+func ipPacket(src, dst net.IP, tcp []byte) []byte {
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		packet := make([]byte, 20+len(tcp))
+		packet[0] = 0x45 //version 4, header size 5
+		binary.BigEndian.PutUint16(packet[2:], uint16(len(packet)))
+		packet[8] = 64 //TTL
+		packet[9] = ipProtoTCP
+		copy(packet[12:], src4)
+		copy(packet[16:], dst4)
+		binary.BigEndian.PutUint16(packet[10:], checksum(packet[:20], 0))
+
+		pseudo := make([]byte, 12)
+		copy(pseudo[0:], src4)
+		copy(pseudo[4:], dst4)
+		pseudo[9] = ipProtoTCP
+		binary.BigEndian.PutUint16(pseudo[10:], uint16(len(tcp)))
+		binary.BigEndian.PutUint16(tcp[16:], checksum(tcp, partialChecksum(pseudo, 0)))
+
+		copy(packet[20:], tcp)
+		return packet
+	}
+
+	packet := make([]byte, 40+len(tcp))
+	packet[0] = 0x60 //version 6
+	binary.BigEndian.PutUint16(packet[4:], uint16(len(tcp)))
+	packet[6] = ipProtoTCP
+	packet[7] = 64 //hop limit
+	copy(packet[8:], src.To16())
+	copy(packet[24:], dst.To16())
+
+	pseudo := make([]byte, 40)
+	copy(pseudo[0:], src.To16())
+	copy(pseudo[16:], dst.To16())
+	binary.BigEndian.PutUint32(pseudo[32:], uint32(len(tcp)))
+	pseudo[39] = ipProtoTCP
+	binary.BigEndian.PutUint16(tcp[16:], checksum(tcp, partialChecksum(pseudo, 0)))
+
+	copy(packet[40:], tcp)
+	return packet
+}
+
+func partialChecksum(data []byte, sum uint32) uint32 {
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(data[i])<<8 | uint32(data[i+1])
+	}
+
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+
+	return sum
+}
+
+func checksum(data []byte, sum uint32) uint16 {
+	sum = partialChecksum(data, sum)
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+
+	return ^uint16(sum)
+}
+
+// dialContext dials the probe connections (recording them when the pcap output is enabled)
+func (copts *clientOptions) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := copts.dialer.DialContext(ctx, network, addr)
+	if err != nil || copts.pcap == nil {
+		return conn, err
+	}
+
+	return copts.pcap.wrapConn(conn), nil
+}
+
+func (p *CustomProbe) initPcapOutput() {
+	if p.opts.PcapOutput == "" {
+		return
+	}
+
+	pw, err := newPcapWriter(p.opts.PcapOutput)
+	if err != nil {
+		//not a fatal error (the probe works without the traffic capture)
+		log.Warnf("HTTP probe - error creating pcap output (%s) - %v", p.opts.PcapOutput, err)
+		return
+	}
+
+	p.clientOpts.pcap = pw
+}
+
+func (p *CustomProbe) savePcapOutput() {
+	if p.clientOpts.pcap == nil {
+		return
+	}
+
+	packets, err := p.clientOpts.pcap.Close()
+	if err != nil {
+		log.Warnf("HTTP probe - error saving pcap output (%s) - %v", p.opts.PcapOutput, err)
+		return
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.pcap.output",
+			ovars{
+				"file":    p.opts.PcapOutput,
+				"packets": packets,
+			})
+	}
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testPcapPacket struct {
+	flags   byte
+	payload []byte
+}
+
+func TestPcapWriter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	//the response is larger than the max segment size (it's split in two segments)
+	response := bytes.Repeat([]byte("r"), pcapMaxSegmentSize+100)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err == nil {
+			conn.Write(response)
+		}
+	}()
+
+	name := filepath.Join(t.TempDir(), "out", "probe.pcap")
+	pw, err := newPcapWriter(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pconn := pw.wrapConn(conn)
+	if _, err := pconn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := io.ReadFull(pconn, make([]byte, len(response))); err != nil {
+		t.Fatal(err)
+	}
+
+	pconn.Close()
+	packetCount, err := pw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) < 24 {
+		t.Fatalf("got %d bytes expected the global header", len(data))
+	}
+
+	if magic := binary.LittleEndian.Uint32(data[0:]); magic != pcapMagic {
+		t.Errorf("got magic %x expected %x", magic, pcapMagic)
+	}
+
+	major, minor := binary.LittleEndian.Uint16(data[4:]), binary.LittleEndian.Uint16(data[6:])
+	if major != pcapVersionMajor || minor != pcapVersionMinor {
+		t.Errorf("got version %d.%d expected %d.%d", major, minor, pcapVersionMajor, pcapVersionMinor)
+	}
+
+	if snapLen := binary.LittleEndian.Uint32(data[16:]); snapLen != pcapSnapLen {
+		t.Errorf("got snap length %d expected %d", snapLen, pcapSnapLen)
+	}
+
+	if linkType := binary.LittleEndian.Uint32(data[20:]); linkType != pcapLinkTypeRaw {
+		t.Errorf("got link type %d expected %d", linkType, pcapLinkTypeRaw)
+	}
+
+	var packets []testPcapPacket
+	for rec := data[24:]; len(rec) > 0; {
+		if len(rec) < 16 {
+			t.Fatalf("truncated packet record header (%d bytes)", len(rec))
+		}
+
+		inclLen := binary.LittleEndian.Uint32(rec[8:])
+		origLen := binary.LittleEndian.Uint32(rec[12:])
+		if inclLen != origLen {
+			t.Errorf("got packet length %d (original %d) expected the same lengths", inclLen, origLen)
+		}
+
+		if uint32(len(rec)-16) < inclLen {
+			t.Fatalf("truncated packet record (%d bytes, expected %d)", len(rec)-16, inclLen)
+		}
+
+		packet := rec[16 : 16+inclLen]
+		rec = rec[16+inclLen:]
+
+		if packet[0] != 0x45 {
+			t.Fatalf("got IP version/header %x expected 0x45", packet[0])
+		}
+
+		if totalLen := binary.BigEndian.Uint16(packet[2:]); int(totalLen) != len(packet) {
+			t.Errorf("got IP total length %d expected the packet length %d", totalLen, len(packet))
+		}
+
+		if packet[9] != ipProtoTCP {
+			t.Errorf("got IP protocol %d expected %d", packet[9], ipProtoTCP)
+		}
+
+		if sum := checksum(packet[:20], 0); sum != 0 {
+			t.Errorf("bad IP header checksum (%x)", sum)
+		}
+
+		tcp := packet[20:]
+		packets = append(packets, testPcapPacket{flags: tcp[13], payload: tcp[20:]})
+	}
+
+	if len(packets) != packetCount {
+		t.Errorf("got %d packet records expected %d", len(packets), packetCount)
+	}
+
+	expected := []struct {
+		flags byte
+		size  int
+	}{
+		{flags: tcpFlagSYN},
+		{flags: tcpFlagSYN | tcpFlagACK},
+		{flags: tcpFlagACK},
+		{flags: tcpFlagPSH | tcpFlagACK, size: 4},
+	}
+
+	//the response segments (the reads can return the partial responses)
+	if len(packets) < len(expected)+2 {
+		t.Fatalf("got %d packets expected at least %d", len(packets), len(expected)+2)
+	}
+
+	for i, e := range expected {
+		if packets[i].flags != e.flags || len(packets[i].payload) != e.size {
+			t.Errorf("packet %d: got flags %x (size=%d) expected %x (size=%d)",
+				i, packets[i].flags, len(packets[i].payload), e.flags, e.size)
+		}
+	}
+
+	if !bytes.Equal(packets[3].payload, []byte("ping")) {
+		t.Errorf("got request payload %q expected 'ping'", packets[3].payload)
+	}
+
+	var received []byte
+	for _, packet := range packets[len(expected) : len(packets)-1] {
+		if packet.flags != tcpFlagPSH|tcpFlagACK || len(packet.payload) > pcapMaxSegmentSize {
+			t.Errorf("got response segment flags %x (size=%d)", packet.flags, len(packet.payload))
+		}
+
+		received = append(received, packet.payload...)
+	}
+
+	if !bytes.Equal(received, response) {
+		t.Errorf("got %d response bytes expected %d", len(received), len(response))
+	}
+
+	if last := packets[len(packets)-1]; last.flags != tcpFlagFIN|tcpFlagACK || len(last.payload) != 0 {
+		t.Errorf("got last packet flags %x (size=%d) expected FIN", last.flags, len(last.payload))
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"fmt"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// planCall is a planned probe command call
+type planCall struct {
+	method   string
+	protocol string
+	target   string
+	socket   string
+}
+
+func (c planCall) String() string {
+	if c.socket != "" {
+		return fmt.Sprintf("%s %s (socket=%s)", c.method, c.target, c.socket)
+	}
+
+	return fmt.Sprintf("%s %s", c.method, c.target)
+}
+
+// Plan returns the probe command calls for the probe targets (e.g., 'GET http://127.0.0.1:8080/health')
+// after the port filtering and the protocol expansion. No network calls are made,
+// so both 'http' and 'https' are planned for the ports with the unknown scheme.
+func (p *CustomProbe) Plan() []string {
+	var plan []string
+	for _, call := range p.planCalls() {
+		plan = append(plan, call.String())
+	}
+
+	return plan
+}
+
+func (p *CustomProbe) planCalls() []planCall {
+	var calls []planCall
+	known := map[planCall]bool{}
+	for targetIdx, target := range p.probeTargets() {
+		for cmdIdx, cmd := range p.opts.Cmds {
+			if cmdUnixSocket(cmd) != target.socket || !p.cmdTargetPort(cmd, target) {
+				continue
+			}
+
+			if isExecCmd(cmd) {
+				//the exec commands run once (in the target container)
+				call := planCall{
+					method:   execCmdMethod,
+					protocol: execCmdProtocol,
+					target:   execCmdString(cmd),
+				}
+
+				if !known[call] {
+					known[call] = true
+					calls = append(calls, call)
+				}
+				continue
+			}
+
+			targetHost, port := target.host, target.port
+			cmd = p.expandVars(cmdVarScope(cmdIdx, targetIdx), cmd)
+			if cmd.BaseURL != "" {
+				var err error
+				if cmd, targetHost, port, err = withBaseURL(cmd); err != nil {
+					log.Debugf("HTTP probe - plan - cmd.BaseURL (%s) error: %v", cmd.BaseURL, err)
+					continue
+				}
+			}
+
+			if target.socket != "" {
+				targetHost, port = unixSocketHost, ""
+				if cmd.Protocol == "" {
+					cmd.Protocol = config.ProtoHTTP
+				}
+			}
+
+			for _, proto := range p.cmdProtocols(cmd, targetHost, port, false) {
+				call := planCall{
+					method:   cmd.Method,
+					protocol: proto,
+					socket:   target.socket,
+				}
+
+				if IsValidWSProto(proto) {
+					call.target = fmt.Sprintf("%s://%s%s", proto, net.JoinHostPort(targetHost, port), wsResource(cmd.Resource))
+				} else {
+					call.target = getHTTPAddr(proto, targetHost, port) + cmd.Resource
+				}
+
+				//the base URL commands have the same calls for all targets
+				if known[call] {
+					continue
+				}
+
+				known[call] = true
+				calls = append(calls, call)
+			}
+		}
+	}
+
+	return calls
+}
+
+// printPlan prints the probe plan (for the dry run probes)
+func (p *CustomProbe) printPlan() {
+	calls := p.planCalls()
+	for _, call := range calls {
+		info := ovars{
+			"method":   call.method,
+			"protocol": call.protocol,
+			"target":   call.target,
+		}
+
+		if call.socket != "" {
+			info["socket"] = call.socket
+		}
+
+		p.xc.Out.Info("http.probe.plan.call", info)
+	}
+
+	p.xc.Out.Info("http.probe.plan",
+		ovars{
+			"calls": len(calls),
+		})
+}
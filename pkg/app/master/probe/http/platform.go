@@ -0,0 +1,77 @@
+package http
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const platformAny = "*"
+
+// SkippedCmd is a probe command skipped because
+// its conditions don't match the target
+type SkippedCmd struct {
+	Cmd    config.HTTPProbeCmd
+	Reason string
+}
+
+func matchPlatformValue(condition, value string) bool {
+	return condition == platformAny || strings.EqualFold(condition, value)
+}
+
+// matchPlatform checks if the target platform matches
+// any of the "os" or "os/arch" platform conditions
+func matchPlatform(conditions []string, osName, arch string) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+
+	for _, condition := range conditions {
+		parts := strings.SplitN(strings.TrimSpace(condition), "/", 2)
+		if !matchPlatformValue(parts[0], osName) {
+			continue
+		}
+
+		if len(parts) == 1 || matchPlatformValue(parts[1], arch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterPlatformCmds removes the commands with platform conditions
+// that don't match the target image platform
+func (p *CustomProbe) filterPlatformCmds(osName, arch string) {
+	if osName == "" && arch == "" {
+		log.Debug("HTTP probe - unknown target platform (ignoring the command platform conditions)")
+		return
+	}
+
+	log.Debugf("HTTP probe - target platform => %s/%s", osName, arch)
+
+	var cmds []config.HTTPProbeCmd
+	for _, cmd := range p.opts.Cmds {
+		if matchPlatform(cmd.Platforms, osName, arch) {
+			cmds = append(cmds, cmd)
+			continue
+		}
+
+		log.Debugf("HTTP probe - skipping command (platforms=%v) => %s %s",
+			cmd.Platforms, cmd.Method, cmd.Resource)
+
+		p.skippedCmds = append(p.skippedCmds, SkippedCmd{
+			Cmd:    cmd,
+			Reason: "platform (" + osName + "/" + arch + ")",
+		})
+	}
+
+	p.opts.Cmds = cmds
+}
+
+// SkippedCmds returns the probe commands skipped because of their conditions
+func (p *CustomProbe) SkippedCmds() []SkippedCmd {
+	return p.skippedCmds
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"fmt"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// selectedPorts returns the probe ports for the selected ports (the '--http-probe-ports' values).
+// The selected ports are the container ports (e.g., the 'EXPOSE' ports) and they are translated
+// to the published host ports (unless the container ports are probed directly).
+// The published host ports are also accepted if they are not the container ports.
+func (p *CustomProbe) selectedPorts(available map[dockerapi.Port]dockerapi.PortBinding, direct bool) []string {
+	hostPorts := map[string]string{}
+	for portKey, portData := range available {
+		if portKey.Proto() == "tcp" && portData.HostPort != "" {
+			hostPorts[portData.HostPort] = portKey.Port()
+		}
+	}
+
+	var ports []string
+	for _, pnum := range p.opts.Ports {
+		port := fmt.Sprintf("%d", pnum)
+		pspec := dockerapi.Port(fmt.Sprintf("%v/tcp", pnum))
+		if binding, ok := available[pspec]; ok {
+			if direct {
+				ports = append(ports, port)
+				continue
+			}
+
+			p.printPortMapping(port, binding.HostPort)
+			ports = append(ports, binding.HostPort)
+			continue
+		}
+
+		if containerPort, ok := hostPorts[port]; ok {
+			log.Debugf("HTTP probe - selected port is a host port => %s (container port %s)", port, containerPort)
+			if direct {
+				p.printPortMapping(containerPort, port)
+				ports = append(ports, containerPort)
+				continue
+			}
+
+			ports = append(ports, port)
+			continue
+		}
+
+		log.Debugf("HTTP probe - ignoring port => %v", pspec)
+	}
+
+	return ports
+}
+
+// printPortMapping prints the container port to host port translation (if the ports are different)
+func (p *CustomProbe) printPortMapping(containerPort, hostPort string) {
+	if containerPort == hostPort {
+		return
+	}
+
+	log.Debugf("HTTP probe - port mapping => container port %s -> host port %s", containerPort, hostPort)
+	if p.printState {
+		p.xc.Out.Info("http.probe.port.mapped",
+			ovars{
+				"container.port": containerPort,
+				"host.port":      hostPort,
+			})
+	}
+}
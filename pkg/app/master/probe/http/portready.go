@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPortReadyTimeout = 60 * time.Second
+
+	portReadyDialTimeout         = 2 * time.Second
+	defaultPortReadyPollInterval = 500 * time.Millisecond
+	//how long an accepted connection needs to stay open for the port to be ready
+	portReadyReadWait = 200 * time.Millisecond
+)
+
+// waitForPortReady polls the target port until it accepts a connection or the timeout passes.
+// The port forwarding proxies (e.g., docker-proxy) accept the connections before the app
+// is listening and then close them right away, so the accepted connection also needs
+// to stay open (waiting for the request) for the port to be ready.
+func (p *CustomProbe) waitForPortReady(host string, port string, timeout time.Duration) bool {
+	return p.waitForAddrReady("tcp", net.JoinHostPort(host, port), timeout)
+}
+
+// waitForAddrReady polls the network address (a TCP address or a Unix socket path)
+// until it accepts a connection that stays open or the timeout passes
+func (p *CustomProbe) waitForAddrReady(network, addr string, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{
+		Timeout:  portReadyDialTimeout,
+		Resolver: p.clientOpts.dialer.Resolver,
+	}
+
+	for attempt := 1; ; attempt++ {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err == nil {
+			conn.SetReadDeadline(time.Now().Add(portReadyReadWait))
+			var buf [1]byte
+			_, err = conn.Read(buf[:])
+			conn.Close()
+
+			if err == nil || errors.Is(err, os.ErrDeadlineExceeded) {
+				log.Debugf("HTTP probe - port ready (%s, attempt=%d)", addr, attempt)
+				return true
+			}
+		}
+
+		log.Tracef("HTTP probe - port not ready (%s, attempt=%d) - %v", addr, attempt, err)
+
+		timer := time.NewTimer(p.portReadyInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// portReady returns true if the target port is ready for the probe calls
+// (the probe commands for the port are skipped if the port doesn't open before the timeout)
+func (p *CustomProbe) portReady(host, port string) bool {
+	if p.portReadyTimeout == 0 {
+		return true
+	}
+
+	if p.clientOpts.proxied(host, port) {
+		//the target might not be reachable without the proxy
+		return true
+	}
+
+	if p.waitForPortReady(host, port, p.portReadyTimeout) {
+		return true
+	}
+
+	if p.stopped() {
+		return false
+	}
+
+	if p.printState {
+		p.xc.Out.State("http.probe.port.timeout",
+			ovars{
+				"host":    host,
+				"port":    port,
+				"timeout": p.portReadyTimeout.String(),
+			})
+	}
+
+	return false
+}
+
+// targetReady returns true if the probe target (a host/port pair or a Unix socket) is ready for the probe calls
+func (p *CustomProbe) targetReady(target probeTarget) bool {
+	if target.socket != "" {
+		return p.socketReady(target.socket)
+	}
+
+	return p.portReady(target.host, target.port)
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrAmbiguousProbePorts = errors.New("no exposed or target ports to select the probe ports")
+
+// the common web service ports (probed first when the image doesn't expose any ports)
+var wellKnownHTTPPorts = []string{"80", "443", "8080", "8443", "8000", "3000", "5000"}
+
+func wellKnownPortRank(port string) int {
+	for idx, val := range wellKnownHTTPPorts {
+		if val == port {
+			return idx
+		}
+	}
+
+	return len(wellKnownHTTPPorts)
+}
+
+// orderFallbackPorts orders the available ports (host port -> container port)
+// when there are no exposed or target ports: the well-known web service ports go first
+// and the other ports are sorted by their container port number
+// (returns the host ports)
+func orderFallbackPorts(availableHostPorts map[string]string) []string {
+	var hostPorts []string
+	for hostPort := range availableHostPorts {
+		hostPorts = append(hostPorts, hostPort)
+	}
+
+	sort.Slice(hostPorts, func(i, j int) bool {
+		a, b := availableHostPorts[hostPorts[i]], availableHostPorts[hostPorts[j]]
+		if ar, br := wellKnownPortRank(a), wellKnownPortRank(b); ar != br {
+			return ar < br
+		}
+
+		if a != b {
+			return comparePorts(a, b)
+		}
+
+		return comparePorts(hostPorts[i], hostPorts[j])
+	})
+
+	return hostPorts
+}
+
+// checkFallbackPorts returns an error if the probe has to guess which of the available ports to probe
+// (the container ports are used in the error guidance)
+func checkFallbackPorts(availableHostPorts map[string]string) error {
+	if len(availableHostPorts) < 2 {
+		return nil
+	}
+
+	var containerPorts []string
+	for _, hostPort := range orderFallbackPorts(availableHostPorts) {
+		containerPorts = append(containerPorts, availableHostPorts[hostPort])
+	}
+
+	return fmt.Errorf("%w - the image has no EXPOSE instructions and the container has multiple ports (%s); select the ports to probe with --http-probe-ports",
+		ErrAmbiguousProbePorts, strings.Join(containerPorts, ", "))
+}
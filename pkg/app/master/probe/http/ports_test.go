@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
+	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/image"
+	"github.com/slimtoolkit/slim/pkg/docker/dockerfile/reverse"
+)
+
+// newNoExposeInspector creates a container inspector for an image without EXPOSE instructions
+// (the container port -> host port)
+func newNoExposeInspector(ipcMode string, ports map[string]string) *container.Inspector {
+	available := map[dockerapi.Port]dockerapi.PortBinding{}
+	for containerPort, hostPort := range ports {
+		available[dockerapi.Port(containerPort+"/tcp")] = dockerapi.PortBinding{HostIP: "127.0.0.1", HostPort: hostPort}
+	}
+
+	return &container.Inspector{
+		ImageInspector: &image.Inspector{DockerfileInfo: &reverse.Dockerfile{}},
+		AvailablePorts: available,
+		SensorIPCMode:  ipcMode,
+		TargetHost:     "127.0.0.1",
+	}
+}
+
+func TestContainerProbeNoExposedPortsOrder(t *testing.T) {
+	ports := map[string]string{
+		"9229": "32001",
+		"8080": "32002",
+		"6000": "32003",
+		"80":   "32004",
+		"5432": "32005",
+	}
+
+	tests := []struct {
+		ipcMode  string
+		expected []string
+	}{
+		{ipcMode: container.SensorIPCModeDirect, expected: []string{"80", "8080", "5432", "6000", "9229"}},
+		{ipcMode: "proxy", expected: []string{"32004", "32002", "32005", "32003", "32001"}},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			probe, err := NewContainerProbe(xc, newNoExposeInspector(test.ipcMode, ports), config.HTTPProbeOptions{}, false)
+			if err != nil {
+				t.Fatalf("ipc=%s: unexpected error: %v", test.ipcMode, err)
+			}
+
+			if !reflect.DeepEqual(probe.Ports(), test.expected) {
+				t.Fatalf("ipc=%s: ports = %v, expected %v", test.ipcMode, probe.Ports(), test.expected)
+			}
+		}
+	}
+}
+
+func TestContainerProbeNoExposedPortsRequireExplicit(t *testing.T) {
+	xc := app.NewExecutionContext("probe", true, "text")
+	opts := config.HTTPProbeOptions{RequireExplicitPorts: true}
+
+	ambiguous := newNoExposeInspector(container.SensorIPCModeDirect, map[string]string{"9229": "32001", "3000": "32002"})
+	if _, err := NewContainerProbe(xc, ambiguous, opts, false); !errors.Is(err, ErrAmbiguousProbePorts) {
+		t.Fatalf("expected ErrAmbiguousProbePorts, got %v", err)
+	}
+
+	single := newNoExposeInspector(container.SensorIPCModeDirect, map[string]string{"3000": "32002"})
+	probe, err := NewContainerProbe(xc, single, opts, false)
+	if err != nil {
+		t.Fatalf("single port: unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(probe.Ports(), []string{"3000"}) {
+		t.Fatalf("single port: ports = %v", probe.Ports())
+	}
+
+	opts.Ports = []uint16{9229}
+	probe, err = NewContainerProbe(xc, ambiguous, opts, false)
+	if err != nil {
+		t.Fatalf("target ports: unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(probe.Ports(), []string{"9229"}) {
+		t.Fatalf("target ports: ports = %v", probe.Ports())
+	}
+}
+
+func TestContainerProbeExposedPortProtocols(t *testing.T) {
+	inspector := &container.Inspector{
+		ImageInspector: &image.Inspector{
+			DockerfileInfo: &reverse.Dockerfile{ExposedPorts: []string{"80/tcp", "53/udp"}},
+		},
+		AvailablePorts: map[dockerapi.Port]dockerapi.PortBinding{
+			"53/udp": {HostIP: "127.0.0.1", HostPort: "32053"},
+			"80/tcp": {HostIP: "127.0.0.1", HostPort: "32080"},
+		},
+		TargetHost: "127.0.0.1",
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	for _, ipcMode := range []string{container.SensorIPCModeDirect, "proxy"} {
+		inspector.SensorIPCMode = ipcMode
+		probe, err := NewContainerProbe(xc, inspector, config.HTTPProbeOptions{}, false)
+		if err != nil {
+			t.Fatalf("ipc=%s: unexpected error: %v", ipcMode, err)
+		}
+
+		expected := []string{"32080"}
+		if ipcMode == container.SensorIPCModeDirect {
+			expected = []string{"80"}
+		}
+
+		if !reflect.DeepEqual(probe.Ports(), expected) {
+			t.Fatalf("ipc=%s: ports = %v, expected %v", ipcMode, probe.Ports(), expected)
+		}
+
+		if !reflect.DeepEqual(probe.NonTCPPorts(), []string{"53/udp"}) {
+			t.Fatalf("ipc=%s: non-tcp ports = %v, expected [53/udp]", ipcMode, probe.NonTCPPorts())
+		}
+	}
+}
+
+func TestContainerProbeSelectedPortMapping(t *testing.T) {
+	ports := map[string]string{
+		"8080": "32769",
+		"9000": "32770",
+	}
+
+	tests := []struct {
+		ipcMode  string
+		selected []uint16
+		expected []string
+	}{
+		//the container ports are translated to the host ports
+		{ipcMode: "proxy", selected: []uint16{8080}, expected: []string{"32769"}},
+		{ipcMode: container.SensorIPCModeDirect, selected: []uint16{8080}, expected: []string{"8080"}},
+		//the host ports are accepted too
+		{ipcMode: "proxy", selected: []uint16{32770, 8080}, expected: []string{"32770", "32769"}},
+		{ipcMode: container.SensorIPCModeDirect, selected: []uint16{32770}, expected: []string{"9000"}},
+		//the unknown ports are ignored
+		{ipcMode: "proxy", selected: []uint16{3000, 9000}, expected: []string{"32770"}},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	for _, test := range tests {
+		opts := config.HTTPProbeOptions{Ports: test.selected}
+		probe, err := NewContainerProbe(xc, newNoExposeInspector(test.ipcMode, ports), opts, false)
+		if err != nil {
+			t.Fatalf("ipc=%s ports=%v: unexpected error: %v", test.ipcMode, test.selected, err)
+		}
+
+		if !reflect.DeepEqual(probe.Ports(), test.expected) {
+			t.Fatalf("ipc=%s ports=%v: probe ports = %v, expected %v", test.ipcMode, test.selected, probe.Ports(), test.expected)
+		}
+	}
+}
+
+func TestContainerProbeContainerIPTargets(t *testing.T) {
+	inspector := newNoExposeInspector("proxy", map[string]string{"8080": "32769"})
+	inspector.ContainerInfo = &dockerapi.Container{
+		NetworkSettings: &dockerapi.NetworkSettings{
+			Networks: map[string]dockerapi.ContainerNetwork{
+				"bridge": {IPAddress: "172.17.0.2"},
+			},
+		},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	probe, err := NewContainerProbe(xc, inspector, config.HTTPProbeOptions{ContainerIP: true}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []probeTarget{
+		{host: "127.0.0.1", port: "32769"},
+		{host: "172.17.0.2", port: "8080", hostPort: "32769"},
+	}
+
+	if targets := probe.probeTargets(); !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("targets = %+v, expected %+v", targets, expected)
+	}
+
+	inspector.SensorIPCMode = container.SensorIPCModeDirect
+	probe, err = NewContainerProbe(xc, inspector, config.HTTPProbeOptions{ContainerIP: true}, false)
+	if err != nil {
+		t.Fatalf("direct: unexpected error: %v", err)
+	}
+
+	if targets := probe.probeTargets(); len(targets) != 1 || targets[0].hostPort != "" {
+		t.Fatalf("direct: targets = %+v", targets)
+	}
+}
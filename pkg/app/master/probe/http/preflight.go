@@ -0,0 +1,71 @@
+package http
+
+import (
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// preflightCmdIdx is the command index for the preflight command calls
+// (they are not the probe command calls)
+const preflightCmdIdx = -1
+
+// preflight runs the preflight commands once (in order) before the probe commands.
+// Each command runs on the first ready probe target it can run on (with the regular call retries).
+// Returns false if a preflight command fails (the next preflight commands are not executed then).
+func (p *CustomProbe) preflight() bool {
+	for _, cmd := range p.opts.PreflightCmds {
+		if p.stopped() {
+			return false
+		}
+
+		start := time.Now()
+		ok, reason := p.preflightCmd(cmd)
+		info := ovars{
+			"method":   cmd.Method,
+			"resource": cmd.Resource,
+			"duration": time.Since(start).Round(time.Millisecond).String(),
+		}
+
+		if !ok {
+			log.Debugf("HTTP probe - preflight command failed (%s) => %s %s", reason, cmd.Method, cmd.Resource)
+			info["reason"] = reason
+			if p.opts.PreflightAbort {
+				atomic.StoreUint32(&p.preflightFailed, 1)
+				info["action"] = "abort"
+			}
+
+			p.xc.Out.State("http.probe.preflight.failed", info)
+			return false
+		}
+
+		if p.printState {
+			p.xc.Out.Info("http.probe.preflight", info)
+		}
+	}
+
+	return true
+}
+
+func (p *CustomProbe) preflightCmd(cmd config.HTTPProbeCmd) (bool, string) {
+	for targetIdx, target := range p.probeTargets() {
+		if cmdUnixSocket(cmd) != target.socket || !p.cmdTargetPort(cmd, target) {
+			continue
+		}
+
+		if !p.targetReady(target) {
+			continue
+		}
+
+		if p.probeCmd(preflightCmdIdx, cmd, targetIdx, target.host, target.port) {
+			return true, ""
+		}
+
+		return false, "failed.calls"
+	}
+
+	return false, "no.ready.targets"
+}
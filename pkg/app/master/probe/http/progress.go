@@ -0,0 +1,256 @@
+package http
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const progressEventsBufferSize = 256
+
+// ProgressEventType is the probe progress event type
+type ProgressEventType string
+
+// probe progress event types
+const (
+	ProgressEventStarted ProgressEventType = "started"
+	ProgressEventCall    ProgressEventType = "call"
+	ProgressEventSummary ProgressEventType = "summary"
+	ProgressEventDone    ProgressEventType = "done"
+)
+
+// ProgressEvent is a probe progress event (for the library users who need
+// to react to the probe progress and the individual call results)
+type ProgressEvent struct {
+	Type ProgressEventType
+	Time time.Time
+	//the call result (for the 'call' events)
+	Call *CallResult
+	//the call is a probe command call
+	//(the other calls are the API spec calls and the extra call checks)
+	Command bool
+	//the call error category (for the failed calls)
+	ErrorCategory string
+	//the probe call counts (for the 'summary' and 'done' events)
+	Summary *ProgressSummary
+}
+
+// ProgressSummary provides the probe call counts
+type ProgressSummary struct {
+	Total       uint64
+	Failures    uint64
+	Successful  uint64
+	SkippedCmds int
+	NotFound    int
+	HeaderLimit uint64
+}
+
+// Events returns the probe progress event channel
+// It needs to be called before the probe is started and the events need to be consumed
+// (the probe waits for the channel buffer to have space for the next event).
+// The channel is closed when the probe is done.
+func (p *CustomProbe) Events() <-chan ProgressEvent {
+	p.progressOnce.Do(func() {
+		p.progress = make(chan ProgressEvent, progressEventsBufferSize)
+	})
+
+	return p.progress
+}
+
+// publish prints the event (if the probe prints its state)
+// and sends it to the progress event channel (if there's one)
+func (p *CustomProbe) publish(event ProgressEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if p.printState {
+		p.printEvent(event)
+	}
+
+	p.send(event)
+}
+
+// send sends the event to the progress event channel (if there's one) without printing it
+func (p *CustomProbe) send(event ProgressEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if p.progress != nil {
+		p.progress <- event
+	}
+}
+
+func (p *CustomProbe) closeEvents() {
+	if p.progress != nil {
+		close(p.progress)
+	}
+}
+
+func (p *CustomProbe) progressSummary() *ProgressSummary {
+	return &ProgressSummary{
+		Total:       atomic.LoadUint64(&p.CallCount),
+		Failures:    atomic.LoadUint64(&p.ErrCount),
+		Successful:  atomic.LoadUint64(&p.OkCount),
+		SkippedCmds: len(p.skippedCmds),
+		NotFound:    p.notFoundCallCount(),
+		HeaderLimit: atomic.LoadUint64(&p.headerLimitCount),
+	}
+}
+
+// addCommandCallResult records the probe command call result
+func (p *CustomProbe) addCommandCallResult(result CallResult, err error) {
+	p.publish(p.commandCallEvent(result, err))
+}
+
+// commandCallEvent records the probe command call result and returns its progress event
+func (p *CustomProbe) commandCallEvent(result CallResult, err error) ProgressEvent {
+	p.recordCallResult(result)
+
+	event := ProgressEvent{
+		Type:    ProgressEventCall,
+		Time:    time.Now(),
+		Call:    &result,
+		Command: true,
+	}
+
+	if err != nil {
+		event.ErrorCategory = errorCategory(err)
+	}
+
+	return event
+}
+
+// addCommandAttemptResult records the probe command call attempt result.
+// The failed attempts are printed only in the verbose mode (returns the event for the failed attempt
+// in the normal mode, so it can be printed if it's the last attempt for the command target).
+func (p *CustomProbe) addCommandAttemptResult(result CallResult, err error) *ProgressEvent {
+	event := p.commandCallEvent(result, err)
+	if err == nil || p.opts.Verbose {
+		p.publish(event)
+		return nil
+	}
+
+	p.send(event)
+	return &event
+}
+
+// retrySleep waits before the next call attempt
+// (the retry wait is printed in the verbose mode if there's a next attempt)
+func (p *CustomProbe) retrySleep(call CallResult, maxAttempts int, wait time.Duration) bool {
+	if p.printState && p.opts.Verbose && call.Attempt < maxAttempts {
+		p.xc.Out.Info("http.probe.call.retry",
+			ovars{
+				"method":  call.Method,
+				"target":  call.Target,
+				"attempt": call.Attempt,
+				"wait":    wait.String(),
+			})
+	}
+
+	return p.sleep(wait)
+}
+
+// printAttemptResult prints the last failed call attempt for the command target (in the normal mode)
+func (p *CustomProbe) printAttemptResult(event *ProgressEvent) {
+	if event != nil && p.printState {
+		p.printEvent(*event)
+	}
+}
+
+// printEvent prints the probe state for the progress event
+func (p *CustomProbe) printEvent(event ProgressEvent) {
+	switch event.Type {
+	case ProgressEventStarted:
+		p.xc.Out.State("http.probe.starting",
+			ovars{
+				"message": "WAIT FOR HTTP PROBE TO FINISH",
+			})
+	case ProgressEventCall:
+		if !event.Command {
+			return
+		}
+
+		call := event.Call
+		callErrorStr := "none"
+		if call.Error != "" {
+			callErrorStr = call.Error
+		}
+
+		statusCode := "error"
+		if call.StatusCode != 0 {
+			statusCode = fmt.Sprintf("%v", call.StatusCode)
+		}
+
+		callInfo := ovars{
+			"status":  statusCode,
+			"method":  call.Method,
+			"target":  call.Target,
+			"attempt": call.Attempt,
+			"error":   callErrorStr,
+			"time":    event.Time.UTC().Format(time.RFC3339),
+		}
+
+		if call.RequestID != "" {
+			callInfo["request.id"] = call.RequestID
+		}
+
+		if call.Credential != "" {
+			callInfo["credential"] = call.Credential
+		}
+
+		if call.Proto != "" {
+			//the negotiated protocol (e.g., to confirm the HTTP/2 calls)
+			callInfo["protocol"] = call.Proto
+		}
+
+		if call.Socket != "" {
+			callInfo["socket"] = call.Socket
+		}
+
+		if call.BodyCheck != "" {
+			callInfo["body.check"] = call.BodyCheck
+		}
+
+		if call.Repeat > 0 {
+			callInfo["repeat"] = call.Repeat
+		}
+
+		if event.ErrorCategory != "" {
+			callInfo["error.category"] = event.ErrorCategory
+		}
+
+		p.xc.Out.Info("http.probe.call", callInfo)
+	case ProgressEventSummary:
+		summary := ovars{
+			"total":      event.Summary.Total,
+			"failures":   event.Summary.Failures,
+			"successful": event.Summary.Successful,
+		}
+
+		if event.Summary.SkippedCmds > 0 {
+			summary["skipped.commands"] = event.Summary.SkippedCmds
+		}
+
+		if event.Summary.NotFound > 0 {
+			summary["not.found"] = event.Summary.NotFound
+		}
+
+		if event.Summary.HeaderLimit > 0 {
+			summary["header.limit"] = event.Summary.HeaderLimit
+		}
+
+		p.xc.Out.Info("http.probe.summary", summary)
+	case ProgressEventDone:
+		outVars := ovars{}
+		switch {
+		case event.Summary.Total == 0:
+			outVars["warning"] = "no.calls"
+		case event.Summary.Successful == 0:
+			outVars["warning"] = "no.successful.calls"
+		}
+
+		p.xc.Out.State("http.probe.done", outVars)
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// printProtocolChain reports the protocol that succeeded for the command protocol chain
+// (the command is successful on the first protocol with a successful call)
+func (p *CustomProbe) printProtocolChain(cmd config.HTTPProbeCmd, port, proto string) {
+	if !p.printState {
+		return
+	}
+
+	status := "ok"
+	if proto == "" {
+		status = "failed"
+		proto = "none"
+	}
+
+	p.xc.Out.Info("http.probe.call.protocol.chain",
+		ovars{
+			"status":   status,
+			"method":   cmd.Method,
+			"resource": cmd.Resource,
+			"port":     port,
+			"chain":    strings.Join(cmd.ProtocolChain, ","),
+			"protocol": proto,
+		})
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	headerRange        = "Range"
+	headerContentRange = "Content-Range"
+
+	defaultProbeRange     = "0-1023"
+	multipartByteRanges   = "multipart/byteranges"
+	maxRangeResponseSize  = 4 * 1024 * 1024
+	contentRangeUnitBytes = "bytes "
+)
+
+var ErrBadContentRange = errors.New("unexpected content range")
+
+// byteRange is a requested byte range (-1 means the value is not set)
+type byteRange struct {
+	start  int64
+	end    int64
+	suffix int64 //the last N bytes ("-N")
+}
+
+func parseByteRange(value string) (byteRange, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "-", 2)
+	if len(parts) != 2 || (parts[0] == "" && parts[1] == "") {
+		return byteRange{}, fmt.Errorf("bad range - '%s'", value)
+	}
+
+	br := byteRange{start: -1, end: -1, suffix: -1}
+	if parts[0] == "" {
+		suffix, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return br, err
+		}
+		br.suffix = suffix
+		return br, nil
+	}
+
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return br, err
+	}
+	br.start = start
+
+	if parts[1] != "" {
+		end, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return br, err
+		}
+		br.end = end
+	}
+
+	if br.end > -1 && br.end < br.start {
+		return br, fmt.Errorf("bad range - '%s'", value)
+	}
+
+	return br, nil
+}
+
+func probeRanges(cmd config.HTTPProbeCmd) []string {
+	if len(cmd.Ranges) == 0 {
+		return []string{defaultProbeRange}
+	}
+
+	return cmd.Ranges
+}
+
+// withRangeDefaults adds the Range header for the 'range' probe mode
+// (the header provided by the user is preserved)
+func withRangeDefaults(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	if !hasHeader(cmd.Headers, headerRange) {
+		cmd.Headers = append(append([]string{}, cmd.Headers...),
+			fmt.Sprintf("%s: bytes=%s", headerRange, strings.Join(probeRanges(cmd), ",")))
+	}
+
+	return cmd
+}
+
+// parseContentRange parses the 'bytes start-end/total' Content-Range header value
+func parseContentRange(value string) (int64, int64, error) {
+	if !strings.HasPrefix(value, contentRangeUnitBytes) {
+		return 0, 0, fmt.Errorf("%w ('%s')", ErrBadContentRange, value)
+	}
+
+	spec := strings.TrimPrefix(value, contentRangeUnitBytes)
+	if idx := strings.Index(spec, "/"); idx != -1 {
+		spec = spec[:idx]
+	}
+
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%w ('%s')", ErrBadContentRange, value)
+	}
+
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w ('%s')", ErrBadContentRange, value)
+	}
+
+	end, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("%w ('%s')", ErrBadContentRange, value)
+	}
+
+	return start, end, nil
+}
+
+// checkRangePart checks the returned range against the requested range
+// (the end can be smaller than requested when the resource is smaller than the range)
+func checkRangePart(requested byteRange, contentRange string, size int) error {
+	start, end, err := parseContentRange(contentRange)
+	if err != nil {
+		return err
+	}
+
+	if requested.start > -1 && start != requested.start {
+		return fmt.Errorf("%w (requested=%d-%d content.range='%s')",
+			ErrBadContentRange, requested.start, requested.end, contentRange)
+	}
+
+	if requested.end > -1 && end > requested.end {
+		return fmt.Errorf("%w (requested=%d-%d content.range='%s')",
+			ErrBadContentRange, requested.start, requested.end, contentRange)
+	}
+
+	if requested.suffix > -1 && end-start+1 > requested.suffix {
+		return fmt.Errorf("%w (requested=-%d content.range='%s')",
+			ErrBadContentRange, requested.suffix, contentRange)
+	}
+
+	if size > -1 && int64(size) != end-start+1 {
+		return fmt.Errorf("%w (content.range='%s' size=%d)", ErrBadContentRange, contentRange, size)
+	}
+
+	return nil
+}
+
+// checkRangeResponse checks the '206 Partial Content' response for the requested ranges.
+// The multipart/byteranges responses (for multiple ranges) need a part for each range.
+func checkRangeResponse(cmd config.HTTPProbeCmd, res *http.Response, body []byte, truncated bool) error {
+	if res.StatusCode != http.StatusPartialContent {
+		return &StatusError{StatusCode: res.StatusCode, Expected: []int{http.StatusPartialContent}}
+	}
+
+	var requested []byteRange
+	for _, val := range probeRanges(cmd) {
+		br, err := parseByteRange(val)
+		if err != nil {
+			return err
+		}
+
+		requested = append(requested, br)
+	}
+
+	size := len(body)
+	if truncated {
+		size = -1
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(res.Header.Get(headerContentType))
+	if mediaType != multipartByteRanges {
+		//the target may return a single range for multiple (overlapping or adjacent) ranges
+		return checkRangePart(requested[0], res.Header.Get(headerContentRange), size)
+	}
+
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var parts int
+	for ; ; parts++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if truncated {
+				//can't check the rest of the parts
+				break
+			}
+
+			return err
+		}
+
+		data, err := io.ReadAll(part)
+		partSize := len(data)
+		if err != nil {
+			partSize = -1
+		}
+
+		if parts >= len(requested) {
+			return fmt.Errorf("%w (too many parts: %d)", ErrBadContentRange, parts+1)
+		}
+
+		if err := checkRangePart(requested[parts], part.Header.Get(headerContentRange), partSize); err != nil {
+			return err
+		}
+	}
+
+	if !truncated && parts != len(requested) {
+		return fmt.Errorf("%w (parts=%d ranges=%d)", ErrBadContentRange, parts, len(requested))
+	}
+
+	return nil
+}
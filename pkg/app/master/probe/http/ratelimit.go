@@ -0,0 +1,40 @@
+package http
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitLogWait is the minimum rate limit wait time logged for the probe calls
+const rateLimitLogWait = 1 * time.Second
+
+// newCallLimiter returns the probe call rate limiter (nil if the calls are not limited).
+// The limiter is shared by all probe workers and it doesn't allow call bursts.
+func newCallLimiter(callsPerSecond float64) *rate.Limiter {
+	if callsPerSecond <= 0 {
+		return nil
+	}
+
+	return rate.NewLimiter(rate.Limit(callsPerSecond), 1)
+}
+
+// waitCallRate blocks until the next probe call is allowed by the call rate limit
+// (the wait is interrupted when the probe is stopped).
+// The wait happens before the call starts, so it's not included in the call duration and timeout.
+func (p *CustomProbe) waitCallRate() {
+	if p.callLimiter == nil {
+		return
+	}
+
+	start := time.Now()
+	if err := p.callLimiter.Wait(p.ctx); err != nil {
+		log.Debugf("HTTP probe - rate limit wait interrupted - %v", err)
+		return
+	}
+
+	if waited := time.Since(start); waited >= rateLimitLogWait {
+		log.Debugf("HTTP probe - rate limit wait => %v", waited.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	headerLocation = "Location"
+
+	maxProbeRedirects = 10
+)
+
+var (
+	ErrRedirectNotHTTPS = errors.New("redirect chain doesn't end at https")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+type redirectPolicy func(req *http.Request, via []*http.Request) error
+
+// limitRedirects returns the default redirect policy for the probe clients
+// (the redirects are followed up to the limit, so the redirect loops don't hang the probe)
+func limitRedirects(maxRedirects int) redirectPolicy {
+	if maxRedirects <= 0 {
+		maxRedirects = maxProbeRedirects
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("%w (stopped after %d redirects)", ErrTooManyRedirects, maxRedirects)
+		}
+
+		return nil
+	}
+}
+
+// noRedirects returns the redirect responses as-is
+func noRedirects(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// cmdRedirectPolicy returns the redirect policy for the command calls.
+// The redirects are not followed if the command has 'follow_redirects' set to false.
+// With 'expect_https' the redirects are followed until the redirect target is an https URL
+// (the https target may not be reachable from the probe, e.g., the default https port is not published).
+func cmdRedirectPolicy(cmd config.HTTPProbeCmd, maxRedirects int) redirectPolicy {
+	if cmd.FollowRedirects != nil && !*cmd.FollowRedirects {
+		return noRedirects
+	}
+
+	limit := limitRedirects(maxRedirects)
+	if !cmd.ExpectHTTPS {
+		return limit
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if err := limit(req, via); err != nil {
+			return err
+		}
+
+		if req.URL.Scheme == config.ProtoHTTPS {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+}
+
+// redirectChain returns the URLs from the original request to the final URL
+// (including the target of the last redirect response if it wasn't followed)
+func redirectChain(res *http.Response) []string {
+	if res == nil || res.Request == nil {
+		return nil
+	}
+
+	var chain []string
+	for req := res.Request; req != nil; {
+		chain = append([]string{req.URL.String()}, chain...)
+		if req.Response == nil {
+			break
+		}
+
+		req = req.Response.Request
+	}
+
+	if isRedirectStatus(res.StatusCode) {
+		if location, err := res.Location(); err == nil {
+			chain = append(chain, location.String())
+		}
+	}
+
+	return chain
+}
+
+func isRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// finalURL returns the last URL in the redirect chain
+func finalURL(res *http.Response) string {
+	chain := redirectChain(res)
+	if len(chain) == 0 {
+		return ""
+	}
+
+	return chain[len(chain)-1]
+}
+
+// checkHTTPSRedirect checks that the redirect chain ends at an https URL
+func checkHTTPSRedirect(res *http.Response) error {
+	chain := redirectChain(res)
+	log.Tracef("HTTP probe - redirect chain => %s", strings.Join(chain, " -> "))
+
+	if len(chain) == 0 || !strings.HasPrefix(chain[len(chain)-1], config.ProtoHTTPS+"://") {
+		return fmt.Errorf("%w (%s)", ErrRedirectNotHTTPS, strings.Join(chain, " -> "))
+	}
+
+	return nil
+}
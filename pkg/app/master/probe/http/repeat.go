@@ -0,0 +1,84 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// cmdRepeatCount returns the number of the repeat calls for the command
+// (the command repeat count overrides the probe repeat count)
+func cmdRepeatCount(cmd config.HTTPProbeCmd, defaultCount int) int {
+	if cmd.RepeatCount > 0 {
+		return cmd.RepeatCount
+	}
+
+	return defaultCount
+}
+
+// repeatRoundTrip makes the additional calls after the first successful command call,
+// so the code loaded on the later requests (e.g., by the JIT or the lazy module loaders)
+// is also used by the app. The repeat calls are not retried.
+func (p *CustomProbe) repeatRoundTrip(
+	client *http.Client,
+	req *http.Request,
+	cmdIdx int,
+	port string,
+	cmd config.HTTPProbeCmd,
+	count int) {
+	for i := 1; i <= count && !p.stopped(); i++ {
+		creq := req.Clone(req.Context())
+		if req.GetBody != nil {
+			creq.Body, _ = req.GetBody()
+		}
+
+		p.waitCallRate()
+		callStart := time.Now()
+		res, err := client.Do(creq)
+		callDuration := time.Since(callStart)
+		atomic.AddUint64(&p.CallCount, 1)
+
+		var statusCode int
+		var resProto string
+		if res != nil {
+			statusCode = res.StatusCode
+			resProto = res.Proto
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err == nil {
+			if expected := expectedStatus(cmd, p.opts.ExpectStatus); len(expected) > 0 && !isExpectedStatus(statusCode, expected) {
+				err = &StatusError{StatusCode: statusCode, Expected: expected}
+			}
+		}
+
+		p.addCommandCallResult(CallResult{
+			Time:       callStart,
+			Port:       port,
+			CmdIndex:   cmdIdx,
+			Method:     creq.Method,
+			Path:       cmd.Resource,
+			Target:     creq.URL.String(),
+			StatusCode: statusCode,
+			Proto:      resProto,
+			Attempt:    1,
+			Repeat:     i,
+			Duration:   callDuration,
+			Error:      errorString(err),
+		}, err)
+
+		if err != nil {
+			atomic.AddUint64(&p.ErrCount, 1)
+			log.Debugf("HTTP probe - repeat call error (%s %s) - %v", creq.Method, creq.URL.String(), err)
+			continue
+		}
+
+		atomic.AddUint64(&p.OkCount, 1)
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReportCall is a probe call record in the JSON probe report
+type ReportCall struct {
+	Time       string  `json:"time"`
+	Target     string  `json:"target"`
+	Method     string  `json:"method"`
+	Status     string  `json:"status"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Protocol   string  `json:"protocol,omitempty"`
+	Attempt    int     `json:"attempt"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+	RequestID  string  `json:"request_id,omitempty"`
+	Socket     string  `json:"socket,omitempty"`
+}
+
+// ReportSummary is the probe run summary in the JSON probe report
+type ReportSummary struct {
+	Total           uint64 `json:"total"`
+	Failures        uint64 `json:"failures"`
+	Successful      uint64 `json:"successful"`
+	Commands        uint64 `json:"commands"`
+	FailedCommands  uint64 `json:"failed_commands"`
+	SkippedCommands int    `json:"skipped_commands,omitempty"`
+	NotFoundRoutes  int    `json:"not_found_routes,omitempty"`
+	Severity        string `json:"severity"`
+	//the reason the probe result fails the command (an 'error' severity or a failed result assertion)
+	ResultError      string                  `json:"result_error,omitempty"`
+	ResultAssertions []ResultAssertionResult `json:"result_assertions,omitempty"`
+	//response time statistics for the probe commands
+	Timings []ReportTiming `json:"timings,omitempty"`
+	//the call counts for each probed port
+	Ports []PortSummary `json:"ports,omitempty"`
+}
+
+// ReportTiming is the probe command response time statistics in the JSON probe report
+type ReportTiming struct {
+	Method   string  `json:"method"`
+	Resource string  `json:"resource"`
+	Calls    int     `json:"calls"`
+	MinMS    float64 `json:"min_ms"`
+	AvgMS    float64 `json:"avg_ms"`
+	MaxMS    float64 `json:"max_ms"`
+	P95MS    float64 `json:"p95_ms"`
+}
+
+// ProbeReport is the JSON probe report with the probe call records and the summary
+type ProbeReport struct {
+	Calls   []ReportCall  `json:"calls"`
+	Summary ReportSummary `json:"summary"`
+}
+
+// Report returns the probe report (the complete report is available after the probe run)
+func (p *CustomProbe) Report() ProbeReport {
+	report := ProbeReport{
+		Calls: []ReportCall{},
+	}
+
+	for _, r := range p.sortedCallResults() {
+		report.Calls = append(report.Calls, ReportCall{
+			Time:       r.Time.UTC().Format(time.RFC3339Nano),
+			Target:     r.Target,
+			Method:     r.Method,
+			Status:     r.Status(),
+			StatusCode: r.StatusCode,
+			Protocol:   r.Proto,
+			Attempt:    r.Attempt,
+			DurationMS: durationMS(r.Duration),
+			Error:      r.Error,
+			RequestID:  r.RequestID,
+			Socket:     r.Socket,
+		})
+	}
+
+	result := p.Result()
+	report.Summary = ReportSummary{
+		Total:            result.Calls,
+		Failures:         result.Failures,
+		Successful:       result.Successful,
+		Commands:         result.Commands,
+		FailedCommands:   result.FailedCommands,
+		NotFoundRoutes:   result.NotFoundRoutes,
+		Severity:         p.Severity().Severity,
+		ResultError:      p.ResultError(),
+		ResultAssertions: p.ResultAssertions(),
+	}
+
+	for _, timing := range p.CmdTimings() {
+		report.Summary.Timings = append(report.Summary.Timings, ReportTiming{
+			Method:   timing.Method,
+			Resource: timing.Resource,
+			Calls:    timing.Calls,
+			MinMS:    durationMS(timing.Min),
+			AvgMS:    durationMS(timing.Avg),
+			MaxMS:    durationMS(timing.Max),
+			P95MS:    durationMS(timing.P95),
+		})
+	}
+
+	report.Summary.Ports = p.Summary().Ports
+
+	p.skippedMu.Lock()
+	report.Summary.SkippedCommands = len(p.skippedCmds)
+	p.skippedMu.Unlock()
+
+	return report
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func (p *CustomProbe) saveReport() {
+	if p.opts.ReportOutput == "" {
+		return
+	}
+
+	if err := writeReport(p.opts.ReportOutput, p.Report()); err != nil {
+		log.Warnf("HTTP probe - error saving the probe report (%s) - %v", p.opts.ReportOutput, err)
+		return
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.report",
+			ovars{
+				"file": p.opts.ReportOutput,
+			})
+	}
+}
+
+func writeReport(name string, report ProbeReport) error {
+	if dir := filepath.Dir(name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, 0644)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	defaultBodyContentType     = "application/json"
+	defaultBodyFileContentType = "application/octet-stream"
+)
+
+// bodyContentType returns the Content-Type for the command body
+// (the command content type if it's set, JSON for the JSON bodies,
+// the detected type for the other bodies and the type for the file extension for the body files)
+func bodyContentType(cmd config.HTTPProbeCmd) string {
+	if cmd.ContentType != "" {
+		return cmd.ContentType
+	}
+
+	if cmd.BodyFile != "" {
+		if ctype := mime.TypeByExtension(filepath.Ext(cmd.BodyFile)); ctype != "" {
+			return ctype
+		}
+
+		return defaultBodyFileContentType
+	}
+
+	if json.Valid([]byte(cmd.Body)) {
+		return defaultBodyContentType
+	}
+
+	return http.DetectContentType([]byte(cmd.Body))
+}
+
+// withBodyContentType adds the Content-Type header for the commands with a body
+// (the Content-Type header provided by the user is preserved)
+func withBodyContentType(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	if cmd.Mode == config.ProbeModeUpload ||
+		(cmd.Body == "" && cmd.BodyFile == "") ||
+		hasHeader(cmd.Headers, headerContentType) {
+		return cmd
+	}
+
+	cmd.Headers = append(append([]string{}, cmd.Headers...),
+		headerContentType+": "+bodyContentType(cmd))
+	return cmd
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	headerRequestID = "X-Request-ID"
+	requestIDPrefix = "slim-probe"
+)
+
+// newRequestID creates the probe call request ID.
+// The random IDs use the "slim-probe-<uuid>" format.
+// The deterministic IDs use the "slim-probe-<target>-<command>-<attempt>" format
+// where 'target' is the index of the probed address/port pair,
+// 'command' is the index of the probe command and 'attempt' is the call attempt (starting with 1),
+// so the same probe configuration produces the same IDs on every run.
+func newRequestID(mode string, targetIdx, cmdIdx, attempt int) string {
+	switch mode {
+	case config.RequestIDModeRandom:
+		return fmt.Sprintf("%s-%s", requestIDPrefix, uuid.New().String())
+	case config.RequestIDModeDeterministic:
+		return fmt.Sprintf("%s-%d-%d-%d", requestIDPrefix, targetIdx, cmdIdx, attempt)
+	default:
+		return ""
+	}
+}
+
+// setRequestID adds the request ID header to the probe call request
+// (unless the probe command already sets the request ID header)
+func (p *CustomProbe) setRequestID(
+	req *http.Request,
+	cmd config.HTTPProbeCmd,
+	targetIdx int,
+	cmdIdx int,
+	attempt int) string {
+	if hasHeader(cmd.Headers, headerRequestID) {
+		return req.Header.Get(headerRequestID)
+	}
+
+	requestID := newRequestID(p.opts.RequestIDMode, targetIdx, cmdIdx, attempt)
+	if requestID != "" {
+		req.Header.Set(headerRequestID, requestID)
+	}
+
+	return requestID
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// ResultAssertion is a post-run assertion over the aggregated probe result
+// (it returns false and a message explaining the failure if the assertion fails)
+type ResultAssertion func(result ProbeResult) (bool, string)
+
+// ResultAssertionResult is the outcome of a probe result assertion
+type ResultAssertionResult struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+type namedResultAssertion struct {
+	name      string
+	assertion ResultAssertion
+}
+
+// AssertOKPerPort checks that each probed port has at least one 2xx response
+func AssertOKPerPort(result ProbeResult) (bool, string) {
+	var failed []string
+	for _, port := range result.Ports {
+		if result.PortSuccess[port] == 0 {
+			failed = append(failed, port)
+		}
+	}
+
+	if len(failed) > 0 {
+		return false, fmt.Sprintf("no 2xx responses for ports: %s", strings.Join(failed, ","))
+	}
+
+	return true, fmt.Sprintf("2xx responses for all ports (%d)", len(result.Ports))
+}
+
+// AssertNoFailedCommands checks that all probe commands have a successful call
+func AssertNoFailedCommands(result ProbeResult) (bool, string) {
+	if result.FailedCommands > 0 {
+		return false, fmt.Sprintf("failed commands: %d (of %d)", result.FailedCommands, result.Commands)
+	}
+
+	return true, fmt.Sprintf("no failed commands (of %d)", result.Commands)
+}
+
+// AssertCmdOK returns an assertion checking that the named probe command succeeded
+// (e.g., the last step in a login flow)
+func AssertCmdOK(name string) ResultAssertion {
+	return func(result ProbeResult) (bool, string) {
+		ok, found := result.CommandOK[name]
+		switch {
+		case !found:
+			return false, fmt.Sprintf("command %s did not run", name)
+		case !ok:
+			return false, fmt.Sprintf("command %s failed", name)
+		default:
+			return true, fmt.Sprintf("command %s succeeded", name)
+		}
+	}
+}
+
+// AssertExpr returns an assertion evaluating the expression with the probe result metrics
+// (the variables are the severity rule metric names, e.g., 'failed_commands == 0 && calls > 3')
+func AssertExpr(expr string) ResultAssertion {
+	return func(result ProbeResult) (bool, string) {
+		lookup := func(name string) (string, bool) {
+			switch name {
+			case config.ProbeMetricCalls,
+				config.ProbeMetricFailures,
+				config.ProbeMetricFailureRate,
+				config.ProbeMetricSuccessRate,
+				config.ProbeMetricNotFoundRoutes,
+				config.ProbeMetricFailedCommands,
+				config.ProbeMetricFailedCommandRate:
+				return strconv.FormatFloat(result.metric(name), 'f', -1, 64), true
+			default:
+				return "", false
+			}
+		}
+
+		val, err := evalExpr(expr, lookup)
+		if err != nil {
+			return false, fmt.Sprintf("expression error: %v", err)
+		}
+
+		ok, err := val.asBool()
+		if err != nil {
+			return false, fmt.Sprintf("expression error: %v", err)
+		}
+
+		if !ok {
+			return false, fmt.Sprintf("expression is false: %s", expr)
+		}
+
+		return true, fmt.Sprintf("expression is true: %s", expr)
+	}
+}
+
+// builtinResultAssertion returns the built-in probe result assertion
+func builtinResultAssertion(value string) (ResultAssertion, bool) {
+	switch {
+	case value == config.ProbeAssertOKPerPort:
+		return AssertOKPerPort, true
+	case value == config.ProbeAssertNoFailedCommands:
+		return AssertNoFailedCommands, true
+	case strings.HasPrefix(value, config.ProbeAssertCmdOKPrefix):
+		return AssertCmdOK(strings.TrimSpace(strings.TrimPrefix(value, config.ProbeAssertCmdOKPrefix))), true
+	case strings.HasPrefix(value, config.ProbeAssertExprPrefix):
+		return AssertExpr(strings.TrimSpace(strings.TrimPrefix(value, config.ProbeAssertExprPrefix))), true
+	default:
+		return nil, false
+	}
+}
+
+// AddResultAssertion adds a custom probe result assertion evaluated after the probe run
+// (call it before starting the probe)
+func (p *CustomProbe) AddResultAssertion(name string, assertion ResultAssertion) {
+	p.customAsserts = append(p.customAsserts, namedResultAssertion{name: name, assertion: assertion})
+}
+
+// checkResultAssertions evaluates the configured and the custom probe result assertions
+func (p *CustomProbe) checkResultAssertions() {
+	var assertions []namedResultAssertion
+	for _, value := range p.opts.ResultAssertions {
+		assertion, ok := builtinResultAssertion(value)
+		if !ok {
+			log.Debugf("HTTP probe - unknown result assertion - %s", value)
+			assertions = append(assertions, namedResultAssertion{
+				name: value,
+				assertion: func(ProbeResult) (bool, string) {
+					return false, "unknown assertion"
+				},
+			})
+			continue
+		}
+
+		assertions = append(assertions, namedResultAssertion{name: value, assertion: assertion})
+	}
+
+	assertions = append(assertions, p.customAsserts...)
+	if len(assertions) == 0 {
+		return
+	}
+
+	result := p.Result()
+	var results []ResultAssertionResult
+	for _, item := range assertions {
+		ok, msg := item.assertion(result)
+		results = append(results, ResultAssertionResult{
+			Name:    item.name,
+			OK:      ok,
+			Message: msg,
+		})
+	}
+
+	p.cmdResultsMu.Lock()
+	p.resultAsserts = results
+	p.cmdResultsMu.Unlock()
+}
+
+// ResultAssertions returns the probe result assertion outcomes (available after the probe run)
+func (p *CustomProbe) ResultAssertions() []ResultAssertionResult {
+	p.cmdResultsMu.Lock()
+	defer p.cmdResultsMu.Unlock()
+
+	return append([]ResultAssertionResult{}, p.resultAsserts...)
+}
+
+// ResultError returns the reason the probe result fails the command
+// (an 'error' severity or a failed result assertion, empty otherwise)
+func (p *CustomProbe) ResultError() string {
+	if p.Severity().Severity == config.ProbeSeverityError {
+		return "severity.error"
+	}
+
+	for _, result := range p.ResultAssertions() {
+		if !result.OK {
+			return "result.assert.failed"
+		}
+	}
+
+	return ""
+}
+
+func (p *CustomProbe) printResultAssertions() {
+	for _, result := range p.ResultAssertions() {
+		status := "ok"
+		if !result.OK {
+			status = "failed"
+		}
+
+		p.xc.Out.Info("http.probe.result.assert",
+			ovars{
+				"assertion": result.Name,
+				"status":    status,
+				"message":   result.Message,
+			})
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CallResult provides the outcome of an individual probe call
+type CallResult struct {
+	Time       time.Time     `json:"time"`
+	Port       string        `json:"port"`
+	CmdIndex   int           `json:"cmd_index"` //-1 for the calls not made by the probe commands
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	Target     string        `json:"target"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Proto      string        `json:"proto,omitempty"` //the response protocol (e.g., "HTTP/2.0")
+	Attempt    int           `json:"attempt"`
+	Duration   time.Duration `json:"duration"`
+	Error      string        `json:"error,omitempty"`
+	RequestID  string        `json:"request_id,omitempty"`
+	//the fallback credential set used for the call
+	Credential string `json:"credential,omitempty"`
+	//Unix socket path (for the Unix socket command calls)
+	Socket string `json:"socket,omitempty"`
+	//the response body check outcome (for the commands with the expected body)
+	BodyCheck string `json:"body_check,omitempty"`
+	//the repeat call number (for the additional calls after the first successful call)
+	Repeat int `json:"repeat,omitempty"`
+}
+
+func (r *CallResult) Status() string {
+	if r.Error != "" && r.StatusCode == 0 {
+		return "error"
+	}
+
+	return fmt.Sprintf("%v", r.StatusCode)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func (p *CustomProbe) addCallResult(result CallResult) {
+	p.recordCallResult(result)
+	p.publish(ProgressEvent{Type: ProgressEventCall, Call: &result})
+}
+
+func (p *CustomProbe) recordCallResult(result CallResult) {
+	p.resultsMu.Lock()
+	p.results = append(p.results, result)
+	p.resultsMu.Unlock()
+
+	p.addCallEvent(result)
+}
+
+// CallResults returns the collected probe call results
+func (p *CustomProbe) CallResults() []CallResult {
+	p.resultsMu.Lock()
+	defer p.resultsMu.Unlock()
+
+	results := make([]CallResult, len(p.results))
+	copy(results, p.results)
+	return results
+}
+
+// comparePorts orders the port numbers numerically
+// (and the other port values as strings)
+func comparePorts(a, b string) bool {
+	an, aerr := strconv.Atoi(a)
+	bn, berr := strconv.Atoi(b)
+	if aerr == nil && berr == nil {
+		return an < bn
+	}
+
+	return a < b
+}
+
+// sortPorts sorts the port list (the ports collected from maps have a random order)
+func sortPorts(ports []string) []string {
+	sort.Slice(ports, func(i, j int) bool {
+		return comparePorts(ports[i], ports[j])
+	})
+
+	return ports
+}
+
+// sortedCallResults returns the probe call results in a stable order
+// (by port, command index, target, path, method and attempt),
+// so the reports for the same probe plan can be compared across runs.
+// The calls not made by the probe commands (e.g., the API spec calls) go after the command calls.
+func (p *CustomProbe) sortedCallResults() []CallResult {
+	results := p.CallResults()
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Port != b.Port {
+			return comparePorts(a.Port, b.Port)
+		}
+
+		if a.CmdIndex != b.CmdIndex {
+			if a.CmdIndex < 0 || b.CmdIndex < 0 {
+				return b.CmdIndex < 0
+			}
+
+			return a.CmdIndex < b.CmdIndex
+		}
+
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+
+		if a.CmdIndex < 0 {
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+
+			if a.Method != b.Method {
+				return a.Method < b.Method
+			}
+		}
+
+		return a.Attempt < b.Attempt
+	})
+
+	return results
+}
+
+var csvOutputHeader = []string{
+	"timestamp",
+	"port",
+	"method",
+	"path",
+	"status",
+	"attempts",
+	"latency_ms",
+	"error",
+}
+
+func (p *CustomProbe) saveCSVOutput() {
+	if p.opts.CSVOutput == "" {
+		return
+	}
+
+	if err := writeCSVOutput(p.opts.CSVOutput, p.sortedCallResults()); err != nil {
+		log.Warnf("HTTP probe - error saving CSV output (%s) - %v", p.opts.CSVOutput, err)
+		return
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.csv.output",
+			ovars{
+				"file": p.opts.CSVOutput,
+			})
+	}
+}
+
+func writeCSVOutput(name string, results []CallResult) error {
+	if dir := filepath.Dir(name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	//closing the file on the error paths (the close error is returned when the records are written)
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(csvOutputHeader); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		record := []string{
+			r.Time.UTC().Format(time.RFC3339Nano),
+			r.Port,
+			r.Method,
+			r.Path,
+			r.Status(),
+			fmt.Sprintf("%d", r.Attempt),
+			fmt.Sprintf("%.3f", float64(r.Duration.Microseconds())/1000),
+			r.Error,
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const defaultCmdWeight = 1
+
+// retryBudget is the total number of retries shared by the probe commands.
+// Each command can use its share of the budget (based on the command weight),
+// so the commands with a larger weight get more retries.
+type retryBudget struct {
+	mu        sync.Mutex
+	remaining int
+	weights   []int
+	limits    []int
+	used      []int
+}
+
+func cmdWeight(cmd config.HTTPProbeCmd) int {
+	if cmd.Weight > 0 {
+		return cmd.Weight
+	}
+
+	return defaultCmdWeight
+}
+
+// newRetryBudget creates the retry budget for the probe commands
+// (returns nil if the budget is not set)
+func newRetryBudget(total int, cmds []config.HTTPProbeCmd) *retryBudget {
+	if total <= 0 || len(cmds) == 0 {
+		return nil
+	}
+
+	b := &retryBudget{
+		remaining: total,
+		weights:   make([]int, len(cmds)),
+		limits:    make([]int, len(cmds)),
+		used:      make([]int, len(cmds)),
+	}
+
+	var totalWeight int
+	for idx, cmd := range cmds {
+		b.weights[idx] = cmdWeight(cmd)
+		totalWeight += b.weights[idx]
+	}
+
+	for idx := range cmds {
+		b.limits[idx] = total * b.weights[idx] / totalWeight
+		if b.limits[idx] == 0 {
+			//each command gets at least one retry (while the budget lasts)
+			b.limits[idx] = 1
+		}
+	}
+
+	return b
+}
+
+// take uses one retry from the command share of the budget
+// (returns false if the command share or the total budget is used up)
+func (b *retryBudget) take(cmdIdx int) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining <= 0 || b.used[cmdIdx] >= b.limits[cmdIdx] {
+		return false
+	}
+
+	b.remaining--
+	b.used[cmdIdx]++
+	return true
+}
+
+// takeRetry checks the retry budget before retrying a probe command call
+func (p *CustomProbe) takeRetry(cmdIdx int, cmd config.HTTPProbeCmd) bool {
+	if p.retryBudget.take(cmdIdx) {
+		return true
+	}
+
+	log.Debugf("HTTP probe - retry budget used up (%s %s)", cmd.Method, cmd.Resource)
+	return false
+}
+
+func (p *CustomProbe) printRetryBudget() {
+	b := p.retryBudget
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for idx, cmd := range p.opts.Cmds {
+		p.xc.Out.Info("http.probe.retry.budget.command",
+			ovars{
+				"method":   cmd.Method,
+				"resource": cmd.Resource,
+				"weight":   b.weights[idx],
+				"limit":    b.limits[idx],
+				"used":     b.used[idx],
+			})
+	}
+
+	p.xc.Out.Info("http.probe.retry.budget",
+		ovars{
+			"total":     p.opts.TotalRetryBudget,
+			"remaining": b.remaining,
+		})
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	routeParamPlaceholder    = "1"
+	routeWildcardPlaceholder = "slim"
+
+	maxRoutesResponseSize = 4 * 1024 * 1024
+)
+
+var ErrNoRoutes = errors.New("no routes found")
+
+// RouteInfo is an app route from its route table
+type RouteInfo struct {
+	Method string
+	Path   string
+}
+
+// RoutesParser extracts the app routes from the route table endpoint response data
+type RoutesParser func(data []byte) ([]RouteInfo, error)
+
+type routesParserInfo struct {
+	name  string
+	parse RoutesParser
+}
+
+// the parsers are tried in order (the first parser that finds routes wins)
+var routesParsers = []routesParserInfo{
+	{name: "json", parse: parseJSONRoutes},
+	{name: "text", parse: parseTextRoutes},
+}
+
+// RegisterRoutesParser adds a parser for an additional route table format
+// (the custom parsers are tried before the built-in parsers)
+func RegisterRoutesParser(name string, parser RoutesParser) {
+	routesParsers = append([]routesParserInfo{{name: name, parse: parser}}, routesParsers...)
+}
+
+func parseRoutes(data []byte) ([]RouteInfo, string, error) {
+	for _, parser := range routesParsers {
+		routes, err := parser.parse(data)
+		if err != nil {
+			log.Debugf("HTTP probe - routes parser '%s' error - %v", parser.name, err)
+			continue
+		}
+
+		if len(routes) > 0 {
+			return routes, parser.name, nil
+		}
+	}
+
+	return nil, "", ErrNoRoutes
+}
+
+var (
+	routeMethodKeys = []string{"method", "methods", "verb", "verbs", "httpmethod"}
+	routePathKeys   = []string{"path", "route", "uri", "pattern", "url", "template"}
+)
+
+func routeField(record map[string]interface{}, keys []string) interface{} {
+	for name, val := range record {
+		for _, key := range keys {
+			if strings.EqualFold(name, key) {
+				return val
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseJSONRoutes parses a JSON list of route objects
+// (e.g., [{"method":"GET","path":"/users/:id"}]) or an object with such list
+func parseJSONRoutes(data []byte) ([]RouteInfo, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var records []interface{}
+	switch val := raw.(type) {
+	case []interface{}:
+		records = val
+	case map[string]interface{}:
+		for _, field := range val {
+			if list, ok := field.([]interface{}); ok {
+				records = append(records, list...)
+			}
+		}
+	}
+
+	var routes []RouteInfo
+	for _, rec := range records {
+		record, ok := rec.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path, _ := routeField(record, routePathKeys).(string)
+		if path == "" {
+			continue
+		}
+
+		var methods []string
+		switch val := routeField(record, routeMethodKeys).(type) {
+		case string:
+			methods = splitRouteMethods(val)
+		case []interface{}:
+			for _, m := range val {
+				if ms, ok := m.(string); ok {
+					methods = append(methods, splitRouteMethods(ms)...)
+				}
+			}
+		}
+
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+
+		for _, method := range methods {
+			routes = append(routes, RouteInfo{Method: method, Path: path})
+		}
+	}
+
+	return routes, nil
+}
+
+// parseTextRoutes parses the route table text output with one route per line
+// (e.g., "GET /users/:id" or the Rails "users GET /users(.:format) users#index" format)
+func parseTextRoutes(data []byte) ([]RouteInfo, error) {
+	var routes []RouteInfo
+	for _, line := range strings.Split(string(data), "\n") {
+		var methods []string
+		for _, token := range strings.Fields(line) {
+			if strings.HasPrefix(token, "/") {
+				if len(methods) == 0 {
+					methods = []string{http.MethodGet}
+				}
+
+				for _, method := range methods {
+					routes = append(routes, RouteInfo{Method: method, Path: token})
+				}
+
+				break
+			}
+
+			if ms := splitRouteMethods(token); isRouteMethodList(ms) {
+				methods = ms
+			}
+		}
+	}
+
+	return routes, nil
+}
+
+func splitRouteMethods(value string) []string {
+	var methods []string
+	for _, method := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == '|' || r == ',' || r == ' '
+	}) {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method == "ANY" || method == "*" {
+			method = http.MethodGet
+		}
+
+		methods = append(methods, method)
+	}
+
+	return methods
+}
+
+func isRouteMethodList(methods []string) bool {
+	if len(methods) == 0 {
+		return false
+	}
+
+	for _, method := range methods {
+		if !isRouteMethod(method) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isRouteMethod(method string) bool {
+	switch method {
+	case http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+		http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSafeRouteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	//Rails optional segments (e.g., "(.:format)")
+	routeOptionalRE = regexp.MustCompile(`\([^)]*\)`)
+	//"{id}", "{id:[0-9]+}", "<id>", "<int:id>", ":id"
+	routeParamRE = regexp.MustCompile(`\{[^}]+\}|<[^>]+>|:[A-Za-z_][A-Za-z0-9_]*`)
+	//"*", "*path"
+	routeWildcardRE = regexp.MustCompile(`\*[A-Za-z0-9_]*`)
+)
+
+// routeProbePath fills in the route path params with placeholders
+func routeProbePath(path string) string {
+	path = routeOptionalRE.ReplaceAllString(path, "")
+	path = routeParamRE.ReplaceAllString(path, routeParamPlaceholder)
+	path = routeWildcardRE.ReplaceAllString(path, routeWildcardPlaceholder)
+	return path
+}
+
+func loadRoutesFromEndpoint(client *http.Client, addr string) ([]RouteInfo, string, error) {
+	res, err := client.Get(addr)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", &StatusError{StatusCode: res.StatusCode, Expected: []int{http.StatusOK}}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxRoutesResponseSize))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return parseRoutes(data)
+}
+
+// probeRoutes fetches the app route table and calls each of the routes
+func (p *CustomProbe) probeRoutes(proto, targetHost, port string) {
+	if p.opts.RoutesEndpoint == "" {
+		return
+	}
+
+	client, err := getHTTPClient(proto, p.clientOpts)
+	if err != nil {
+		p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
+		return
+	}
+
+	addr := getHTTPAddr(proto, targetHost, port)
+	routes, format, err := loadRoutesFromEndpoint(client, fmt.Sprintf("%s%s", addr, p.opts.RoutesEndpoint))
+	if err != nil {
+		p.xc.Out.Info("http.probe.routes.error",
+			ovars{
+				"message":  "error loading routes from endpoint",
+				"endpoint": p.opts.RoutesEndpoint,
+				"error":    err,
+			})
+		return
+	}
+
+	seen := map[string]struct{}{}
+	var selected []RouteInfo
+	var skipped int
+	for _, route := range routes {
+		if !isRouteMethod(route.Method) {
+			log.Debugf("HTTP probe - skipping route with unsupported method => %s %s", route.Method, route.Path)
+			skipped++
+			continue
+		}
+
+		if !isSafeRouteMethod(route.Method) && !p.opts.RoutesDestructive {
+			log.Debugf("HTTP probe - skipping destructive route => %s %s", route.Method, route.Path)
+			skipped++
+			continue
+		}
+
+		route.Path = routeProbePath(route.Path)
+		key := route.Method + " " + route.Path
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		selected = append(selected, route)
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.routes",
+			ovars{
+				"endpoint": p.opts.RoutesEndpoint,
+				"format":   format,
+				"routes":   len(selected),
+				"skipped":  skipped,
+			})
+	}
+
+	for _, route := range selected {
+		p.apiSpecEndpointCall(client, port, fmt.Sprintf("%s%s", addr, route.Path), route.Method)
+	}
+}
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+var (
+	ErrCmdDependencyCycle   = errors.New("probe command dependency cycle")
+	ErrUnknownCmdDependency = errors.New("unknown probe command dependency")
+	ErrDuplicateCmdName     = errors.New("duplicate probe command name")
+)
+
+// cmdDep is a probe command dependency
+type cmdDep struct {
+	name     string
+	idx      int  //-1 if the command is not in the command list
+	required bool //false if the command only needs to wait for the dependency (group teardown)
+}
+
+// cmdDependencies returns the explicit ('depends_on'), the group and the variable dependencies for each command.
+// The group commands depend on the group setup command
+// and the group teardown command waits for all other group commands (their results don't matter).
+// The commands referencing the captured variables wait for the earlier commands capturing them
+// (the commands still run if the capture fails, like in the sequential probing).
+func cmdDependencies(cmds []config.HTTPProbeCmd) [][]cmdDep {
+	names := map[string]int{}
+	setups := map[string]int{}
+	captures := map[string]int{}
+	deps := make([][]cmdDep, len(cmds))
+	for idx, cmd := range cmds {
+		added := map[int]bool{}
+		for _, name := range cmdVarRefs(cmd) {
+			if capIdx, ok := captures[name]; ok && !added[capIdx] {
+				added[capIdx] = true
+				deps[idx] = append(deps[idx], cmdDep{name: cmdDepName(cmds[capIdx]), idx: capIdx})
+			}
+		}
+
+		for name := range cmd.Capture {
+			captures[name] = idx
+		}
+	}
+
+	for idx, cmd := range cmds {
+		if cmd.Name != "" {
+			names[cmd.Name] = idx
+		}
+
+		if cmd.Group != "" && cmd.GroupStage == config.CmdGroupStageSetup {
+			setups[cmd.Group] = idx
+		}
+	}
+
+	for idx, cmd := range cmds {
+		for _, dep := range cmd.DependsOn {
+			depIdx, ok := names[dep]
+			if !ok {
+				depIdx = -1
+			}
+
+			deps[idx] = append(deps[idx], cmdDep{name: dep, idx: depIdx, required: true})
+		}
+
+		if cmd.Group == "" {
+			continue
+		}
+
+		switch cmd.GroupStage {
+		case config.CmdGroupStageSetup:
+		case config.CmdGroupStageTeardown:
+			for otherIdx, other := range cmds {
+				if otherIdx != idx && other.Group == cmd.Group {
+					deps[idx] = append(deps[idx], cmdDep{name: cmdDepName(other), idx: otherIdx})
+				}
+			}
+		default:
+			if setupIdx, ok := setups[cmd.Group]; ok {
+				deps[idx] = append(deps[idx], cmdDep{name: cmdDepName(cmds[setupIdx]), idx: setupIdx, required: true})
+			}
+		}
+	}
+
+	return deps
+}
+
+func cmdDepName(cmd config.HTTPProbeCmd) string {
+	if cmd.Name != "" {
+		return cmd.Name
+	}
+
+	if cmd.GroupStage != "" {
+		return cmd.Group + "/" + cmd.GroupStage
+	}
+
+	return fmt.Sprintf("%s %s", cmd.Method, cmd.Resource)
+}
+
+// orderProbeCmds sorts the probe commands, so the commands run after their dependencies
+// (the original order is preserved for the independent commands)
+func orderProbeCmds(cmds []config.HTTPProbeCmd) ([]config.HTTPProbeCmd, error) {
+	names := map[string]bool{}
+	for _, cmd := range cmds {
+		if cmd.Name == "" {
+			continue
+		}
+
+		if names[cmd.Name] {
+			return nil, fmt.Errorf("%w - '%s'", ErrDuplicateCmdName, cmd.Name)
+		}
+
+		names[cmd.Name] = true
+	}
+
+	pending := make([]int, len(cmds))
+	dependents := make([][]int, len(cmds))
+	for idx, deps := range cmdDependencies(cmds) {
+		for _, dep := range deps {
+			if dep.idx == -1 {
+				return nil, fmt.Errorf("%w - '%s' (command: %s %s)",
+					ErrUnknownCmdDependency, dep.name, cmds[idx].Method, cmds[idx].Resource)
+			}
+
+			pending[idx]++
+			dependents[dep.idx] = append(dependents[dep.idx], idx)
+		}
+	}
+
+	//Kahn's algorithm always picking the first ready command
+	ordered := make([]config.HTTPProbeCmd, 0, len(cmds))
+	added := make([]bool, len(cmds))
+	for len(ordered) < len(cmds) {
+		next := -1
+		for idx := range cmds {
+			if !added[idx] && pending[idx] == 0 {
+				next = idx
+				break
+			}
+		}
+
+		if next == -1 {
+			var cycle []string
+			for idx, cmd := range cmds {
+				if !added[idx] {
+					cycle = append(cycle, cmdDepName(cmd))
+				}
+			}
+
+			return nil, fmt.Errorf("%w - %v", ErrCmdDependencyCycle, cycle)
+		}
+
+		added[next] = true
+		ordered = append(ordered, cmds[next])
+		for _, idx := range dependents[next] {
+			pending[idx]--
+		}
+	}
+
+	return ordered, nil
+}
+
+// cmdStates tracks the probe command results for the dependent commands
+type cmdStates struct {
+	mu   sync.Mutex
+	deps [][]cmdDep
+	ok   []bool
+	done []chan struct{}
+}
+
+func newCmdStates(cmds []config.HTTPProbeCmd) *cmdStates {
+	states := &cmdStates{
+		deps: cmdDependencies(cmds),
+		ok:   make([]bool, len(cmds)),
+		done: make([]chan struct{}, len(cmds)),
+	}
+
+	for idx := range cmds {
+		states.done[idx] = make(chan struct{})
+	}
+
+	return states
+}
+
+func (s *cmdStates) set(idx int, ok bool) {
+	s.mu.Lock()
+	s.ok[idx] = ok
+	s.mu.Unlock()
+	close(s.done[idx])
+}
+
+// wait waits for the command dependencies
+// and returns the first failed (or not executed) required dependency
+func (s *cmdStates) wait(idx int) string {
+	var failed string
+	for _, dep := range s.deps[idx] {
+		if dep.idx == -1 {
+			//the dependency is not in the command list (e.g., skipped because of its platform conditions)
+			if failed == "" {
+				failed = dep.name
+			}
+			continue
+		}
+
+		<-s.done[dep.idx]
+
+		s.mu.Lock()
+		depOK := s.ok[dep.idx]
+		s.mu.Unlock()
+		if !depOK && dep.required && failed == "" {
+			failed = dep.name
+		}
+	}
+
+	return failed
+}
+
+func (p *CustomProbe) skipDependentCmd(cmd config.HTTPProbeCmd, dep string) {
+	log.Debugf("HTTP probe - skipping command (failed dependency '%s') => %s %s", dep, cmd.Method, cmd.Resource)
+
+	p.skippedMu.Lock()
+	p.skippedCmds = append(p.skippedCmds, SkippedCmd{
+		Cmd:    cmd,
+		Reason: "dependency (" + dep + ")",
+	})
+	p.skippedMu.Unlock()
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.command.skipped",
+			ovars{
+				"method":   cmd.Method,
+				"resource": cmd.Resource,
+				"reason":   "dependency (" + dep + ")",
+			})
+	}
+}
+
+// probeCmds runs the probe commands for the target address and port.
+// The commands run after their dependencies and the commands with failed dependencies are skipped
+// (the group teardown commands run even if the other group commands fail).
+// The independent commands run in parallel when the probe concurrency is greater than one
+// (the worker pool is shared with the other probe targets).
+// Only the commands for the target Unix socket run for the socket targets
+// and the Unix socket commands don't run for the host/port targets.
+func (p *CustomProbe) probeCmds(targetIdx int, target probeTarget) {
+	targetHost, port := target.host, target.port
+	states := newCmdStates(p.opts.Cmds)
+
+	if p.opts.Concurrency <= 1 {
+		for cmdIdx, cmd := range p.opts.Cmds {
+			if p.stopped() {
+				return
+			}
+
+			if cmdUnixSocket(cmd) != target.socket || !p.cmdTargetPort(cmd, target) {
+				states.set(cmdIdx, false)
+				continue
+			}
+
+			if dep := states.wait(cmdIdx); dep != "" {
+				p.skipDependentCmd(cmd, dep)
+				p.groupStageDone(cmd, targetHost, port, groupStageSkipped)
+				states.set(cmdIdx, false)
+				continue
+			}
+
+			p.gauges.queue(targetHost)
+			p.gauges.start(targetHost)
+			ok := p.probeCmd(cmdIdx, cmd, targetIdx, targetHost, port)
+			p.gauges.done(targetHost)
+
+			p.countCmd(cmd, ok)
+			p.groupStageDone(cmd, targetHost, port, groupStageStatus(ok))
+			states.set(cmdIdx, ok)
+		}
+
+		return
+	}
+
+	workers := p.cmdWorkers
+	var wg sync.WaitGroup
+	for cmdIdx, cmd := range p.opts.Cmds {
+		if cmdUnixSocket(cmd) != target.socket || !p.cmdTargetPort(cmd, target) {
+			states.set(cmdIdx, false)
+			continue
+		}
+
+		wg.Add(1)
+		go func(cmdIdx int, cmd config.HTTPProbeCmd) {
+			defer wg.Done()
+			if dep := states.wait(cmdIdx); dep != "" {
+				p.skipDependentCmd(cmd, dep)
+				p.groupStageDone(cmd, targetHost, port, groupStageSkipped)
+				states.set(cmdIdx, false)
+				return
+			}
+
+			p.gauges.queue(targetHost)
+			workers <- struct{}{}
+			p.gauges.start(targetHost)
+			if p.stopped() {
+				<-workers
+				p.gauges.done(targetHost)
+				states.set(cmdIdx, false)
+				return
+			}
+
+			ok := p.probeCmd(cmdIdx, cmd, targetIdx, targetHost, port)
+			<-workers
+			p.gauges.done(targetHost)
+
+			p.countCmd(cmd, ok)
+			p.groupStageDone(cmd, targetHost, port, groupStageStatus(ok))
+			states.set(cmdIdx, ok)
+		}(cmdIdx, cmd)
+	}
+
+	wg.Wait()
+}
+
+// probeTargetsParallel probes all target ports in parallel (used when the probe concurrency is greater than one).
+// The target commands share the probe worker pool, so the concurrency limit applies to all probe calls.
+// All targets are probed because the targets don't wait for each other
+// (there's no 'first successful port' shortcut like in the sequential probing).
+func (p *CustomProbe) probeTargetsParallel() {
+	var wg sync.WaitGroup
+	for targetIdx, target := range p.probeTargets() {
+		wg.Add(1)
+		go func(targetIdx int, target probeTarget) {
+			defer wg.Done()
+			if p.stopped() || !p.targetReady(target) {
+				return
+			}
+
+			p.probeCmds(targetIdx, target)
+		}(targetIdx, target)
+	}
+
+	wg.Wait()
+}
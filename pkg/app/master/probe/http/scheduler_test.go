@@ -0,0 +1,210 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+func TestOrderProbeCmds(t *testing.T) {
+	tt := []struct {
+		name     string
+		cmds     []config.HTTPProbeCmd
+		expected []string
+		err      error
+	}{
+		{
+			name: "independent commands",
+			cmds: []config.HTTPProbeCmd{
+				{Resource: "/a"},
+				{Resource: "/b"},
+			},
+			expected: []string{"/a", "/b"},
+		},
+		{
+			name: "explicit dependencies",
+			cmds: []config.HTTPProbeCmd{
+				{Resource: "/orders", DependsOn: []string{"login", "users"}},
+				{Resource: "/health"},
+				{Name: "users", Resource: "/users", DependsOn: []string{"login"}},
+				{Name: "login", Resource: "/login"},
+			},
+			expected: []string{"/health", "/login", "/users", "/orders"},
+		},
+		{
+			name: "variable dependencies",
+			cmds: []config.HTTPProbeCmd{
+				{Resource: "/login", Capture: map[string]string{"token": "json:token"}},
+				{Resource: "/health"},
+				{Resource: "/items", Headers: []string{"Authorization: Bearer ${token}"}},
+			},
+			expected: []string{"/login", "/health", "/items"},
+		},
+		{
+			name: "group dependencies",
+			cmds: []config.HTTPProbeCmd{
+				{Resource: "/teardown", Group: "g", GroupStage: config.CmdGroupStageTeardown},
+				{Resource: "/step", Group: "g"},
+				{Resource: "/setup", Group: "g", GroupStage: config.CmdGroupStageSetup},
+			},
+			expected: []string{"/setup", "/step", "/teardown"},
+		},
+		{
+			name: "dependency cycle",
+			cmds: []config.HTTPProbeCmd{
+				{Resource: "/health"},
+				{Name: "a", Resource: "/a", DependsOn: []string{"c"}},
+				{Name: "b", Resource: "/b", DependsOn: []string{"a"}},
+				{Name: "c", Resource: "/c", DependsOn: []string{"b"}},
+			},
+			err: ErrCmdDependencyCycle,
+		},
+		{
+			name: "self dependency",
+			cmds: []config.HTTPProbeCmd{
+				{Name: "a", Resource: "/a", DependsOn: []string{"a"}},
+			},
+			err: ErrCmdDependencyCycle,
+		},
+		{
+			name: "missing dependency",
+			cmds: []config.HTTPProbeCmd{
+				{Name: "a", Resource: "/a"},
+				{Resource: "/b", DependsOn: []string{"missing"}},
+			},
+			err: ErrUnknownCmdDependency,
+		},
+		{
+			name: "duplicate names",
+			cmds: []config.HTTPProbeCmd{
+				{Name: "a", Resource: "/a"},
+				{Name: "a", Resource: "/b"},
+			},
+			err: ErrDuplicateCmdName,
+		},
+	}
+
+	for _, test := range tt {
+		ordered, err := orderProbeCmds(test.cmds)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v expected %v", test.name, err, test.err)
+			continue
+		}
+
+		var resources []string
+		for _, cmd := range ordered {
+			resources = append(resources, cmd.Resource)
+		}
+
+		if strings.Join(resources, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: got order %q expected %q", test.name, resources, test.expected)
+		}
+	}
+}
+
+func TestCmdVarScopes(t *testing.T) {
+	p := &CustomProbe{}
+	p.setVar(cmdVarScope(preflightCmdIdx, 0), "api_key", "shared")
+	p.setVar(cmdVarScope(0, 0), "token", "target0")
+	p.setVar(cmdVarScope(0, 1), "token", "target1")
+	p.setVar(cmdVarScope(0, 1), "api_key", "override")
+
+	tt := []struct {
+		scope    int
+		value    string
+		expected string
+	}{
+		{scope: 0, value: "${token}/${api_key}", expected: "target0/shared"},
+		{scope: 1, value: "{{token}}/{{api_key}}", expected: "target1/override"},
+		{scope: 2, value: "${token}/${api_key}", expected: "${token}/shared"},
+		{scope: sharedVarScope, value: "${api_key}", expected: "shared"},
+	}
+
+	for _, test := range tt {
+		if value := p.expandVarRefs(test.scope, test.value); value != test.expected {
+			t.Errorf("scope %d '%s': got '%s' expected '%s'", test.scope, test.value, value, test.expected)
+		}
+	}
+}
+
+// the dependent commands use the variables captured for their own probe target
+// (the targets are probed in parallel)
+func TestProbeCmdsConcurrentTargetVars(t *testing.T) {
+	//the '/sync' calls wait for each other, so both targets capture their tokens before the '/items' calls
+	var syncs int32
+	syncsDone := make(chan struct{})
+
+	var ports []uint
+	for i := 0; i < 2; i++ {
+		token := fmt.Sprintf("token-%d", i)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/login":
+				fmt.Fprintf(w, `{"token":%q}`, token)
+			case "/sync":
+				if atomic.AddInt32(&syncs, 1) == 2 {
+					close(syncsDone)
+				}
+
+				select {
+				case <-syncsDone:
+				case <-time.After(5 * time.Second):
+				}
+			case "/items":
+				if r.Header.Get("Authorization") != "Bearer "+token {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				fmt.Fprint(w, "[]")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pnum, err := strconv.Atoi(port)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ports = append(ports, uint(pnum))
+	}
+
+	opts := config.HTTPProbeOptions{
+		Concurrency: 4,
+		Cmds: []config.HTTPProbeCmd{
+			{Name: "login", Method: "GET", Resource: "/login", Capture: map[string]string{"token": "json:token"}},
+			{Name: "sync", Method: "GET", Resource: "/sync", DependsOn: []string{"login"}},
+			{Method: "GET", Resource: "/items", Headers: []string{"Authorization: Bearer ${token}"}, DependsOn: []string{"sync"}, ExpectStatus: []int{http.StatusOK}},
+		},
+	}
+
+	xc := app.NewExecutionContext("probe", true, "text")
+	p, err := NewEndpointProbe(xc, "127.0.0.1", ports, opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Start()
+	<-p.DoneChan()
+
+	if p.ErrCount != 0 || p.OkCount != 6 {
+		t.Errorf("got ok/errors %d/%d expected 6/0 (the targets used the other target variables)", p.OkCount, p.ErrCount)
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const schemeDetectTimeout = 2 * time.Second
+
+// portSchemes holds the detected target port schemes (one detection for each target address and port)
+type portSchemes struct {
+	mu      sync.Mutex
+	schemes map[string]*portScheme
+}
+
+type portScheme struct {
+	once   sync.Once
+	scheme string
+}
+
+func (s *portSchemes) get(key string) *portScheme {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.schemes == nil {
+		s.schemes = map[string]*portScheme{}
+	}
+
+	ps, ok := s.schemes[key]
+	if !ok {
+		ps = &portScheme{}
+		s.schemes[key] = ps
+	}
+
+	return ps
+}
+
+// portScheme returns the scheme for the target port ('http' or 'https')
+// or an empty string if the port scheme is unknown (both schemes are probed then).
+// The scheme is detected once for each target address and port.
+func (p *CustomProbe) portScheme(targetHost, port string) string {
+	//the cassette calls are not real calls (and the recorded calls need to match the played back calls)
+	if p.clientOpts.cassette != nil {
+		return ""
+	}
+
+	//the proxied targets might not be reachable without the proxy
+	if p.clientOpts.proxied(targetHost, port) {
+		return ""
+	}
+
+	ps := p.schemes.get(net.JoinHostPort(targetHost, port))
+	ps.once.Do(func() {
+		ps.scheme = p.detectScheme(targetHost, port)
+		log.Debugf("HTTP probe - port scheme (%s:%s) => '%s'", targetHost, port, ps.scheme)
+
+		if p.printState && ps.scheme != "" {
+			p.xc.Out.Info("http.probe.port.scheme",
+				ovars{
+					"host":   targetHost,
+					"port":   port,
+					"scheme": ps.scheme,
+				})
+		}
+	})
+
+	return ps.scheme
+}
+
+// detectScheme tries a TLS handshake with the target port:
+// a successful handshake (or a TLS alert from the server) means https
+// and a non-TLS response means http (e.g., '400 Bad Request' from an HTTP server)
+func (p *CustomProbe) detectScheme(targetHost, port string) string {
+	ctx, cancel := context.WithTimeout(p.ctx, schemeDetectTimeout)
+	defer cancel()
+
+	conn, err := p.clientOpts.dialer.DialContext(ctx, "tcp", net.JoinHostPort(targetHost, port))
+	if err != nil {
+		log.Debugf("HTTP probe - port scheme detection error (%s:%s) - %v", targetHost, port, err)
+		return ""
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	serverName := p.clientOpts.tlsServerName
+	if serverName == "" && net.ParseIP(targetHost) == nil {
+		serverName = targetHost
+	}
+
+	tconn := tls.Client(conn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+
+	err = tconn.HandshakeContext(ctx)
+	var recordErr tls.RecordHeaderError
+	switch {
+	case err == nil:
+		return config.ProtoHTTPS
+	case errors.As(err, &recordErr):
+		return config.ProtoHTTP
+	case strings.Contains(err.Error(), "remote error: tls:"):
+		//the server speaks TLS, but it rejected the handshake (e.g., it requires a client certificate)
+		return config.ProtoHTTPS
+	default:
+		log.Debugf("HTTP probe - port scheme detection handshake error (%s:%s) - %v", targetHost, port, err)
+		return ""
+	}
+}
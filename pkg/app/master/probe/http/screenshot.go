@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	screenshotTimeout    = 30 * time.Second
+	screenshotWindowSize = "1280,1024"
+)
+
+// the headless browser executables (Chrome or Chromium) looked up when the browser is not configured
+var screenshotBrowsers = []string{
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"headless-shell",
+}
+
+var screenshotNameRE = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// findScreenshotBrowser returns the headless browser executable path
+// (the browser is only needed when the failure screenshots are enabled)
+func findScreenshotBrowser(browser string) (string, error) {
+	if browser != "" {
+		return exec.LookPath(browser)
+	}
+
+	for _, name := range screenshotBrowsers {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no headless browser found (%s)", strings.Join(screenshotBrowsers, ", "))
+}
+
+// needsScreenshot returns true if the page call failed with a response
+// (the screenshots are taken only for the page loads, i.e., the GET calls)
+func needsScreenshot(cmd config.HTTPProbeCmd, res *http.Response, err error) bool {
+	if res == nil || cmd.Method != http.MethodGet {
+		return false
+	}
+
+	return err != nil || res.StatusCode >= http.StatusBadRequest
+}
+
+func screenshotFileName(idx uint64, addr string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(addr, "http://"), "https://")
+	name = strings.Trim(screenshotNameRE.ReplaceAllString(name, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	return fmt.Sprintf("%03d-%s.png", idx, name)
+}
+
+// captureScreenshot renders the failed page with the headless browser and saves its screenshot
+func (p *CustomProbe) captureScreenshot(addr string) {
+	browser, err := findScreenshotBrowser(p.opts.ScreenshotBrowser)
+	if err != nil {
+		log.Warnf("HTTP probe - screenshot browser error - %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(p.opts.ScreenshotOnFailure, 0755); err != nil {
+		log.Warnf("HTTP probe - screenshot directory error - %v", err)
+		return
+	}
+
+	idx := atomic.AddUint64(&p.screenshotCount, 1)
+	fileName := filepath.Join(p.opts.ScreenshotOnFailure, screenshotFileName(idx, addr))
+
+	ctx, cancel := context.WithTimeout(p.ctx, screenshotTimeout)
+	defer cancel()
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		//the probe targets use self-signed certificates and the browser may run as root in containers
+		"--no-sandbox",
+		"--ignore-certificate-errors",
+		"--hide-scrollbars",
+		"--window-size=" + screenshotWindowSize,
+		"--screenshot=" + fileName,
+		addr,
+	}
+
+	log.Debugf("HTTP probe - screenshot => %s %s", browser, strings.Join(args, " "))
+	output, err := exec.CommandContext(ctx, browser, args...).CombinedOutput()
+	if err == nil {
+		if _, err = os.Stat(fileName); err != nil {
+			err = fmt.Errorf("no screenshot file (%v)", err)
+		}
+	}
+
+	if err != nil {
+		log.Warnf("HTTP probe - screenshot error (%s) - %v (output: %s)", addr, err, string(output))
+		return
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.screenshot",
+			ovars{
+				"target": addr,
+				"file":   fileName,
+			})
+	}
+}
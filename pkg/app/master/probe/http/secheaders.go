@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the curated set of security headers for the 'security-headers' probe mode
+var defaultSecurityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Content-Type-Options",
+	"X-Frame-Options",
+	"Referrer-Policy",
+}
+
+var ErrMissingSecurityHeaders = errors.New("missing security headers")
+
+func securityHeaderNames(custom []string) []string {
+	names := append([]string{}, defaultSecurityHeaders...)
+	for _, name := range custom {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		known := false
+		for _, existing := range names {
+			if existing == name {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// checkSecurityHeaders returns the present and the missing security headers
+func checkSecurityHeaders(header http.Header, custom []string) (present, missing []string) {
+	for _, name := range securityHeaderNames(custom) {
+		if header.Get(name) != "" {
+			present = append(present, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	return present, missing
+}
+
+// securityHeadersCheck reports the security headers in the response
+// and returns an error for the missing headers (unless the command only warns)
+func (p *CustomProbe) securityHeadersCheck(cmd config.HTTPProbeCmd, addr string, header http.Header) error {
+	present, missing := checkSecurityHeaders(header, cmd.SecurityHeaders)
+
+	status := "ok"
+	switch {
+	case len(missing) > 0 && cmd.SecurityHeadersWarn:
+		status = "warning"
+	case len(missing) > 0:
+		status = "failed"
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.call.security.headers",
+			ovars{
+				"status":  status,
+				"method":  cmd.Method,
+				"target":  addr,
+				"present": strings.Join(present, ","),
+				"missing": strings.Join(missing, ","),
+				"time":    time.Now().UTC().Format(time.RFC3339),
+			})
+	}
+
+	if status == "failed" {
+		return fmt.Errorf("%w (%s)", ErrMissingSecurityHeaders, strings.Join(missing, ","))
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	secretSourceEnv  = "env"
+	secretSourceFile = "file"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secret references (e.g., '${env:API_TOKEN}' or '${file:/run/secrets/api_token}')
+// resolved when the probe requests are created, so the secrets are not stored in the probe config
+var secretRefRE = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// readSecretFile reads the secret file (without the trailing newline)
+func readSecretFile(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("%w (file=%s): %v", ErrSecretNotFound, name, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveSecretRefs replaces the secret references with the environment variable values
+// or the secret file data (the errors include the secret names, but not the secret values)
+func resolveSecretRefs(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var resolveErr error
+	value = secretRefRE.ReplaceAllStringFunc(value, func(ref string) string {
+		match := secretRefRE.FindStringSubmatch(ref)
+		source, name := match[1], strings.TrimSpace(match[2])
+
+		switch source {
+		case secretSourceEnv:
+			if val, ok := os.LookupEnv(name); ok {
+				return val
+			}
+
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("%w (env=%s)", ErrSecretNotFound, name)
+			}
+		case secretSourceFile:
+			val, err := readSecretFile(name)
+			if err == nil {
+				return val
+			}
+
+			if resolveErr == nil {
+				resolveErr = err
+			}
+		}
+
+		return ""
+	})
+
+	return value, resolveErr
+}
+
+// cmdBasicAuth returns the resolved basic auth credentials for the command
+// (the password file is used if the command doesn't have the password)
+func cmdBasicAuth(cmd config.HTTPProbeCmd) (username, password string, err error) {
+	if username, err = resolveSecretRefs(cmd.Username); err != nil {
+		return "", "", err
+	}
+
+	if cmd.Password == "" && cmd.PasswordFile != "" {
+		password, err = readSecretFile(cmd.PasswordFile)
+		return username, password, err
+	}
+
+	password, err = resolveSecretRefs(cmd.Password)
+	return username, password, err
+}
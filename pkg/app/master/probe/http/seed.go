@@ -0,0 +1,184 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	seedTimeout       = 5 * time.Minute
+	seedOutputMaxSize = 512
+	seedRetryWait     = 8 * time.Second
+
+	envSeedTargetHost  = "DSLIM_HTTP_PROBE_TARGET_HOST"
+	envSeedTargetPorts = "DSLIM_HTTP_PROBE_TARGET_PORTS"
+)
+
+// seedOutputTail returns the end of the seed command output (for the error reports)
+func seedOutputTail(output []byte) string {
+	output = bytes.TrimSpace(output)
+	if len(output) > seedOutputMaxSize {
+		output = output[len(output)-seedOutputMaxSize:]
+	}
+
+	return string(output)
+}
+
+// seedExec runs the seed command on the host (e.g., to load an SQL file into the database container)
+// The command gets the probe target host and ports in its environment.
+func (p *CustomProbe) seedExec(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.opts.SeedExec)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", envSeedTargetHost, p.targetHost),
+		fmt.Sprintf("%s=%s", envSeedTargetPorts, strings.Join(p.ports, ",")))
+
+	output, err := cmd.CombinedOutput()
+	log.Debugf("HTTP probe - seed exec output: %s", string(output))
+	if err != nil {
+		if tail := seedOutputTail(output); tail != "" {
+			return fmt.Errorf("%v (output: %s)", err, tail)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// seedEndpointAddr returns the seed endpoint URL
+// (the target path is called on the first probe target port)
+func (p *CustomProbe) seedEndpointAddr(resource string) (string, error) {
+	if !strings.HasPrefix(resource, "/") {
+		return resource, nil
+	}
+
+	var targets []probeTarget
+	for _, target := range p.probeTargets() {
+		if target.socket == "" {
+			targets = append(targets, target)
+		}
+	}
+
+	if len(targets) == 0 {
+		return "", fmt.Errorf("no probe targets for the seed endpoint")
+	}
+
+	proto := config.ProtoHTTP
+	if targets[0].port == defaultHTTPSPortStr {
+		proto = config.ProtoHTTPS
+	}
+
+	return getHTTPAddr(proto, targets[0].host, targets[0].port) + resource, nil
+}
+
+// seedEndpoint calls the app seed endpoint (expecting a 2xx response)
+// The call is retried while the target is not ready (no response).
+func (p *CustomProbe) seedEndpoint(ctx context.Context) error {
+	method, resource, err := config.ParseProbeSeedEndpoint(p.opts.SeedEndpoint)
+	if err != nil {
+		return err
+	}
+
+	addr, err := p.seedEndpointAddr(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := getHTTPClient(config.ProtoHTTP, p.clientOpts)
+	if err != nil {
+		return err
+	}
+
+	retryCount := probeRetryCount
+	if p.opts.RetryCount > 0 {
+		retryCount = p.opts.RetryCount
+	}
+
+	retryWait := seedRetryWait
+	if p.opts.RetryWait > 0 {
+		retryWait = time.Duration(p.opts.RetryWait) * time.Second
+	}
+
+	for i := 0; ; i++ {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, method, addr, nil)
+		if err != nil {
+			return err
+		}
+
+		setDefaultHeaders(req.Header, p.defaultHeaders)
+
+		var res *http.Response
+		p.waitCallRate()
+		res, err = client.Do(req)
+		if err == nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+
+			if res.StatusCode < 200 || res.StatusCode > 299 {
+				return fmt.Errorf("seed endpoint (%s %s) status: %d", method, addr, res.StatusCode)
+			}
+
+			return nil
+		}
+
+		log.Debugf("HTTP probe - seed endpoint call error (%s %s) - %v", method, addr, err)
+		if i+1 >= retryCount || p.stopped() {
+			return err
+		}
+
+		p.sleep(retryWait)
+	}
+}
+
+// seed runs the seeding step before the probe commands
+// and reports the result (the probe commands run even if the seeding fails)
+func (p *CustomProbe) seed() {
+	steps := []struct {
+		kind   string
+		target string
+		run    func(ctx context.Context) error
+	}{
+		{kind: "exec", target: p.opts.SeedExec, run: p.seedExec},
+		{kind: "endpoint", target: p.opts.SeedEndpoint, run: p.seedEndpoint},
+	}
+
+	for _, step := range steps {
+		if step.target == "" || p.stopped() {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(p.ctx, seedTimeout)
+		start := time.Now()
+		err := step.run(ctx)
+		cancel()
+
+		info := ovars{
+			"type":     step.kind,
+			"target":   step.target,
+			"status":   "ok",
+			"duration": time.Since(start).Round(time.Millisecond).String(),
+		}
+
+		if err != nil {
+			log.Debugf("HTTP probe - seed %s error - %v", step.kind, err)
+			info["status"] = "failed"
+			info["error"] = err.Error()
+		}
+
+		if p.printState || err != nil {
+			p.xc.Out.Info("http.probe.seed", info)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// ProbeResult provides the probe result metrics (used by the severity rules)
+type ProbeResult struct {
+	Calls          uint64
+	Failures       uint64
+	Successful     uint64
+	FailureRate    float64
+	SuccessRate    float64
+	NotFoundRoutes int
+	//the probe command results (the command is successful if one of its calls is successful)
+	Commands          uint64
+	FailedCommands    uint64
+	FailedCommandRate float64
+	//the probed ports and the number of the 2xx responses for each port
+	Ports       []string
+	PortSuccess map[string]int
+	//the named probe command results (true if the command has a successful call)
+	CommandOK map[string]bool
+}
+
+// SeverityResult is the probe result severity and the rule that triggered it
+// (no rule if no rule is triggered, which is the 'info' severity)
+type SeverityResult struct {
+	Severity string
+	Rule     *config.HTTPProbeSeverityRule
+	Value    float64
+}
+
+var severityLevels = map[string]int{
+	config.ProbeSeverityInfo:  0,
+	config.ProbeSeverityWarn:  1,
+	config.ProbeSeverityError: 2,
+}
+
+// Result returns the probe result metrics
+func (p *CustomProbe) Result() ProbeResult {
+	result := ProbeResult{
+		Calls:          atomic.LoadUint64(&p.CallCount),
+		Failures:       atomic.LoadUint64(&p.ErrCount),
+		Successful:     atomic.LoadUint64(&p.OkCount),
+		NotFoundRoutes: len(p.NotFoundRoutes()),
+		Commands:       atomic.LoadUint64(&p.cmdCount),
+		FailedCommands: atomic.LoadUint64(&p.cmdErrCount),
+		Ports:          append([]string{}, p.ports...),
+		PortSuccess:    map[string]int{},
+		CommandOK:      map[string]bool{},
+	}
+
+	for _, call := range p.CallResults() {
+		if call.StatusCode >= 200 && call.StatusCode <= 299 {
+			result.PortSuccess[call.Port]++
+		}
+	}
+
+	p.cmdResultsMu.Lock()
+	for name, ok := range p.cmdOK {
+		result.CommandOK[name] = ok
+	}
+	p.cmdResultsMu.Unlock()
+
+	if result.Calls > 0 {
+		result.FailureRate = float64(result.Failures) * 100 / float64(result.Calls)
+		result.SuccessRate = float64(result.Successful) * 100 / float64(result.Calls)
+	}
+
+	if result.Commands > 0 {
+		result.FailedCommandRate = float64(result.FailedCommands) * 100 / float64(result.Commands)
+	}
+
+	return result
+}
+
+// countCmd counts the probe command results (for each probed target)
+// and saves the named command results
+func (p *CustomProbe) countCmd(cmd config.HTTPProbeCmd, ok bool) {
+	atomic.AddUint64(&p.cmdCount, 1)
+	if !ok {
+		atomic.AddUint64(&p.cmdErrCount, 1)
+	}
+
+	p.countRequiredCmd(cmd, ok)
+
+	if cmd.Name == "" {
+		return
+	}
+
+	p.cmdResultsMu.Lock()
+	if p.cmdOK == nil {
+		p.cmdOK = map[string]bool{}
+	}
+
+	p.cmdOK[cmd.Name] = p.cmdOK[cmd.Name] || ok
+	p.cmdResultsMu.Unlock()
+}
+
+func (r ProbeResult) metric(name string) float64 {
+	switch name {
+	case config.ProbeMetricCalls:
+		return float64(r.Calls)
+	case config.ProbeMetricFailures:
+		return float64(r.Failures)
+	case config.ProbeMetricFailureRate:
+		return r.FailureRate
+	case config.ProbeMetricSuccessRate:
+		return r.SuccessRate
+	case config.ProbeMetricNotFoundRoutes:
+		return float64(r.NotFoundRoutes)
+	case config.ProbeMetricFailedCommands:
+		return float64(r.FailedCommands)
+	case config.ProbeMetricFailedCommandRate:
+		return r.FailedCommandRate
+	default:
+		return 0
+	}
+}
+
+func ruleTriggered(rule config.HTTPProbeSeverityRule, value float64) bool {
+	switch rule.Op {
+	case ">":
+		return value > rule.Threshold
+	case ">=":
+		return value >= rule.Threshold
+	case "<":
+		return value < rule.Threshold
+	case "<=":
+		return value <= rule.Threshold
+	default:
+		return false
+	}
+}
+
+// EvaluateSeverity returns the highest severity of the triggered rules
+// (the first rule with the highest severity is the reported rule)
+func EvaluateSeverity(result ProbeResult, rules []config.HTTPProbeSeverityRule) SeverityResult {
+	severity := SeverityResult{Severity: config.ProbeSeverityInfo}
+	for idx := range rules {
+		rule := &rules[idx]
+		value := result.metric(rule.Metric)
+		if !ruleTriggered(*rule, value) {
+			continue
+		}
+
+		if severity.Rule == nil || severityLevels[rule.Severity] > severityLevels[severity.Severity] {
+			severity = SeverityResult{
+				Severity: rule.Severity,
+				Rule:     rule,
+				Value:    value,
+			}
+		}
+	}
+
+	return severity
+}
+
+// Severity returns the probe result severity computed with the configured severity rules
+func (p *CustomProbe) Severity() SeverityResult {
+	return EvaluateSeverity(p.Result(), p.opts.SeverityRules)
+}
+
+func (p *CustomProbe) printSeverity() {
+	if len(p.opts.SeverityRules) == 0 {
+		return
+	}
+
+	severity := p.Severity()
+	info := ovars{
+		"severity": severity.Severity,
+	}
+
+	if severity.Rule != nil {
+		info["rule"] = severity.Rule.Rule
+		info["metric"] = severity.Rule.Metric
+		info["value"] = strconv.FormatFloat(severity.Value, 'f', -1, 64)
+	}
+
+	p.xc.Out.Info("http.probe.severity", info)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const headerHost = "Host"
+
+// cmdHostHeader returns the host name from the command Host header (without the port)
+func cmdHostHeader(cmd config.HTTPProbeCmd) string {
+	for _, hline := range cmd.Headers {
+		hparts := strings.SplitN(hline, ":", 2)
+		if len(hparts) != 2 || !strings.EqualFold(strings.TrimSpace(hparts[0]), headerHost) {
+			continue
+		}
+
+		host := strings.TrimSpace(hparts[1])
+		if name, _, err := net.SplitHostPort(host); err == nil {
+			host = name
+		}
+
+		return host
+	}
+
+	return ""
+}
+
+// tlsServerName returns the TLS server name (SNI) for the command calls.
+// The IP addresses can't be used for SNI, so the calls to the targets probed by IP
+// use the command Host header or the configured server name.
+// (returns an empty string to use the target host)
+func tlsServerName(cmd config.HTTPProbeCmd, targetHost, serverName string) string {
+	if host := cmdHostHeader(cmd); host != "" && net.ParseIP(host) == nil {
+		return host
+	}
+
+	if net.ParseIP(targetHost) != nil {
+		return serverName
+	}
+
+	return ""
+}
+
+// setTLSServerName sets the TLS server name for the client transport
+func setTLSServerName(client *http.Client, name string) {
+	transport := client.Transport
+	if ct, ok := transport.(*CassetteTransport); ok {
+		transport = ct.Transport
+	}
+
+	switch t := transport.(type) {
+	case *http.Transport:
+		if t.TLSClientConfig != nil {
+			t.TLSClientConfig.ServerName = name
+		}
+	case *http2.Transport:
+		if t.TLSClientConfig != nil {
+			t.TLSClientConfig.ServerName = name
+		}
+	}
+}
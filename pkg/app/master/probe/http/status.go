@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// StatusError is used when a probe call gets a response
+// with a status code the probe command doesn't expect
+type StatusError struct {
+	StatusCode int
+	Expected   []int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d (expected: %v)", e.StatusCode, e.Expected)
+}
+
+func isExpectedStatus(status int, expected []int) bool {
+	for _, val := range expected {
+		if val == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expectedStatus returns the status codes expected for a successful command call
+// (the command status codes, then the mode status codes, then the default status codes;
+// any response is successful if there are no expected status codes)
+func expectedStatus(cmd config.HTTPProbeCmd, defaults []int) []int {
+	if len(cmd.ExpectStatus) > 0 {
+		return cmd.ExpectStatus
+	}
+
+	switch cmd.Mode {
+	case config.ProbeModeWebDAV:
+		return webdavExpectedStatus(cmd.Method)
+	case config.ProbeModeRange, config.ProbeModeUpload:
+		//the mode checks validate the response status
+		return nil
+	}
+
+	return defaults
+}
+
+// isServerStatusError returns true if the call got an unexpected server error (5xx) response
+// (retried like the transient errors because the target app might still be starting)
+func isServerStatusError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// PortSummary provides the probe call counts for a probed port (or Unix socket)
+type PortSummary struct {
+	Port      string `json:"port,omitempty"`
+	Socket    string `json:"socket,omitempty"`
+	CallCount uint64 `json:"call_count"`
+	ErrCount  uint64 `json:"err_count"`
+	OkCount   uint64 `json:"ok_count"`
+}
+
+// Summary is the machine readable probe run summary
+// (for the probe users who need to act on the probe outcome without parsing the probe output)
+type Summary struct {
+	CallCount uint64 `json:"call_count"`
+	ErrCount  uint64 `json:"err_count"`
+	OkCount   uint64 `json:"ok_count"`
+	//the call counts for each probed port (based on the recorded call results)
+	Ports []PortSummary `json:"ports,omitempty"`
+	//the probe has no successful calls (including the probes without calls)
+	NoSuccessfulCalls bool `json:"no_successful_calls"`
+	//the probe was canceled or it reached its deadline
+	Canceled bool `json:"canceled,omitempty"`
+	//the probe was stopped at its deadline or when it reached its max time
+	DeadlineReached bool `json:"deadline_reached,omitempty"`
+	//the reason the probe result fails the command (see ResultError)
+	ResultError string `json:"result_error,omitempty"`
+}
+
+// Summary returns the probe run summary
+// (the complete summary is available when the probe is done, i.e., its DoneChan is closed)
+func (p *CustomProbe) Summary() Summary {
+	summary := Summary{
+		CallCount:   atomic.LoadUint64(&p.CallCount),
+		ErrCount:    atomic.LoadUint64(&p.ErrCount),
+		OkCount:     atomic.LoadUint64(&p.OkCount),
+		Canceled:    atomic.LoadUint32(&p.canceled) == 1,
+		ResultError: p.ResultError(),
+	}
+
+	summary.DeadlineReached = p.DeadlineReached()
+
+	summary.NoSuccessfulCalls = summary.OkCount == 0
+
+	type portKey struct {
+		port   string
+		socket string
+	}
+
+	var keys []portKey
+	ports := map[portKey]*PortSummary{}
+	for _, call := range p.CallResults() {
+		key := portKey{port: call.Port, socket: call.Socket}
+		ps, ok := ports[key]
+		if !ok {
+			ps = &PortSummary{Port: call.Port, Socket: call.Socket}
+			ports[key] = ps
+			keys = append(keys, key)
+		}
+
+		ps.CallCount++
+		if call.Error != "" {
+			ps.ErrCount++
+		} else {
+			ps.OkCount++
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].socket != keys[j].socket {
+			return keys[i].socket < keys[j].socket
+		}
+
+		return comparePorts(keys[i].port, keys[j].port)
+	})
+
+	for _, key := range keys {
+		summary.Ports = append(summary.Ports, *ports[key])
+	}
+
+	return summary
+}
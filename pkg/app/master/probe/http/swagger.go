@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi2"
@@ -202,7 +203,7 @@ func apiSpecPrefix(spec *openapi3.T) (string, error) {
 func (p *CustomProbe) loadAPISpecs(proto, targetHost, port string) {
 
 	baseAddr := getHTTPAddr(proto, targetHost, port)
-	client, err := getHTTPClient(proto)
+	client, err := getHTTPClient(proto, p.clientOpts)
 	if err != nil {
 		p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
 		return
@@ -283,7 +284,7 @@ func (p *CustomProbe) probeAPISpecEndpoints(proto, targetHost, port, prefix stri
 			})
 	}
 
-	httpClient, err := getHTTPClient(proto)
+	httpClient, err := getHTTPClient(proto, p.clientOpts)
 	if err != nil {
 		p.xc.Out.Error("HTTP probe - construct client error - %v", err.Error())
 		return
@@ -303,12 +304,12 @@ func (p *CustomProbe) probeAPISpecEndpoints(proto, targetHost, port, prefix stri
 		ops := pathOps(pathInfo)
 		for apiMethod := range ops {
 			//make a call (no params for now)
-			p.apiSpecEndpointCall(httpClient, endpoint, apiMethod)
+			p.apiSpecEndpointCall(httpClient, port, endpoint, apiMethod)
 		}
 	}
 }
 
-func (p *CustomProbe) apiSpecEndpointCall(client *http.Client, endpoint, method string) {
+func (p *CustomProbe) apiSpecEndpointCall(client *http.Client, port, endpoint, method string) {
 	maxRetryCount := probeRetryCount
 	if p.opts.RetryCount > 0 {
 		maxRetryCount = p.opts.RetryCount
@@ -324,23 +325,32 @@ func (p *CustomProbe) apiSpecEndpointCall(client *http.Client, endpoint, method
 	}
 
 	method = strings.ToUpper(method)
-	for i := 0; i < maxRetryCount; i++ {
-		req, err := http.NewRequest(method, endpoint, nil)
+	backoff := newRetryBackoff(p.opts.RetryBackoffReset, p.opts.RetryMaxWait)
+	for i := 0; i < maxRetryCount && !p.stopped(); i++ {
+		req, err := http.NewRequestWithContext(p.ctx, method, endpoint, nil)
 		if err != nil {
 			p.xc.Out.Error("HTTP probe - construct request error - %v", err.Error())
 			// Break since the same args are passed to NewRequest() on each loop.
 			break
 		}
-		//no body, no request headers and no credentials for now
+		//no body and no credentials for now (only the default headers)
+		setDefaultHeaders(req.Header, p.defaultHeaders)
+		p.throttleCPU()
+		p.waitCallRate()
+
+		callStart := time.Now()
 		res, err := client.Do(req)
-		p.CallCount++
+		callDuration := time.Since(callStart)
+		atomic.AddUint64(&p.CallCount, 1)
 
+		var statusNum int
 		if res != nil {
+			statusNum = res.StatusCode
 			if res.Body != nil {
+				//closing the body in each retry iteration (the deferred calls would keep all bodies open)
 				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
 			}
-
-			defer res.Body.Close()
 		}
 
 		statusCode := "error"
@@ -351,6 +361,19 @@ func (p *CustomProbe) apiSpecEndpointCall(client *http.Client, endpoint, method
 			callErrorStr = err.Error()
 		}
 
+		p.addCallResult(CallResult{
+			Time:       callStart,
+			Port:       port,
+			CmdIndex:   -1,
+			Method:     method,
+			Path:       req.URL.RequestURI(),
+			Target:     endpoint,
+			StatusCode: statusNum,
+			Attempt:    i + 1,
+			Duration:   callDuration,
+			Error:      errorString(err),
+		})
+
 		if p.printState {
 			p.xc.Out.Info("http.probe.api-spec.probe.endpoint.call",
 				ovars{
@@ -364,23 +387,28 @@ func (p *CustomProbe) apiSpecEndpointCall(client *http.Client, endpoint, method
 		}
 
 		if err == nil {
-			p.OkCount++
+			atomic.AddUint64(&p.OkCount, 1)
 			break
 		} else {
-			p.ErrCount++
+			atomic.AddUint64(&p.ErrCount, 1)
+
+			if !isRetryableError(err, p.opts.RetryOn) {
+				log.Debugf("HTTP probe - not retrying the call (error.category=%s)", errorCategory(err))
+				break
+			}
 
 			if urlErr, ok := err.(*url.Error); ok {
-				if urlErr.Err == io.EOF {
+				if isNotReadyError(urlErr.Err) {
 					log.Debugf("HTTP probe - target not ready yet (retry again later)...")
-					time.Sleep(notReadyErrorWait * time.Second)
+					p.sleep(backoff.next(notReadyErrorWait * time.Second))
 				} else {
 					log.Debugf("HTTP probe - web error... retry again later...")
-					time.Sleep(webErrorWait * time.Second)
+					p.sleep(backoff.next(webErrorWait * time.Second))
 
 				}
 			} else {
 				log.Debugf("HTTP probe - other error... retry again later...")
-				time.Sleep(otherErrorWait * time.Second)
+				p.sleep(backoff.next(otherErrorWait * time.Second))
 			}
 		}
 
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"sort"
+	"time"
+)
+
+// CmdTiming provides the response time statistics for a probe command
+// (for the command calls with a response on all probed targets)
+type CmdTiming struct {
+	Method   string
+	Resource string
+	Calls    int
+	Min      time.Duration
+	Avg      time.Duration
+	Max      time.Duration
+	P95      time.Duration
+}
+
+// CmdTimings returns the response time statistics for the probe commands (in the command order)
+// The calls without a response (e.g., the connection errors and the timeouts) are not included,
+// so the failing targets don't skew the statistics.
+func (p *CustomProbe) CmdTimings() []CmdTiming {
+	durations := map[int][]time.Duration{}
+	for _, result := range p.CallResults() {
+		if result.CmdIndex < 0 || result.CmdIndex >= len(p.opts.Cmds) || result.StatusCode == 0 {
+			continue
+		}
+
+		durations[result.CmdIndex] = append(durations[result.CmdIndex], result.Duration)
+	}
+
+	var timings []CmdTiming
+	for idx, cmd := range p.opts.Cmds {
+		values := durations[idx]
+		if len(values) == 0 {
+			continue
+		}
+
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		var total time.Duration
+		for _, val := range values {
+			total += val
+		}
+
+		timings = append(timings, CmdTiming{
+			Method:   cmd.Method,
+			Resource: cmd.Resource,
+			Calls:    len(values),
+			Min:      values[0],
+			Avg:      total / time.Duration(len(values)),
+			Max:      values[len(values)-1],
+			P95:      percentile(values, 95),
+		})
+	}
+
+	return timings
+}
+
+// percentile returns the nearest-rank percentile for the sorted values
+func percentile(sorted []time.Duration, pct int) time.Duration {
+	rank := (pct*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func (p *CustomProbe) printTimings() {
+	for _, timing := range p.CmdTimings() {
+		p.xc.Out.Info("http.probe.timing",
+			ovars{
+				"method":   timing.Method,
+				"resource": timing.Resource,
+				"calls":    timing.Calls,
+				"min":      timing.Min.Round(time.Microsecond).String(),
+				"avg":      timing.Avg.Round(time.Microsecond).String(),
+				"max":      timing.Max.Round(time.Microsecond).String(),
+				"p95":      timing.P95.Round(time.Microsecond).String(),
+			})
+	}
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// the host for the Unix socket call URLs (and their Host header)
+const unixSocketHost = "localhost"
+
+// cmdUnixSocket returns the Unix socket path for the command
+// (empty if the command calls are sent to the target address and port)
+func cmdUnixSocket(cmd config.HTTPProbeCmd) string {
+	if cmd.UnixSocket == "" {
+		return ""
+	}
+
+	path, err := config.ParseProbeUnixSocket(cmd.UnixSocket)
+	if err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// socketOnlyCmds returns true if all probe commands use Unix sockets
+// (the target address and port are not probed then)
+func socketOnlyCmds(cmds []config.HTTPProbeCmd) bool {
+	for _, cmd := range cmds {
+		if cmdUnixSocket(cmd) == "" {
+			return false
+		}
+	}
+
+	return len(cmds) > 0
+}
+
+// socketTargets returns the probe targets for the command Unix sockets (in the command order)
+func socketTargets(cmds []config.HTTPProbeCmd) []probeTarget {
+	var targets []probeTarget
+	seen := map[string]bool{}
+	for _, cmd := range cmds {
+		path := cmdUnixSocket(cmd)
+		if path == "" || seen[path] {
+			continue
+		}
+
+		seen[path] = true
+		targets = append(targets, probeTarget{host: unixSocketHost, socket: path})
+	}
+
+	return targets
+}
+
+// setUnixSocket connects the client transport to the Unix socket (instead of the call URL address)
+func setUnixSocket(client *http.Client, copts *clientOptions, path string) {
+	transport := client.Transport
+	if ct, ok := transport.(*CassetteTransport); ok {
+		transport = ct.Transport
+	}
+
+	dial := func(ctx context.Context) (net.Conn, error) {
+		return copts.dialContext(ctx, "unix", path)
+	}
+
+	switch t := transport.(type) {
+	case *http.Transport:
+		//the Unix socket calls are local (they never go through a proxy)
+		t.Proxy = nil
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx)
+		}
+	case *http2.Transport:
+		if t.AllowHTTP {
+			t.DialTLS = func(_, _ string, _ *tls.Config) (net.Conn, error) {
+				return dial(context.Background())
+			}
+			return
+		}
+
+		t.DialTLS = func(_, _ string, cfg *tls.Config) (net.Conn, error) {
+			conn, err := dial(context.Background())
+			if err != nil {
+				return nil, err
+			}
+
+			tconn := tls.Client(conn, cfg)
+			if err := tconn.Handshake(); err != nil {
+				conn.Close()
+				return nil, err
+			}
+
+			return tconn, nil
+		}
+	}
+}
+
+// socketReady returns true if the Unix socket is ready for the probe calls
+// (the probe commands for the socket are skipped if it doesn't accept connections before the timeout)
+func (p *CustomProbe) socketReady(path string) bool {
+	if p.portReadyTimeout == 0 {
+		return true
+	}
+
+	if p.waitForAddrReady("unix", path, p.portReadyTimeout) {
+		return true
+	}
+
+	if p.stopped() {
+		return false
+	}
+
+	if p.printState {
+		p.xc.Out.State("http.probe.socket.timeout",
+			ovars{
+				"socket":  path,
+				"timeout": p.portReadyTimeout.String(),
+			})
+	}
+
+	return false
+}
+
+// printSocketSummary prints the call results for each probed Unix socket
+func (p *CustomProbe) printSocketSummary() {
+	targets := socketTargets(p.opts.Cmds)
+	if len(targets) == 0 {
+		return
+	}
+
+	type socketCounts struct {
+		total      uint64
+		failures   uint64
+		successful uint64
+	}
+
+	counts := map[string]*socketCounts{}
+	for _, result := range p.CallResults() {
+		if result.Socket == "" {
+			continue
+		}
+
+		if counts[result.Socket] == nil {
+			counts[result.Socket] = &socketCounts{}
+		}
+
+		counts[result.Socket].total++
+		if result.Error == "" {
+			counts[result.Socket].successful++
+		} else {
+			counts[result.Socket].failures++
+		}
+	}
+
+	for _, target := range targets {
+		info := counts[target.socket]
+		if info == nil {
+			info = &socketCounts{}
+		}
+
+		p.xc.Out.Info("http.probe.socket.summary",
+			ovars{
+				"socket":     target.socket,
+				"total":      info.total,
+				"failures":   info.failures,
+				"successful": info.successful,
+			})
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultUploadSize      = 1024 * 1024
+	defaultUploadChunkSize = 32 * 1024
+	maxUploadResponseSize  = 64 * 1024
+)
+
+var ErrUploadNotVerified = errors.New("upload size not found in response")
+
+var uploadDataPattern = []byte("slim.probe.upload.data.")
+
+// uploadBodyReader generates the upload data as it's read,
+// so large uploads don't need to be buffered in memory.
+// Each read returns at most one chunk of data.
+type uploadBodyReader struct {
+	size      int64
+	chunkSize int
+	offset    int64
+}
+
+func newUploadBodyReader(size int64, chunkSize int) *uploadBodyReader {
+	if size <= 0 {
+		size = defaultUploadSize
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = defaultUploadChunkSize
+	}
+
+	return &uploadBodyReader{
+		size:      size,
+		chunkSize: chunkSize,
+	}
+}
+
+func (r *uploadBodyReader) Read(buf []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	count := len(buf)
+	if count > r.chunkSize {
+		count = r.chunkSize
+	}
+
+	if remaining := r.size - r.offset; int64(count) > remaining {
+		count = int(remaining)
+	}
+
+	for i := 0; i < count; i++ {
+		buf[i] = uploadDataPattern[(r.offset+int64(i))%int64(len(uploadDataPattern))]
+	}
+
+	r.offset += int64(count)
+	return count, nil
+}
+
+// Seek only supports rewinding (used to retry the upload)
+func (r *uploadBodyReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return r.offset, errors.New("unsupported seek")
+	}
+
+	r.offset = 0
+	return 0, nil
+}
+
+// checkUploadResponse checks that the upload was accepted by the target
+// and, optionally, that the response body includes the uploaded byte count
+func checkUploadResponse(res *http.Response, body []byte, size int64, verify bool) error {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &StatusError{
+			StatusCode: res.StatusCode,
+			Expected:   []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent},
+		}
+	}
+
+	if verify && !bytes.Contains(body, []byte(strconv.FormatInt(size, 10))) {
+		return fmt.Errorf("%w (size=%d)", ErrUploadNotVerified, size)
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const (
+	captureSourceHeader = "header:"
+	captureSourceJSON   = "json:"
+	captureSourceStatus = "status"
+	captureSourceBody   = "body"
+	captureSourceSize   = "size"
+	captureSourceURL    = "final_url"
+
+	builtinVarStatus        = "status"
+	builtinVarSize          = "size"
+	builtinVarContentLength = "content_length"
+	builtinVarFinalURL      = "final_url"
+	builtinVarRedirects     = "redirects"
+
+	maxCaptureBodySize = 1024 * 1024
+)
+
+var (
+	ErrCaptureNotFound = errors.New("captured value not found")
+	ErrAssertFailed    = errors.New("assertion failed")
+)
+
+var varRefRE = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// variable template references (e.g., '{{token}}')
+// the references to the unknown variables are left for the data generator templates
+var varTemplateRE = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
+
+func needsResponseBody(cmd config.HTTPProbeCmd) bool {
+	return len(cmd.Capture) > 0 || len(cmd.Assert) > 0
+}
+
+// the variable scope for the values captured by the preflight commands
+// (they are available for all probe targets)
+const sharedVarScope = -1
+
+// cmdVarScope returns the variable scope for the command calls.
+// Each probe target has its own captured variables (the probe index is the scope),
+// so the targets probed in parallel don't use the values captured for the other targets.
+func cmdVarScope(cmdIdx, targetIdx int) int {
+	if cmdIdx == preflightCmdIdx {
+		return sharedVarScope
+	}
+
+	return targetIdx
+}
+
+// getVar returns the variable value for the scope (or the shared value if the scope doesn't have it)
+func (p *CustomProbe) getVar(scope int, name string) (string, bool) {
+	p.varsMu.Lock()
+	defer p.varsMu.Unlock()
+	if val, ok := p.vars[scope][name]; ok {
+		return val, true
+	}
+
+	val, ok := p.vars[sharedVarScope][name]
+	return val, ok
+}
+
+func (p *CustomProbe) setVar(scope int, name, value string) {
+	p.varsMu.Lock()
+	defer p.varsMu.Unlock()
+	if p.vars == nil {
+		p.vars = map[int]map[string]string{}
+	}
+
+	if p.vars[scope] == nil {
+		p.vars[scope] = map[string]string{}
+	}
+
+	p.vars[scope][name] = value
+}
+
+func (p *CustomProbe) expandVarRefs(scope int, value string) string {
+	value = varRefRE.ReplaceAllStringFunc(value, func(ref string) string {
+		name := varRefRE.FindStringSubmatch(ref)[1]
+		if val, ok := p.getVar(scope, name); ok {
+			return val
+		}
+
+		log.Debugf("HTTP probe - unknown variable reference => %s", ref)
+		return ref
+	})
+
+	return varTemplateRE.ReplaceAllStringFunc(value, func(ref string) string {
+		name := varTemplateRE.FindStringSubmatch(ref)[1]
+		if val, ok := p.getVar(scope, name); ok {
+			return val
+		}
+
+		return ref
+	})
+}
+
+// cmdVarRefs returns the variable names referenced by the command
+// (the '{{name}}' references include the data generator templates)
+func cmdVarRefs(cmd config.HTTPProbeCmd) []string {
+	var names []string
+	values := append([]string{cmd.Resource, cmd.BaseURL, cmd.Body}, cmd.Headers...)
+	values = append(values, cmd.Exec...)
+	for _, value := range values {
+		for _, re := range []*regexp.Regexp{varRefRE, varTemplateRE} {
+			for _, match := range re.FindAllStringSubmatch(value, -1) {
+				names = append(names, match[1])
+			}
+		}
+	}
+
+	return names
+}
+
+// expandVars replaces the '${name}' (or '{{name}}') variable references
+// in the command resource, headers, body and exec arguments with the values captured for the variable scope
+func (p *CustomProbe) expandVars(scope int, cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	cmd.Resource = p.expandVarRefs(scope, cmd.Resource)
+	cmd.BaseURL = p.expandVarRefs(scope, cmd.BaseURL)
+	cmd.Body = p.expandVarRefs(scope, cmd.Body)
+
+	var headers []string
+	for _, header := range cmd.Headers {
+		headers = append(headers, p.expandVarRefs(scope, header))
+	}
+	cmd.Headers = headers
+
+	if len(cmd.Exec) > 0 {
+		var args []string
+		for _, arg := range cmd.Exec {
+			args = append(args, p.expandVarRefs(scope, arg))
+		}
+		cmd.Exec = args
+	}
+
+	return cmd
+}
+
+// captureValue extracts a value from the response
+// ('status', 'body', 'size', 'final_url', 'header:<name>' or 'json:<path>', the default is a JSON path).
+// The JSON path is a list of dot separated object keys or array indexes (e.g., 'data.items.0.id').
+// The 'size' value is the number of the body bytes received
+// and the Content-Length header falls back to it for the chunked responses.
+func captureValue(source string, res *http.Response, body responseBody) (string, error) {
+	header := res.Header
+	switch {
+	case source == captureSourceStatus:
+		return strconv.Itoa(res.StatusCode), nil
+	case source == captureSourceBody:
+		return string(body.data), nil
+	case source == captureSourceSize:
+		return strconv.FormatInt(body.size, 10), nil
+	case source == captureSourceURL:
+		return finalURL(res), nil
+	case strings.HasPrefix(source, captureSourceHeader):
+		name := strings.TrimSpace(strings.TrimPrefix(source, captureSourceHeader))
+		if values := header.Values(name); len(values) > 0 {
+			return values[0], nil
+		}
+
+		if strings.EqualFold(name, headerContentLength) && body.chunked() {
+			return strconv.FormatInt(body.size, 10), nil
+		}
+
+		return "", fmt.Errorf("%w (header '%s')", ErrCaptureNotFound, name)
+	}
+
+	if body.truncated {
+		return "", fmt.Errorf("%w (json '%s' - response body too large)", ErrCaptureNotFound, source)
+	}
+
+	path := strings.TrimPrefix(source, captureSourceJSON)
+	var data interface{}
+	if err := json.Unmarshal(body.data, &data); err != nil {
+		return "", err
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+
+		switch val := data.(type) {
+		case map[string]interface{}:
+			field, ok := val[key]
+			if !ok {
+				return "", fmt.Errorf("%w (json '%s')", ErrCaptureNotFound, path)
+			}
+
+			data = field
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(val) {
+				return "", fmt.Errorf("%w (json '%s')", ErrCaptureNotFound, path)
+			}
+
+			data = val[idx]
+		default:
+			return "", fmt.Errorf("%w (json '%s')", ErrCaptureNotFound, path)
+		}
+	}
+
+	switch val := data.(type) {
+	case string:
+		return val, nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case nil:
+		return "", nil
+	default:
+		raw, err := json.Marshal(val)
+		return string(raw), err
+	}
+}
+
+// exprVarValues returns the current values of the variables in the expression
+func exprVarValues(expr string, lookup func(name string) (string, bool)) string {
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return ""
+	}
+
+	var values []string
+	for _, tok := range tokens {
+		if tok.kind != "ident" || tok.text == "true" || tok.text == "false" {
+			continue
+		}
+
+		val, ok := lookup(tok.text)
+		if !ok {
+			val = "<unknown>"
+		}
+
+		values = append(values, fmt.Sprintf("%s=%s", tok.text, val))
+	}
+
+	return strings.Join(values, ",")
+}
+
+// captureAndAssert saves the captured response values
+// and then evaluates the command assertions
+func (p *CustomProbe) captureAndAssert(
+	scope int,
+	cmd config.HTTPProbeCmd,
+	addr string,
+	res *http.Response,
+	body responseBody) error {
+	for name, source := range cmd.Capture {
+		val, err := captureValue(source, res, body)
+		if err != nil {
+			log.Debugf("HTTP probe - capture error (%s=%s) - %v", name, source, err)
+			return err
+		}
+
+		log.Debugf("HTTP probe - captured variable => %s='%s'", name, val)
+		p.setVar(scope, name, val)
+	}
+
+	lookup := func(name string) (string, bool) {
+		switch name {
+		case builtinVarStatus:
+			return strconv.Itoa(res.StatusCode), true
+		case builtinVarSize:
+			return strconv.FormatInt(body.size, 10), true
+		case builtinVarContentLength:
+			return strconv.FormatInt(body.contentLength(), 10), true
+		case builtinVarFinalURL:
+			return finalURL(res), true
+		case builtinVarRedirects:
+			return strconv.Itoa(len(redirectChain(res)) - 1), true
+		}
+
+		return p.getVar(scope, name)
+	}
+
+	var failed []string
+	for _, expr := range cmd.Assert {
+		result := "passed"
+		errStr := "none"
+		val, err := evalExpr(expr, lookup)
+		if err == nil {
+			var ok bool
+			if ok, err = val.asBool(); err == nil && !ok {
+				result = "failed"
+			}
+		}
+
+		if err != nil {
+			result = "error"
+			errStr = err.Error()
+		}
+
+		if result != "passed" {
+			failed = append(failed, expr)
+		}
+
+		if p.printState {
+			p.xc.Out.Info("http.probe.call.assert",
+				ovars{
+					"result": result,
+					"method": cmd.Method,
+					"target": addr,
+					"assert": expr,
+					"values": exprVarValues(expr, lookup),
+					"error":  errStr,
+					"time":   time.Now().UTC().Format(time.RFC3339),
+				})
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%w (%s)", ErrAssertFailed, strings.Join(failed, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// VCR-style record/playback support for the probe HTTP clients.
+// In the 'record' mode the real interactions are captured and saved to a cassette file.
+// In the 'playback' mode the responses are served from the cassette file
+// without making any network calls.
+// Requests are matched by method and request URI (path and query),
+// so the host and port can be different between the record and playback runs
+// (the credential query parameters are compared redacted).
+
+var ErrCassetteNoMatch = errors.New("no matching cassette interaction")
+
+// the value saved in the cassettes for the credential headers and query parameters
+const cassetteRedactedValue = "[REDACTED]"
+
+// the headers with the credentials (they are redacted in the saved interactions)
+var cassetteCredentialHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// the header name parts of the API key and token headers (e.g., 'X-Api-Key' and 'X-Auth-Token')
+var cassetteCredentialHeaderParts = []string{
+	"token",
+	"api-key",
+	"apikey",
+	"secret",
+	"password",
+}
+
+// the query parameters with the credentials (they are redacted in the saved request URLs)
+var cassetteCredentialParams = map[string]struct{}{
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"id_token":      {},
+	"api_key":       {},
+	"api-key":       {},
+	"apikey":        {},
+	"key":           {},
+	"password":      {},
+	"secret":        {},
+	"client_secret": {},
+	"signature":     {},
+}
+
+func isCassetteCredentialHeader(name string) bool {
+	for _, hname := range cassetteCredentialHeaders {
+		if strings.EqualFold(name, hname) {
+			return true
+		}
+	}
+
+	lname := strings.ToLower(name)
+	for _, part := range cassetteCredentialHeaderParts {
+		if strings.Contains(lname, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactCassetteHeaders returns a copy of the headers with the credential header values redacted
+// (the credentials, including the resolved secret references, are never saved in the cassettes)
+func redactCassetteHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for name, values := range redacted {
+		if !isCassetteCredentialHeader(name) {
+			continue
+		}
+
+		for idx := range values {
+			values[idx] = cassetteRedactedValue
+		}
+	}
+
+	return redacted
+}
+
+// redactCassetteURL returns the URL with the credential query parameter values redacted
+// (the URLs without the credential query parameters are returned as-is)
+func redactCassetteURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+
+	query := u.Query()
+	var redacted bool
+	for name, values := range query {
+		if _, ok := cassetteCredentialParams[strings.ToLower(name)]; !ok {
+			continue
+		}
+
+		for idx := range values {
+			values[idx] = cassetteRedactedValue
+		}
+
+		redacted = true
+	}
+
+	if !redacted {
+		return raw
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// Cassette is a set of recorded request/response interactions
+type Cassette struct {
+	Interactions []*CassetteInteraction `json:"interactions"`
+
+	mu     sync.Mutex
+	played map[int]bool
+}
+
+func NewCassette() *Cassette {
+	return &Cassette{
+		played: map[int]bool{},
+	}
+}
+
+// LoadCassette loads a cassette from a file
+func LoadCassette(name string) (*Cassette, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := NewCassette()
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+// Save saves the cassette interactions to a file
+func (c *Cassette) Save(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	//the recorded interactions can have the sensitive app data
+	return os.WriteFile(name, data, 0600)
+}
+
+func (c *Cassette) add(interaction *CassetteInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+// match returns the first matching interaction that hasn't been played yet
+// or the last matching interaction if all of them have been played already
+func (c *Cassette) match(method, uri string) *CassetteInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for idx, interaction := range c.Interactions {
+		if interaction.Request.Method != method ||
+			requestURI(redactCassetteURL(interaction.Request.URL)) != uri {
+			continue
+		}
+
+		if !c.played[idx] {
+			c.played[idx] = true
+			return interaction
+		}
+
+		last = idx
+	}
+
+	if last != -1 {
+		return c.Interactions[last]
+	}
+
+	return nil
+}
+
+func requestURI(raw string) string {
+	req, err := http.NewRequest(http.MethodGet, raw, nil)
+	if err != nil {
+		return raw
+	}
+
+	return req.URL.RequestURI()
+}
+
+// CassetteTransport is an http.RoundTripper that records
+// or plays back the interactions in a cassette
+type CassetteTransport struct {
+	Mode      string
+	Cassette  *Cassette
+	Transport http.RoundTripper
+}
+
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch t.Mode {
+	case config.CassetteModeRecord:
+		return t.record(req)
+	case config.CassetteModePlayback:
+		return t.playback(req)
+	default:
+		return nil, fmt.Errorf("unknown cassette mode - %s", t.Mode)
+	}
+}
+
+func (t *CassetteTransport) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		//failed calls are not recorded
+		return nil, err
+	}
+
+	var resBody []byte
+	if res.Body != nil {
+		resBody, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		res.Body = io.NopCloser(bytes.NewReader(resBody))
+	}
+
+	t.Cassette.add(&CassetteInteraction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     redactCassetteURL(req.URL.String()),
+			Headers: redactCassetteHeaders(req.Header),
+			Body:    string(reqBody),
+		},
+		Response: CassetteResponse{
+			StatusCode: res.StatusCode,
+			Headers:    redactCassetteHeaders(res.Header),
+			Body:       string(resBody),
+		},
+	})
+
+	return res, nil
+}
+
+func (t *CassetteTransport) playback(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	interaction := t.Cassette.match(req.Method, requestURI(redactCassetteURL(req.URL.String())))
+	if interaction == nil {
+		log.Debugf("CassetteTransport.playback: no match for %s %s", req.Method, req.URL.String())
+		return nil, ErrCassetteNoMatch
+	}
+
+	headers := interaction.Response.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	body := []byte(interaction.Response.Body)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (p *CustomProbe) saveCassette() {
+	if p.opts.CassetteMode != config.CassetteModeRecord ||
+		p.clientOpts.cassette == nil {
+		return
+	}
+
+	if err := p.clientOpts.cassette.Save(p.opts.CassetteFile); err != nil {
+		log.Warnf("HTTP probe - error saving the cassette (%s) - %v", p.opts.CassetteFile, err)
+		return
+	}
+
+	if p.printState {
+		p.xc.Out.Info("http.probe.cassette.saved",
+			ovars{
+				"file":         p.opts.CassetteFile,
+				"interactions": len(p.clientOpts.cassette.Interactions),
+			})
+	}
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+func TestCassetteRecordPlayback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Probe", "recorded")
+		fmt.Fprintf(w, "%s %s", r.Method, r.URL.RequestURI())
+	}))
+
+	cassetteFile := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder := &CassetteTransport{
+		Mode:     config.CassetteModeRecord,
+		Cassette: NewCassette(),
+	}
+
+	client := &http.Client{Transport: recorder}
+	for _, path := range []string{"/", "/api/info?id=1"} {
+		res, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("record call error: %v", err)
+		}
+
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	if err := recorder.Cassette.Save(cassetteFile); err != nil {
+		t.Fatalf("cassette save error: %v", err)
+	}
+
+	//no live server for the playback calls
+	srv.Close()
+
+	cassette, err := LoadCassette(cassetteFile)
+	if err != nil {
+		t.Fatalf("cassette load error: %v", err)
+	}
+
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("got %d interactions expected 2", len(cassette.Interactions))
+	}
+
+	client = &http.Client{
+		Transport: &CassetteTransport{
+			Mode:     config.CassetteModePlayback,
+			Cassette: cassette,
+		},
+	}
+
+	tt := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/api/info?id=1", expected: "GET /api/info?id=1"},
+		{path: "/", expected: "GET /"},
+		//replayed again for retries
+		{path: "/", expected: "GET /"},
+	}
+
+	for _, test := range tt {
+		//a different host/port is ok for playback
+		res, err := client.Get("http://127.0.0.1:1" + test.path)
+		if err != nil {
+			t.Fatalf("playback call error (%s): %v", test.path, err)
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if string(body) != test.expected {
+			t.Errorf("got '%s' expected '%s'", body, test.expected)
+		}
+
+		if res.Header.Get("X-Probe") != "recorded" {
+			t.Errorf("missing recorded response header (%s)", test.path)
+		}
+	}
+
+	if _, err := client.Get("http://127.0.0.1:1/unknown"); err == nil {
+		t.Errorf("expected an error for an unknown interaction")
+	}
+}
+
+func TestCassetteRecordRedactsCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "server-session-secret"})
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	recorder := &CassetteTransport{
+		Mode:     config.CassetteModeRecord,
+		Cassette: NewCassette(),
+	}
+
+	client := &http.Client{Transport: recorder}
+	for _, setAuth := range []func(req *http.Request){
+		func(req *http.Request) { req.SetBasicAuth("user", "basic-password-secret") },
+		func(req *http.Request) { req.Header.Set("Authorization", "Bearer bearer-token-secret") },
+		func(req *http.Request) { req.Header.Set("Proxy-Authorization", "Basic proxy-secret") },
+		func(req *http.Request) { req.Header.Set("Cookie", "session=client-session-secret") },
+		func(req *http.Request) { req.Header.Set("X-Api-Key", "api-key-secret") },
+		func(req *http.Request) { req.Header.Set("X-Auth-Token", "auth-token-secret") },
+		func(req *http.Request) { req.URL.RawQuery = "page=1&token=query-token-secret" },
+		func(req *http.Request) { req.URL.RawQuery = "api_key=query-key-secret" },
+	} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/private", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		setAuth(req)
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("record call error: %v", err)
+		}
+
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	cassetteFile := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Cassette.Save(cassetteFile); err != nil {
+		t.Fatalf("cassette save error: %v", err)
+	}
+
+	info, err := os.Stat(cassetteFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("got cassette file mode %v expected %v", mode, os.FileMode(0600))
+	}
+
+	data, err := os.ReadFile(cassetteFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{
+		"basic-password-secret",
+		"dXNlcjpiYXNpYy1wYXNzd29yZC1zZWNyZXQ", //base64 'user:basic-password-secret'
+		"bearer-token-secret",
+		"proxy-secret",
+		"client-session-secret",
+		"server-session-secret",
+		"api-key-secret",
+		"auth-token-secret",
+		"query-token-secret",
+		"query-key-secret",
+	} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette has a credential value (%s)", secret)
+		}
+	}
+
+	if !strings.Contains(string(data), cassetteRedactedValue) {
+		t.Errorf("cassette has no redacted credential headers")
+	}
+
+	cassette, err := LoadCassette(cassetteFile)
+	if err != nil {
+		t.Fatalf("cassette load error: %v", err)
+	}
+
+	//the calls with the credential query parameters are still played back
+	client = &http.Client{
+		Transport: &CassetteTransport{
+			Mode:     config.CassetteModePlayback,
+			Cassette: cassette,
+		},
+	}
+
+	res, err := client.Get("http://127.0.0.1:1/private?page=1&token=other-token")
+	if err != nil {
+		t.Fatalf("playback call error: %v", err)
+	}
+
+	res.Body.Close()
+}
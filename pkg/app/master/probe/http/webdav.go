@@ -0,0 +1,182 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+// WebDAV methods (RFC 4918)
+const (
+	MethodPropFind  = "PROPFIND"
+	MethodPropPatch = "PROPPATCH"
+	MethodMkCol     = "MKCOL"
+	MethodCopy      = "COPY"
+	MethodMove      = "MOVE"
+	MethodLock      = "LOCK"
+	MethodUnlock    = "UNLOCK"
+)
+
+const (
+	headerDepth       = "Depth"
+	headerDestination = "Destination"
+	headerOverwrite   = "Overwrite"
+	headerTimeout     = "Timeout"
+	headerLockToken   = "Lock-Token"
+	headerContentType = "Content-Type"
+
+	webdavContentType     = "application/xml; charset=utf-8"
+	webdavDestinationExt  = ".slim-probe"
+	webdavDefaultLockTime = "Second-60"
+)
+
+var ErrWebDAVNoDestination = errors.New("WebDAV MOVE call without the Destination header")
+
+const webdavPropFindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:">
+  <D:allprop/>
+</D:propfind>`
+
+const webdavPropPatchBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propertyupdate xmlns:D="DAV:" xmlns:Z="http://slimtoolkit.org/probe/">
+  <D:set>
+    <D:prop>
+      <Z:probe>slim</Z:probe>
+    </D:prop>
+  </D:set>
+</D:propertyupdate>`
+
+const webdavLockBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:lockinfo xmlns:D="DAV:">
+  <D:lockscope><D:exclusive/></D:lockscope>
+  <D:locktype><D:write/></D:locktype>
+  <D:owner>slim</D:owner>
+</D:lockinfo>`
+
+// webdavExpectedStatus returns the status codes expected for a successful WebDAV call
+func webdavExpectedStatus(method string) []int {
+	switch method {
+	case MethodPropFind, MethodPropPatch:
+		return []int{http.StatusMultiStatus}
+	case MethodMkCol:
+		return []int{http.StatusCreated}
+	case MethodCopy, MethodMove:
+		return []int{http.StatusCreated, http.StatusNoContent}
+	case MethodLock:
+		return []int{http.StatusOK, http.StatusCreated}
+	case MethodUnlock:
+		return []int{http.StatusNoContent, http.StatusOK}
+	default:
+		//regular HTTP methods used with WebDAV resources
+		return []int{http.StatusOK, http.StatusCreated, http.StatusNoContent, http.StatusMultiStatus}
+	}
+}
+
+func hasHeader(headers []string, name string) bool {
+	for _, hline := range headers {
+		hparts := strings.SplitN(hline, ":", 2)
+		if strings.EqualFold(strings.TrimSpace(hparts[0]), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withWebDAVDefaults fills in the conventional WebDAV headers and bodies
+// for the command method (the values provided by the user are preserved)
+func withWebDAVDefaults(cmd config.HTTPProbeCmd) config.HTTPProbeCmd {
+	headers := append([]string{}, cmd.Headers...)
+	addDefault := func(name, value string) {
+		if !hasHeader(headers, name) {
+			headers = append(headers, name+": "+value)
+		}
+	}
+
+	noBody := cmd.Body == "" && cmd.BodyFile == ""
+	switch cmd.Method {
+	case MethodPropFind:
+		addDefault(headerDepth, "1")
+		if noBody {
+			cmd.Body = webdavPropFindBody
+		}
+	case MethodPropPatch:
+		if noBody {
+			cmd.Body = webdavPropPatchBody
+		}
+	case MethodCopy, MethodMove:
+		addDefault(headerDepth, "infinity")
+		addDefault(headerOverwrite, "T")
+	case MethodLock:
+		addDefault(headerDepth, "0")
+		addDefault(headerTimeout, webdavDefaultLockTime)
+		if noBody {
+			cmd.Body = webdavLockBody
+		}
+	}
+
+	if cmd.Body != "" || cmd.BodyFile != "" {
+		addDefault(headerContentType, webdavContentType)
+	}
+
+	cmd.Headers = headers
+	return cmd
+}
+
+// prepareWebDAVRequest sets the request specific WebDAV headers
+// that depend on the target address or on other calls.
+// The MOVE calls need an explicit destination (a default destination would relocate the real resource).
+func (p *CustomProbe) prepareWebDAVRequest(client *http.Client, req *http.Request) error {
+	switch req.Method {
+	case MethodCopy:
+		if req.Header.Get(headerDestination) == "" {
+			dst := *req.URL
+			dst.Path = strings.TrimSuffix(dst.Path, "/") + webdavDestinationExt
+			req.Header.Set(headerDestination, dst.String())
+		}
+	case MethodMove:
+		if req.Header.Get(headerDestination) == "" {
+			return fmt.Errorf("%w (%s)", ErrWebDAVNoDestination, req.URL.Path)
+		}
+	case MethodUnlock:
+		if req.Header.Get(headerLockToken) == "" {
+			if token := p.webdavLockToken(client, req); token != "" {
+				req.Header.Set(headerLockToken, token)
+			}
+		}
+	}
+
+	return nil
+}
+
+// webdavLockToken locks the target resource to get a lock token for the UNLOCK call
+func (p *CustomProbe) webdavLockToken(client *http.Client, req *http.Request) string {
+	lreq := req.Clone(req.Context())
+	lreq.Method = MethodLock
+	lreq.Body = io.NopCloser(strings.NewReader(webdavLockBody))
+	lreq.ContentLength = int64(len(webdavLockBody))
+	lreq.GetBody = nil
+	lreq.Header.Set(headerContentType, webdavContentType)
+	lreq.Header.Set(headerDepth, "0")
+	lreq.Header.Set(headerTimeout, webdavDefaultLockTime)
+
+	p.waitCallRate()
+	res, err := client.Do(lreq)
+	if err != nil {
+		log.Debugf("HTTP probe - webdav lock error - %v", err)
+		return ""
+	}
+
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	token := res.Header.Get(headerLockToken)
+	log.Debugf("HTTP probe - webdav lock (status=%d token='%s')", res.StatusCode, token)
+	return token
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPrepareWebDAVRequestDestination(t *testing.T) {
+	tt := []struct {
+		method      string
+		destination string
+		expected    string
+		err         error
+	}{
+		{method: MethodCopy, expected: "http://127.0.0.1:8080/files/report.txt.slim-probe"},
+		{method: MethodCopy, destination: "/files/copy.txt", expected: "/files/copy.txt"},
+		{method: MethodMove, destination: "/files/moved.txt", expected: "/files/moved.txt"},
+		{method: MethodMove, err: ErrWebDAVNoDestination},
+	}
+
+	p := &CustomProbe{}
+	for _, test := range tt {
+		req, err := http.NewRequest(test.method, "http://127.0.0.1:8080/files/report.txt", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if test.destination != "" {
+			req.Header.Set(headerDestination, test.destination)
+		}
+
+		err = p.prepareWebDAVRequest(http.DefaultClient, req)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v expected %v", test.method, err, test.err)
+		}
+
+		if dst := req.Header.Get(headerDestination); dst != test.expected {
+			t.Errorf("%s: got destination '%s' expected '%s'", test.method, dst, test.expected)
+		}
+	}
+}
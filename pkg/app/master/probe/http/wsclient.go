@@ -2,6 +2,8 @@ package http
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,6 +27,11 @@ type WebsocketClient struct {
 	Addr      string
 	pongCh    chan string
 	doneCh    chan struct{}
+
+	//the dialer for the connections (the default dialer if it's nil)
+	Dialer *websocket.Dialer
+	//the handshake request headers
+	Header http.Header
 }
 
 type WebsocketMessage struct {
@@ -42,7 +49,7 @@ func NewWebsocketClient(proto, host, port string) (*WebsocketClient, error) {
 	}
 
 	wsclient := &WebsocketClient{
-		Addr:   fmt.Sprintf("%s://%s:%s", proto, host, port),
+		Addr:   fmt.Sprintf("%s://%s", proto, net.JoinHostPort(host, port)),
 		doneCh: make(chan struct{}),
 		pongCh: make(chan string, 10),
 	}
@@ -60,7 +67,12 @@ func IsValidWSProto(proto string) bool {
 }
 
 func (wc *WebsocketClient) Connect() error {
-	conn, _, err := websocket.DefaultDialer.Dial(wc.Addr, nil)
+	dialer := wc.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, _, err := dialer.Dial(wc.Addr, wc.Header)
 	if err != nil {
 		log.Debugf("WebsocketClient.Connect: ws.Dial error=%v", err)
 		return err
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+)
+
+const wsReplyTimeout = 5 * time.Second
+
+var ErrNoWSReply = errors.New("no websocket reply")
+
+// wsReply waits for the reply to the command message and checks the reply
+// with the command body expectations (without them the reply is optional)
+func (p *CustomProbe) wsReply(wc *WebsocketClient, cmd config.HTTPProbeCmd) error {
+	select {
+	case wsMsg := <-wc.ReadCh:
+		log.Debugf("HTTP probe - websocket read - [type=%v data=%s]", wsMsg.Type, string(wsMsg.Data))
+		if !hasExpectedBody(cmd) {
+			return nil
+		}
+
+		return checkExpectedBody(cmd, responseBody{
+			data:         wsMsg.Data,
+			size:         int64(len(wsMsg.Data)),
+			declaredSize: -1,
+		})
+	case <-time.After(wsReplyTimeout):
+		log.Debugf("HTTP probe - websocket read time out")
+		if !hasExpectedBody(cmd) {
+			return nil
+		}
+
+		return fmt.Errorf("%w (timeout=%s)", ErrNoWSReply, wsReplyTimeout)
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+// wsResource returns the websocket URL path for the probe command resource
+func wsResource(resource string) string {
+	if resource == "" || resource == "/" {
+		return ""
+	}
+
+	if !strings.HasPrefix(resource, "/") {
+		return "/" + resource
+	}
+
+	return resource
+}
+
+// wsDialer creates the websocket dialer for the probe command calls
+// (the probe dialer, proxy and TLS config like for the https calls)
+func (p *CustomProbe) wsDialer(serverName string) *websocket.Dialer {
+	return &websocket.Dialer{
+		NetDialContext:   p.clientOpts.dialer.DialContext,
+		Proxy:            p.clientOpts.proxy,
+		HandshakeTimeout: p.clientOpts.callTimeout,
+		TLSClientConfig:  p.clientOpts.clientTLSConfig(serverName),
+	}
+}
+
+// wsRequestHeader returns the websocket handshake request headers for the probe command
+// (the default and command headers and the basic auth credentials)
+func wsRequestHeader(cmd config.HTTPProbeCmd, defaults []string) http.Header {
+	req, err := newHTTPRequestFromCmd(context.Background(), cmd, "http://localhost/", nil, defaults)
+	if err != nil {
+		log.Debugf("HTTP probe - websocket request header error - %v", err)
+		return nil
+	}
+
+	header := req.Header
+	if req.Host != "" {
+		header.Set(headerHost, req.Host)
+	}
+
+	return header
+}
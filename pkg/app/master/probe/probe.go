@@ -0,0 +1,30 @@
+// Package probe provides the common interface for the target probes
+// (the HTTP, TCP and exec probes in the subpackages).
+package probe
+
+// Probe is a target probe started by the build and profile commands
+type Probe interface {
+	// Start starts the probe execution (the probe runs in the background)
+	Start()
+	// DoneChan returns the channel closed when the probe execution is done
+	DoneChan() <-chan struct{}
+}
+
+// StartAll starts the probes (they run at the same time)
+// and returns the channel closed when all probes are done
+func StartAll(probes ...Probe) <-chan struct{} {
+	for _, p := range probes {
+		p.Start()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for _, p := range probes {
+			<-p.DoneChan()
+		}
+
+		close(done)
+	}()
+
+	return done
+}
@@ -0,0 +1,230 @@
+package tcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	dockerapi "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slimtoolkit/slim/pkg/app"
+	"github.com/slimtoolkit/slim/pkg/app/master/config"
+	"github.com/slimtoolkit/slim/pkg/app/master/inspectors/container"
+	"github.com/slimtoolkit/slim/pkg/app/master/probe"
+)
+
+const (
+	probeRetryCount = 5
+	probeRetryWait  = 2 * time.Second
+
+	defaultConnectTimeout = 5 * time.Second
+	defaultReadTimeout    = 5 * time.Second
+)
+
+var ErrUnexpectedResponse = errors.New("unexpected response")
+
+type ovars = app.OutVars
+
+// TCPProbe is a probe for the target ports that don't serve HTTP
+// (e.g., databases, message brokers or custom binary protocols)
+type TCPProbe struct {
+	xc *app.ExecutionContext
+
+	opts config.TCPProbeOptions
+
+	targetHost string
+	targets    []probeTarget
+
+	printState bool
+
+	CallCount uint64
+	ErrCount  uint64
+	OkCount   uint64
+
+	doneChan chan struct{}
+}
+
+var _ probe.Probe = (*TCPProbe)(nil)
+
+type probeTarget struct {
+	spec config.TCPProbeSpec
+	//the port to connect (the host port or the container port)
+	port string
+}
+
+// NewContainerProbe creates a new TCP probe for the target container ports
+func NewContainerProbe(
+	xc *app.ExecutionContext,
+	inspector *container.Inspector,
+	opts config.TCPProbeOptions,
+	printState bool,
+) (*TCPProbe, error) {
+	probe := &TCPProbe{
+		xc:         xc,
+		opts:       opts,
+		targetHost: inspector.TargetHost,
+		printState: printState,
+		doneChan:   make(chan struct{}),
+	}
+
+	for _, spec := range opts.Specs {
+		pspec := dockerapi.Port(fmt.Sprintf("%v/tcp", spec.Port))
+		portInfo, ok := inspector.AvailablePorts[pspec]
+		if !ok {
+			log.Debugf("TCP probe - ignoring port => %v", pspec)
+			continue
+		}
+
+		port := portInfo.HostPort
+		if inspector.SensorIPCMode == container.SensorIPCModeDirect {
+			port = strconv.Itoa(int(spec.Port))
+		}
+
+		probe.targets = append(probe.targets, probeTarget{spec: spec, port: port})
+	}
+
+	log.Debugf("TCP probe - targets => %+v", probe.targets)
+	return probe, nil
+}
+
+// Ports returns the list of the probed ports
+func (p *TCPProbe) Ports() []string {
+	var ports []string
+	for _, target := range p.targets {
+		ports = append(ports, target.port)
+	}
+
+	return ports
+}
+
+// Start starts the TCP probe instance execution
+func (p *TCPProbe) Start() {
+	if p.printState {
+		p.xc.Out.State("tcp.probe.starting",
+			ovars{
+				"message": "WAIT FOR TCP PROBE TO FINISH",
+			})
+
+		p.xc.Out.Info("tcp.probe.ports",
+			ovars{
+				"count":   len(p.targets),
+				"targets": strings.Join(p.Ports(), ","),
+			})
+	}
+
+	go func() {
+		log.Info("TCP probe started...")
+
+		for _, target := range p.targets {
+			p.probeTarget(target)
+		}
+
+		log.Info("TCP probe done.")
+
+		if p.printState {
+			p.xc.Out.Info("tcp.probe.summary",
+				ovars{
+					"total":      atomic.LoadUint64(&p.CallCount),
+					"failures":   atomic.LoadUint64(&p.ErrCount),
+					"successful": atomic.LoadUint64(&p.OkCount),
+				})
+
+			outVars := ovars{}
+			if atomic.LoadUint64(&p.OkCount) == 0 {
+				outVars["warning"] = "no.successful.calls"
+			}
+
+			p.xc.Out.State("tcp.probe.done", outVars)
+		}
+
+		close(p.doneChan)
+	}()
+}
+
+// probeTarget connects to the target port until the probe call is successful
+// (the target app might not be ready to accept the connections yet)
+func (p *TCPProbe) probeTarget(target probeTarget) {
+	addr := net.JoinHostPort(p.targetHost, target.port)
+	for i := 0; i < probeRetryCount; i++ {
+		err := p.call(addr, target.spec)
+		atomic.AddUint64(&p.CallCount, 1)
+
+		if p.printState {
+			errInfo := "none"
+			if err != nil {
+				errInfo = err.Error()
+			}
+
+			p.xc.Out.Info("tcp.probe.call",
+				ovars{
+					"port":    target.spec.Port,
+					"target":  addr,
+					"attempt": i + 1,
+					"error":   errInfo,
+				})
+		}
+
+		if err == nil {
+			atomic.AddUint64(&p.OkCount, 1)
+			return
+		}
+
+		atomic.AddUint64(&p.ErrCount, 1)
+		log.Debugf("TCP probe - call error (%s) - %v", addr, err)
+
+		if i < probeRetryCount-1 {
+			time.Sleep(probeRetryWait)
+		}
+	}
+}
+
+// call connects to the target address, sends the spec data
+// and checks the expected response prefix (if the spec has them)
+func (p *TCPProbe) call(addr string, spec config.TCPProbeSpec) error {
+	conn, err := net.DialTimeout("tcp", addr, defaultConnectTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if spec.Send == "" && spec.Expect == "" {
+		return nil
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(defaultReadTimeout)); err != nil {
+		return err
+	}
+
+	if spec.Send != "" {
+		if _, err := io.WriteString(conn, spec.Send); err != nil {
+			return err
+		}
+	}
+
+	if spec.Expect == "" {
+		return nil
+	}
+
+	data := make([]byte, len(spec.Expect))
+	n, err := io.ReadFull(conn, data)
+	if err != nil && n == 0 {
+		return err
+	}
+
+	if string(data[:n]) != spec.Expect {
+		return fmt.Errorf("%w (%q)", ErrUnexpectedResponse, data[:n])
+	}
+
+	return nil
+}
+
+// DoneChan returns the 'done' channel for the TCP probe instance
+func (p *TCPProbe) DoneChan() <-chan struct{} {
+	return p.doneChan
+}
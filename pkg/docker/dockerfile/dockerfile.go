@@ -59,6 +59,7 @@ func GenerateFromInfo(
 	exposedPorts map[docker.Port]struct{},
 	entrypoint []string,
 	cmd []string,
+	healthcheck *docker.HealthConfig,
 	hasData bool,
 	tarData bool) error {
 
@@ -134,6 +135,11 @@ func GenerateFromInfo(
 		}
 	}
 
+	if healthcheck != nil && len(healthcheck.Test) > 0 {
+		dfData.WriteString(healthcheckInstruction(healthcheck))
+		dfData.WriteByte('\n')
+	}
+
 	if len(entrypoint) > 0 {
 		//TODO: need to make sure the generated ENTRYPOINT is compatible with the original behavior
 		var quotedEntryPoint []string
@@ -161,3 +167,45 @@ func GenerateFromInfo(
 
 	return os.WriteFile(dockerfileLocation, dfData.Bytes(), 0644)
 }
+
+// healthcheckInstruction creates a HEALTHCHECK instruction from the image healthcheck config
+// (the default flag values are omitted)
+func healthcheckInstruction(config *docker.HealthConfig) string {
+	if config.Test[0] == "NONE" {
+		return "HEALTHCHECK NONE"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(instTypeHealthcheck)
+	if config.Interval > 0 {
+		fmt.Fprintf(&sb, " --interval=%v", config.Interval)
+	}
+
+	if config.Timeout > 0 {
+		fmt.Fprintf(&sb, " --timeout=%v", config.Timeout)
+	}
+
+	if config.StartPeriod > 0 {
+		fmt.Fprintf(&sb, " --start-period=%v", config.StartPeriod)
+	}
+
+	if config.Retries > 0 {
+		fmt.Fprintf(&sb, " --retries=%d", config.Retries)
+	}
+
+	sb.WriteString(" CMD ")
+	if config.Test[0] == "CMD-SHELL" {
+		sb.WriteString(strings.Join(config.Test[1:], " "))
+		return sb.String()
+	}
+
+	var quotedTest []string
+	for _, arg := range config.Test[1:] {
+		quotedTest = append(quotedTest, strconv.Quote(arg))
+	}
+
+	sb.WriteString("[")
+	sb.WriteString(strings.Join(quotedTest, ","))
+	sb.WriteString("]")
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package dockerfile
+
+import (
+	"testing"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthcheckInstruction(t *testing.T) {
+	tt := []struct {
+		name     string
+		config   docker.HealthConfig
+		expected string
+	}{
+		{
+			name:     "none",
+			config:   docker.HealthConfig{Test: []string{"NONE"}, Interval: 10 * time.Second},
+			expected: "HEALTHCHECK NONE",
+		},
+		{
+			name:     "exec form",
+			config:   docker.HealthConfig{Test: []string{"CMD", "curl", "-f", "http://localhost/"}},
+			expected: `HEALTHCHECK CMD ["curl","-f","http://localhost/"]`,
+		},
+		{
+			name:     "exec form with quotes",
+			config:   docker.HealthConfig{Test: []string{"CMD", "sh", "-c", `echo "ok"`}},
+			expected: `HEALTHCHECK CMD ["sh","-c","echo \"ok\""]`,
+		},
+		{
+			name:     "shell form",
+			config:   docker.HealthConfig{Test: []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}},
+			expected: "HEALTHCHECK CMD curl -f http://localhost/ || exit 1",
+		},
+		{
+			name: "all flags",
+			config: docker.HealthConfig{
+				Test:        []string{"CMD", "/healthcheck"},
+				Interval:    30 * time.Second,
+				Timeout:     5 * time.Second,
+				StartPeriod: time.Minute,
+				Retries:     3,
+			},
+			expected: `HEALTHCHECK --interval=30s --timeout=5s --start-period=1m0s --retries=3 CMD ["/healthcheck"]`,
+		},
+		{
+			name: "some flags",
+			config: docker.HealthConfig{
+				Test:    []string{"CMD-SHELL", "pg_isready"},
+				Timeout: 1500 * time.Millisecond,
+				Retries: 5,
+			},
+			expected: "HEALTHCHECK --timeout=1.5s --retries=5 CMD pg_isready",
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, healthcheckInstruction(&test.config))
+		})
+	}
+}